@@ -20,7 +20,7 @@ import (
 
 	"google.golang.org/grpc"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 )
 
 func main() {
@@ -13,22 +13,203 @@ limitations under the License.
 
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/model"
+)
 
 type Config struct {
 	ClientAddr           string
 	GcInterval           time.Duration
 	IdleDurationBeforeGC time.Duration
 	RctRate              float64
+	// 按 meta key 覆盖的 RctRate
+	RctRateOverrides map[string]float64
+	// 根据请求耗时的波动自动调整请求耗时 EWMA 的衰减系数，波动越大衰减越快
+	AdaptiveRctRate bool
+	// BackfillIdle 需要补齐到的空闲实例数，gc 也不会把空闲实例回收到该数量以下
+	MinIdleInstances int
+	// 空闲实例数上限，空闲队列已满时新空闲的实例直接回收，0 表示不限制
+	MaxIdleInstances int
+	// 实例总数上限，0 表示不限制
+	MaxInstances int
+	// 同时处于创建中的实例数上限，0 表示不限制
+	MaxConcurrentCreations int
+	// sticky key 与实例绑定关系的有效期，0 表示不过期
+	StickyKeyTTL time.Duration
+	// 把空闲实例通知给等待请求的超时时间，超时后实例放回空闲队列
+	IdleNotifyTimeout time.Duration
+	// 实例被回收时的回调，在回收的 goroutine 中同步调用，nil 表示不通知
+	EvictionCallback func(instance *model.Instance, reason string)
+	// 单个 scaler 可分配的内存总量，0 表示不限制
+	ResourceBudgetMb int64
+	// 单个实例可同时处理的请求数，0 表示使用平台返回的默认值
+	InstanceCapacity int
+	// 首个请求到来时等待平台就绪的最长时间，0 表示不探测
+	StartupTimeout time.Duration
+	// 计算期望实例数时的安全系数
+	ScalingSafetyFactor float64
+	// 长轮询 channel 的缓冲大小，小于等于 0 时按 1 处理
+	// 大于 1 时实例可能先投递给已经取消的请求，请求发现后再放回空闲队列
+	LongPollingChanSize int
+	// Close 的默认超时时间，调用方传入的 ctx 已有 deadline 时以 ctx 为准
+	ShutdownTimeout time.Duration
+	// 平台 CreateSlot+Init 的预期耗时，平均耗时超过 3 倍时 CheckLive 返回 false，0 表示不检查
+	ExpectedPlatformCallDuration time.Duration
+	// 单次 CreateSlot 调用的超时时间
+	SlotCreateTimeout time.Duration
+	// 单次 Init 调用的超时时间，CreateSlot 或 Init 超时后等待的请求收到 DeadlineExceeded
+	SlotInitTimeout time.Duration
+	// gc 和后台回收时单次销毁 slot 的超时时间，scaler 关闭时提前取消
+	SlotDeleteTimeout time.Duration
+	// 每个 ClientId 每秒允许的 Assign 次数，不在表中的客户端不限流
+	ClientRateLimits map[string]float64
+	// 按 meta key 覆盖的配置
+	MetaOverrides map[string]MetaConfig
+	// 回收后重置复用的 slot 数量上限，0 表示不复用，平台需要实现 SlotResetter
+	SlotReusePoolSize int
+	// Init 失败率超过该值时输出告警日志，0 表示不告警
+	InitErrorRateThreshold float64
+	// 空闲队列按 InitDurationInMs 从小到大插入，队首是初始化最快的实例
+	// 插入需要 O(n) 扫描；gc 从队尾开始回收，开启后队尾不再是空闲最久的实例，部分实例的回收会延后
+	SortIdleByInitDuration bool
+	// Assign 耗时超过该值时输出告警日志并调用 OnSlowAssign，0 表示不检查
+	AssignSlowPathThreshold time.Duration
+	// 慢 Assign 回调，在 Assign 返回前同步调用，nil 表示不通知
+	OnSlowAssign func(requestId string, latency time.Duration)
+	// DrainIdle 缩容时的回收顺序，gc 始终按空闲时间回收
+	EvictPolicy EvictPolicy
+	// gc 扫描整个空闲队列回收过期实例，并按 Instance.Score 从高到低回收
+	EvictByScore bool
+	// slot 创建和删除的审计日志路径，空表示不记录
+	AuditLogPath string
+	// 审计日志超过该大小时轮转，0 表示不轮转
+	// 多个 scaler 共用同一路径时使用第一个打开该路径的 scaler 的配置
+	AuditLogMaxSizeMb int64
+	// 同一个应用拆分成的 Simple 分片数，大于 1 时 Assign 按 request id 分散到各分片
+	ShardCount int
+	// 有请求等待超过该时间、且期间没有成功的 Assign 或 Idle 调用时 CheckLive 返回 false，没有流量时不受影响，0 表示不检查
+	LivenessTimeout time.Duration
+	// 创建后立即预热并保持的空闲实例数，gc 不会把空闲实例回收到该数量以下，0 表示不预热
+	WarmPoolSize int
+	// CreateSlot 或 Init 失败后的重试次数，重试间隔从 100ms 开始指数增长，最长 5s，0 表示不重试
+	MaxCreateRetries int
+	// 分配空闲实例的顺序，空表示 IdleQueueLIFO，sticky key 和 routing key 仍然优先
+	// LIFO 总是分配最近空闲的实例，内存中的缓存更可能仍然有效；FIFO 分配空闲最久的实例，各实例的使用更均匀
+	IdleQueueOrder IdleQueueOrder
+	// 已分配但尚未 Idle 的请求数上限，达到上限时 Assign 直接返回 ResourceExhausted，0 表示不限制
+	MaxConcurrentRequests int64
+	// 用于计算 Assign 耗时分位数的样本数，小于等于 0 时为 1000
+	AssignLatencySampleSize int
+	// 连续创建实例失败达到该次数后熔断，熔断期间直接返回 Unavailable，0 表示不熔断
+	CircuitBreakerThreshold int
+	// 熔断后经过该时间放行一次探测创建
+	CircuitBreakerRecovery time.Duration
+	// 距离上一次 Assign 返回超过该时间后，新的请求到来时清空请求耗时等统计，0 表示不清空
+	StalenessDuration time.Duration
+	// 请求要求销毁实例时，平台实现 InstanceResetter 的情况下原地重置实例的最多次数，0 表示总是销毁
+	InstanceMaxRecycleCount int
+	// 进程内每秒允许的 CreateSlot 调用数，配置相同的 scaler 共享限额，超出时等待，0 表示不限制
+	SlotCreateRPS float64
+	// CreateSlot 限流允许的突发数，小于等于 0 时取 ceil(SlotCreateRPS)
+	SlotCreateBurst int
+	// 检查点文件所在目录，非空时 New 从已有的检查点恢复实例，Close 时写入检查点
+	CheckpointDir string
+	// 单次 gc 回收的实例数超过该值时 gc 间隔减半（最短 1 秒），连续多次没有回收时加倍（最长 GcInterval），0 表示固定间隔
+	GcChurnThreshold int
+	// 等待实例的请求数上限，达到上限时 Assign 直接返回 ResourceExhausted，0 表示不限制
+	MaxQueueDepth int
+	// 统计 Assign 请求速率的滑动窗口，按秒取整
+	RequestRateWindow time.Duration
+	// Assign 成功后没有空闲和创建中的实例时，提前创建一个实例留给下一个请求，受 MaxIdleInstances 限制
+	HotStandby bool
+	// 请求在等待队列中的最长时间，与 ctx 的 deadline 和请求的 TimeoutMs 相互独立，0 表示不限制
+	LongPollingTimeout time.Duration
+	// 空闲实例的内存总量上限，gc 超出时不论空闲时间先回收内存最大的空闲实例，0 表示不限制
+	MaxIdleMemoryMb int64
+	// 实例绑定 affinity key 的有效期，过期后 gc 清除绑定，0 表示不过期
+	AffinityTimeout time.Duration
+	// 实例就绪或空闲时按 deadline 顺序找第一个 meta key 相同、内存不小于请求内存的等待请求，而不是总是通知队首，
+	// 避免队首请求不匹配时阻塞后面的请求，关闭时总是通知队首
+	FairQueueing bool
+	// 预先创建但不初始化的 slot 数，请求到来时只需要 Init，CreateSlot 与直接创建共享限流，0 表示不预先创建
+	// 只有内存与应用 meta 相同的请求使用这些 slot
+	PreallocatedSlots int
+}
+
+// RctRateFor 返回 metaKey 使用的 RctRate，没有覆盖时使用 RctRate
+func (c *Config) RctRateFor(metaKey string) float64 {
+	if rate, ok := c.RctRateOverrides[metaKey]; ok {
+		return rate
+	}
+	return c.RctRate
 }
 
+// MetaConfig 单个应用的配置覆盖项，零值表示沿用全局配置
+type MetaConfig struct {
+	InitTimeoutMs          int64
+	IdleDurationBeforeGCMs int64
+	MaxInstances           int
+}
+
+// Merge 把覆盖项应用到 base 的副本上并返回
+func (m MetaConfig) Merge(base Config) Config {
+	if m.InitTimeoutMs > 0 {
+		base.SlotInitTimeout = time.Duration(m.InitTimeoutMs) * time.Millisecond
+	}
+	if m.IdleDurationBeforeGCMs > 0 {
+		base.IdleDurationBeforeGC = time.Duration(m.IdleDurationBeforeGCMs) * time.Millisecond
+	}
+	if m.MaxInstances > 0 {
+		base.MaxInstances = m.MaxInstances
+	}
+	return base
+}
+
+// EvictPolicy 缩容时选择回收实例的顺序
+type EvictPolicy int
+
+const (
+	// 先回收空闲最久的实例
+	EvictPolicyIdleTime EvictPolicy = iota
+	// 先回收 InitDurationInMs 最大的实例，保留初始化快的实例
+	EvictPolicyPreserveFastInit
+)
+
+// IdleQueueOrder 空闲队列的分配顺序
+type IdleQueueOrder string
+
+const (
+	IdleQueueLIFO IdleQueueOrder = "lifo"
+	// 从空闲队列队尾分配，开启 SortIdleByInitDuration 时队尾是初始化最慢的实例
+	IdleQueueFIFO IdleQueueOrder = "fifo"
+	// 加权最少连接，选择正在处理的请求数除以实例内存最小的实例，适合内存规格不同的实例混合使用
+	IdleQueueWeightedLeastConn IdleQueueOrder = "wlc"
+)
+
 var DefaultConfig *Config
 
 func init() {
 	DefaultConfig = &Config{
-		ClientAddr:           "127.0.0.1:50051",
-		GcInterval:           1 * time.Second,
-		IdleDurationBeforeGC: 5 * time.Minute,
-		RctRate:              0.9,
+		ClientAddr:             "127.0.0.1:50051",
+		GcInterval:             1 * time.Second,
+		IdleDurationBeforeGC:   5 * time.Minute,
+		RctRate:                0.9,
+		StickyKeyTTL:           1 * time.Minute,
+		IdleNotifyTimeout:      5 * time.Second,
+		StartupTimeout:         10 * time.Second,
+		ScalingSafetyFactor:    1.2,
+		LongPollingChanSize:    1,
+		ShutdownTimeout:        30 * time.Second,
+		SlotCreateTimeout:      60 * time.Second,
+		SlotDeleteTimeout:      30 * time.Second,
+		SlotInitTimeout:        30 * time.Second,
+		LivenessTimeout:        45 * time.Second,
+		MaxCreateRetries:       3,
+		CircuitBreakerRecovery: 10 * time.Second,
+		StalenessDuration:      5 * time.Minute,
+		RequestRateWindow:      60 * time.Second,
+		AffinityTimeout:        10 * time.Minute,
 	}
 }
@@ -14,15 +14,30 @@ limitations under the License.
 package model
 
 import (
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 )
 
 type Meta struct {
 	pb.Meta
 }
 
+// NewMeta 复制 proto 中的 meta 字段，pb.Meta 含有锁不能直接按值复制
+func NewMeta(meta *pb.Meta) *Meta {
+	return &Meta{
+		Meta: pb.Meta{
+			Key:           meta.Key,
+			Runtime:       meta.Runtime,
+			TimeoutInSecs: meta.TimeoutInSecs,
+			MemoryInMb:    meta.MemoryInMb,
+		},
+	}
+}
+
 type Instance struct {
 	Id               string
 	Slot             *Slot
@@ -31,4 +46,116 @@ type Instance struct {
 	InitDurationInMs int64
 	Busy             bool
 	LastIdleTime     time.Time
+	// 最近一次分配给的 meta key
+	LastMetaKey string
+	// 等待本次请求结束后回收
+	PendingEviction bool
+	// 最近一次请求的执行统计
+	LastExecutionStats *ExecutionStats
+	// 实例可同时处理的请求数，小于等于 0 时按 1 处理
+	InstanceCapacity int
+	// 正在处理的请求数，原子读写
+	UsedSlots int32
+	// 累计分配的请求数，原子读写
+	UsageCount int64
+	// 实例所在的网络等级，来自 CreateSlot 返回的 slot
+	NetworkTier string
+	// 请求要求销毁时是否尝试原地重置实例，以及最多重置的次数
+	AllowRecycle    bool
+	MaxRecycleCount int
+	// 已经重置的次数
+	RecycleCount int
+	// 加权最少连接分配时的权重，与实例的内存成正比
+	Weight float64
+	// 最近一次分配给的请求的 affinity key 及分配时间，实例回到空闲队列后保留
+	AffinityKey string
+	AffinityAt  time.Time
+	// 实例初始化时使用的标签，只能分配给 RequiredTags 都满足的请求
+	Tags map[string]string
+	// 平台附加的自定义数据，通过 SetCustomData/GetCustomData 读写
+	CustomData map[string]interface{}
+	customMu   sync.RWMutex
+}
+
+// IsBusy 实例上是否有正在处理的请求
+func (i *Instance) IsBusy() bool {
+	return atomic.LoadInt32(&i.UsedSlots) > 0
+}
+
+// HasFreeSlot 实例是否还能接收新的请求
+func (i *Instance) HasFreeSlot() bool {
+	capacity := i.InstanceCapacity
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return int(atomic.LoadInt32(&i.UsedSlots)) < capacity
+}
+
+// AcquireSlot 占用一个并发槽位
+func (i *Instance) AcquireSlot() {
+	atomic.AddInt32(&i.UsedSlots, 1)
+	atomic.AddInt64(&i.UsageCount, 1)
+	i.Busy = true
+}
+
+// ReleaseSlot 释放一个并发槽位，槽位全部释放后实例变为空闲
+func (i *Instance) ReleaseSlot() {
+	if atomic.AddInt32(&i.UsedSlots, -1) <= 0 {
+		atomic.StoreInt32(&i.UsedSlots, 0)
+		i.Busy = false
+		i.LastIdleTime = time.Now()
+	}
+}
+
+// Score 回收优先级，越高越应该先回收
+// 由三部分相加：空闲时间占 idleDurationBeforeGC 的比例（不超过 1）、1/(UsageCount+1)、InitDurationInMs 占 maxObservedInitMs 的比例
+func (i *Instance) Score(idleDurationBeforeGC time.Duration, maxObservedInitMs int64) float64 {
+	var score float64
+	if idleDurationBeforeGC > 0 {
+		score += math.Min(float64(time.Since(i.LastIdleTime))/float64(idleDurationBeforeGC), 1)
+	}
+	score += 1 / float64(atomic.LoadInt64(&i.UsageCount)+1)
+	if maxObservedInitMs > 0 {
+		score += float64(i.InitDurationInMs) / float64(maxObservedInitMs)
+	}
+	return score
+}
+
+// SetCustomData 设置自定义数据
+func (i *Instance) SetCustomData(key string, value interface{}) {
+	i.customMu.Lock()
+	defer i.customMu.Unlock()
+	if i.CustomData == nil {
+		i.CustomData = make(map[string]interface{})
+	}
+	i.CustomData[key] = value
+}
+
+// GetCustomData 读取自定义数据
+func (i *Instance) GetCustomData(key string) (interface{}, bool) {
+	i.customMu.RLock()
+	defer i.customMu.RUnlock()
+	value, ok := i.CustomData[key]
+	return value, ok
+}
+
+// CustomDataSnapshot 返回自定义数据的浅拷贝
+func (i *Instance) CustomDataSnapshot() map[string]interface{} {
+	i.customMu.RLock()
+	defer i.customMu.RUnlock()
+	if len(i.CustomData) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]interface{}, len(i.CustomData))
+	for key, value := range i.CustomData {
+		snapshot[key] = value
+	}
+	return snapshot
+}
+
+type ExecutionStats struct {
+	CpuMs           int64
+	MemPeakMb       int64
+	NetworkBytesIn  int64
+	NetworkBytesOut int64
 }
@@ -13,7 +13,7 @@ limitations under the License.
 
 package model
 
-import pb "github.com/AliyunContainerService/scaler/proto"
+import pb "github.com/AliyunContainerService/scaler/go/proto"
 
 type Slot struct {
 	pb.Slot
@@ -22,13 +22,15 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 )
 
 type PlatformClient struct {
 	clientConn io.Closer
+	conn       *grpc.ClientConn
 	c          pb.PlatformClient
 	addr       string
 }
@@ -41,6 +43,7 @@ func New(addr string) (Client, error) {
 	}
 	return &PlatformClient{
 		clientConn: conn,
+		conn:       conn,
 		c:          pb.NewPlatformClient(conn),
 		addr:       addr,
 	}, nil
@@ -59,14 +62,40 @@ func (client *PlatformClient) CreateSlot(ctx context.Context, requestId string,
 		return nil, fmt.Errorf("create slot failed with code: %d, message: %s", reply.Status, *reply.ErrorMessage)
 	}
 
+	return newSlot(reply.Slot), nil
+}
+
+func newSlot(slot *pb.Slot) *model2.Slot {
 	return &model2.Slot{
 		Slot: pb.Slot{
-			Id:                 reply.Slot.Id,
-			ResourceConfig:     reply.Slot.ResourceConfig,
-			CreateTime:         reply.Slot.CreateTime,
-			CreateDurationInMs: reply.Slot.CreateDurationInMs,
+			Id:                 slot.Id,
+			ResourceConfig:     slot.ResourceConfig,
+			CreateTime:         slot.CreateTime,
+			CreateDurationInMs: slot.CreateDurationInMs,
+			NetworkTier:        slot.NetworkTier,
 		},
-	}, nil
+	}
+}
+
+// CreateSlotGroup 通过一次调用创建 count 个 slot，平台返回的 slot 可能少于 count
+func (client *PlatformClient) CreateSlotGroup(ctx context.Context, requestId string, count int, slotResourceConfig *model2.SlotResourceConfig) ([]*model2.Slot, error) {
+	req := &pb.CreateSlotGroupRequest{
+		RequestId:      requestId,
+		ResourceConfig: &slotResourceConfig.ResourceConfig,
+		Count:          uint32(count),
+	}
+	reply, err := client.c.CreateSlotGroup(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Status != pb.Status_Ok {
+		return nil, fmt.Errorf("create slot group failed with code: %d, message: %s", reply.Status, reply.GetErrorMessage())
+	}
+	slots := make([]*model2.Slot, 0, len(reply.Slots))
+	for _, slot := range reply.Slots {
+		slots = append(slots, newSlot(slot))
+	}
+	return slots, nil
 }
 
 func (client *PlatformClient) DestroySLot(ctx context.Context, requestId, slotId, reason string) error {
@@ -85,12 +114,49 @@ func (client *PlatformClient) DestroySLot(ctx context.Context, requestId, slotId
 	return nil
 }
 
+// ResetSlot 清理 slot 上的应用状态，重置后的 slot 可以再次初始化实例
+func (client *PlatformClient) ResetSlot(ctx context.Context, requestId, slotId string) error {
+	req := &pb.ResetSlotRequest{
+		RequestId: requestId,
+		SlotId:    slotId,
+	}
+	reply, err := client.c.ResetSlot(ctx, req)
+	if err != nil {
+		return err
+	}
+	if reply.Status != pb.Status_Ok {
+		return fmt.Errorf("reset slot failed with code: %d, msg: %s", reply.Status, reply.GetErrorMessage())
+	}
+	return nil
+}
+
+// ResetInstance 在 slot 上重新初始化实例，用于回收用户代码出错但沙箱仍然可用的实例
+func (client *PlatformClient) ResetInstance(ctx context.Context, requestId, slotId string) error {
+	req := &pb.ResetInstanceRequest{
+		RequestId: requestId,
+		SlotId:    slotId,
+	}
+	reply, err := client.c.ResetInstance(ctx, req)
+	if err != nil {
+		return err
+	}
+	if reply.Status != pb.Status_Ok {
+		return fmt.Errorf("reset instance failed with code: %d, msg: %s", reply.Status, reply.GetErrorMessage())
+	}
+	return nil
+}
+
 func (client *PlatformClient) Init(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta) (*model2.Instance, error) {
+	return client.InitWithTags(ctx, requestId, instanceId, slot, meta, nil)
+}
+
+func (client *PlatformClient) InitWithTags(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta, tags map[string]string) (*model2.Instance, error) {
 	req := &pb.InitRequest{
 		RequestId:  requestId,
 		InstanceId: instanceId,
 		SlotId:     slot.Id,
 		MetaData:   &meta.Meta,
+		Tags:       tags,
 	}
 	reply, err := client.c.Init(ctx, req)
 	if err != nil {
@@ -99,15 +165,32 @@ func (client *PlatformClient) Init(ctx context.Context, requestId, instanceId st
 	if reply.Status != pb.Status_Ok {
 		return nil, fmt.Errorf("init app failed with code: %d, message: %s", reply.Status, *reply.ErrorMessage)
 	}
-	return &model2.Instance{
-		Id:               instanceId,
-		Slot:             slot,
-		Meta:             meta,
-		CreateTimeInMs:   int64(reply.CreateTime),
-		InitDurationInMs: int64(reply.InitDurationInMs),
-		Busy:             false,
-		LastIdleTime:     time.Now(),
-	}, nil
+	instance := &model2.Instance{}
+	instance.Id = instanceId
+	instance.Slot = slot
+	instance.Meta = meta
+	instance.CreateTimeInMs = int64(reply.CreateTime)
+	instance.InitDurationInMs = int64(reply.InitDurationInMs)
+	instance.Busy = false
+	instance.LastIdleTime = time.Now()
+	instance.InstanceCapacity = 1
+	instance.NetworkTier = slot.NetworkTier
+	instance.Tags = tags
+	return instance, nil
+}
+
+// Ping 等待与平台的连接进入 Ready 状态
+func (client *PlatformClient) Ping(ctx context.Context) error {
+	client.conn.Connect()
+	for {
+		state := client.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !client.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("platform %s not ready, state: %s: %w", client.addr, state, ctx.Err())
+		}
+	}
 }
 
 func (client *PlatformClient) Close() error {
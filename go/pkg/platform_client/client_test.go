@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform_client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePlatformServer 只实现测试用到的 rpc，其余返回 Unimplemented
+type fakePlatformServer struct {
+	pb.UnimplementedPlatformServer
+	mu             sync.Mutex
+	groupRequests  []*pb.CreateSlotGroupRequest
+	resetSlots     []string
+	resetInstances []string
+}
+
+func (s *fakePlatformServer) CreateSlotGroup(ctx context.Context, req *pb.CreateSlotGroupRequest) (*pb.CreateSlotGroupReply, error) {
+	s.mu.Lock()
+	s.groupRequests = append(s.groupRequests, req)
+	s.mu.Unlock()
+	if req.Count == 0 {
+		message := "count must be positive"
+		return &pb.CreateSlotGroupReply{Status: pb.Status_InternalError, ErrorMessage: &message}, nil
+	}
+	reply := &pb.CreateSlotGroupReply{Status: pb.Status_Ok}
+	for i := 0; i < int(req.Count); i++ {
+		reply.Slots = append(reply.Slots, &pb.Slot{
+			Id:             fmt.Sprintf("%s-%d", req.RequestId, i),
+			ResourceConfig: req.ResourceConfig,
+			NetworkTier:    "fast",
+		})
+	}
+	return reply, nil
+}
+
+func (s *fakePlatformServer) ResetSlot(ctx context.Context, req *pb.ResetSlotRequest) (*pb.ResetSlotReply, error) {
+	s.mu.Lock()
+	s.resetSlots = append(s.resetSlots, req.SlotId)
+	s.mu.Unlock()
+	if req.SlotId == "missing" {
+		message := "slot not found"
+		return &pb.ResetSlotReply{Status: pb.Status_NotFound, ErrorMessage: &message}, nil
+	}
+	return &pb.ResetSlotReply{Status: pb.Status_Ok}, nil
+}
+
+func (s *fakePlatformServer) ResetInstance(ctx context.Context, req *pb.ResetInstanceRequest) (*pb.ResetInstanceReply, error) {
+	s.mu.Lock()
+	s.resetInstances = append(s.resetInstances, req.SlotId)
+	s.mu.Unlock()
+	if req.SlotId == "missing" {
+		message := "slot not found"
+		return &pb.ResetInstanceReply{Status: pb.Status_NotFound, ErrorMessage: &message}, nil
+	}
+	return &pb.ResetInstanceReply{Status: pb.Status_Ok}, nil
+}
+
+// 在本地端口启动 server，返回连接到它的 PlatformClient
+func newTestClient(t *testing.T, server pb.PlatformServer) *PlatformClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterPlatformServer(grpcServer, server)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+	client, err := New(lis.Addr().String())
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client.(*PlatformClient)
+}
+
+func testResourceConfig() *model2.SlotResourceConfig {
+	return &model2.SlotResourceConfig{ResourceConfig: pb.ResourceConfig{MemoryInMegabytes: 128}}
+}
+
+func TestCreateSlotGroup(t *testing.T) {
+	server := &fakePlatformServer{}
+	client := newTestClient(t, server)
+
+	slots, err := client.CreateSlotGroup(context.Background(), "req", 3, testResourceConfig())
+	if err != nil {
+		t.Fatalf("create slot group: %v", err)
+	}
+	if len(slots) != 3 {
+		t.Fatalf("got %d slots, want 3", len(slots))
+	}
+	for i, slot := range slots {
+		if slot.Id != fmt.Sprintf("req-%d", i) || slot.NetworkTier != "fast" || slot.ResourceConfig.MemoryInMegabytes != 128 {
+			t.Fatalf("unexpected slot %d: %+v", i, slot)
+		}
+	}
+	server.mu.Lock()
+	requests := server.groupRequests
+	server.mu.Unlock()
+	if len(requests) != 1 || requests[0].Count != 3 {
+		t.Fatalf("expected one rpc for 3 slots, got %v", requests)
+	}
+
+	if _, err := client.CreateSlotGroup(context.Background(), "bad", 0, testResourceConfig()); err == nil {
+		t.Fatalf("non-ok status should return an error")
+	}
+}
+
+// 平台没有实现该 rpc 时返回 Unimplemented，scaler 据此退回逐个创建
+func TestCreateSlotGroupUnimplemented(t *testing.T) {
+	client := newTestClient(t, &pb.UnimplementedPlatformServer{})
+	_, err := client.CreateSlotGroup(context.Background(), "req", 3, testResourceConfig())
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("got %v, want Unimplemented", err)
+	}
+}
+
+func TestResetSlot(t *testing.T) {
+	server := &fakePlatformServer{}
+	client := newTestClient(t, server)
+	// scaler 通过类型断言检测平台是否支持重置 slot
+	var resetter SlotResetter = client
+	if err := resetter.ResetSlot(context.Background(), "req", "slot-1"); err != nil {
+		t.Fatalf("reset slot: %v", err)
+	}
+	if err := resetter.ResetSlot(context.Background(), "req", "missing"); err == nil {
+		t.Fatalf("non-ok status should return an error")
+	}
+	server.mu.Lock()
+	slots := append([]string(nil), server.resetSlots...)
+	server.mu.Unlock()
+	if len(slots) != 2 || slots[0] != "slot-1" {
+		t.Fatalf("unexpected reset requests %v", slots)
+	}
+
+	unimplemented := newTestClient(t, &pb.UnimplementedPlatformServer{})
+	if err := unimplemented.ResetSlot(context.Background(), "req", "slot-1"); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("got %v, want Unimplemented", err)
+	}
+}
+
+func TestResetInstance(t *testing.T) {
+	server := &fakePlatformServer{}
+	client := newTestClient(t, server)
+	// scaler 通过类型断言检测平台是否支持原地重置实例
+	var resetter InstanceResetter = client
+	if err := resetter.ResetInstance(context.Background(), "req", "slot-1"); err != nil {
+		t.Fatalf("reset instance: %v", err)
+	}
+	if err := resetter.ResetInstance(context.Background(), "req", "missing"); err == nil {
+		t.Fatalf("non-ok status should return an error")
+	}
+	server.mu.Lock()
+	slots := append([]string(nil), server.resetInstances...)
+	server.mu.Unlock()
+	if len(slots) != 2 || slots[0] != "slot-1" {
+		t.Fatalf("unexpected reset requests %v", slots)
+	}
+
+	unimplemented := newTestClient(t, &pb.UnimplementedPlatformServer{})
+	if err := unimplemented.ResetInstance(context.Background(), "req", "slot-1"); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("got %v, want Unimplemented", err)
+	}
+}
@@ -24,4 +24,27 @@ type Client interface {
 	CreateSlot(ctx context.Context, requestId string, slotResourceConfig *model2.SlotResourceConfig) (*model2.Slot, error)
 	DestroySLot(ctx context.Context, requestId, slotId, reason string) error
 	Init(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta) (*model2.Instance, error)
+	// Ping 检查平台是否可以访问
+	Ping(ctx context.Context) error
+}
+
+// SlotGroupCreator 由支持一次调用创建一组 slot 的平台实现，scaler 通过类型断言检测
+// 平台不支持时返回 Unimplemented，scaler 退回逐个创建
+type SlotGroupCreator interface {
+	CreateSlotGroup(ctx context.Context, requestId string, count int, slotResourceConfig *model2.SlotResourceConfig) ([]*model2.Slot, error)
+}
+
+// SlotResetter 由支持重置 slot 的平台实现，重置后的 slot 可以直接用于初始化新实例
+type SlotResetter interface {
+	ResetSlot(ctx context.Context, requestId, slotId string) error
+}
+
+// InstanceResetter 由支持原地重新初始化实例的平台实现，用于回收用户代码出错但沙箱仍然可用的实例
+type InstanceResetter interface {
+	ResetInstance(ctx context.Context, requestId, slotId string) error
+}
+
+// TagInitializer 由支持按标签初始化实例的平台实现，用于满足 AssignRequest.RequiredTags
+type TagInitializer interface {
+	InitWithTags(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta, tags map[string]string) (*model2.Instance, error)
 }
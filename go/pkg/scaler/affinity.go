@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// 实例的 affinity key 是否仍然有效
+func (s *Simple) affinityValid(instance *model2.Instance) bool {
+	if instance.AffinityKey == "" {
+		return false
+	}
+	timeout := s.cfg().AffinityTimeout
+	return timeout <= 0 || time.Since(instance.AffinityAt) <= timeout
+}
+
+// 查找 affinity key 相同且未过期的空闲实例，调用方需持有 idleMu
+func (s *Simple) affinityIdleInstance(affinityKey string) *list.Element {
+	if affinityKey == "" {
+		return nil
+	}
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		if instance.AffinityKey == affinityKey && s.affinityValid(instance) {
+			return element
+		}
+	}
+	return nil
+}
+
+// 实例分配给带有 affinity key 的请求后绑定该 key，新创建的实例也由此继承请求的 key
+func bindAffinity(instance *model2.Instance, affinityKey string) {
+	if affinityKey == "" {
+		return
+	}
+	instance.AffinityKey = affinityKey
+	instance.AffinityAt = time.Now()
+}
+
+// 清除空闲实例上超过 AffinityTimeout 的 affinity key，在 gc 中调用
+func (s *Simple) expireAffinity() {
+	if s.cfg().AffinityTimeout <= 0 {
+		return
+	}
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		if instance.AffinityKey != "" && !s.affinityValid(instance) {
+			instance.AffinityKey = ""
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+// 创建两个实例，先归还带 affinity key 的实例，使另一个实例位于空闲队列队首
+func assignAffinityPair(t *testing.T, s *Simple, affinityKey string) (bound, other *pb.AssignReply) {
+	t.Helper()
+	ctx := context.Background()
+	request := assignRequest("bound")
+	request.AffinityKey = affinityKey
+	var err error
+	bound, err = s.Assign(ctx, request)
+	if err != nil {
+		t.Fatalf("assign bound: %v", err)
+	}
+	other, err = s.Assign(ctx, assignRequest("other"))
+	if err != nil {
+		t.Fatalf("assign other: %v", err)
+	}
+	for i, reply := range []*pb.AssignReply{bound, other} {
+		if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+		want := int64(i + 1)
+		waitFor(t, time.Second, func() bool { return int64(s.Stats().TotalIdleInstance) == want })
+	}
+	return bound, other
+}
+
+func TestAssignRoutesToAffinityInstance(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	bound, _ := assignAffinityPair(t, s, "session-1")
+
+	request := assignRequest("next")
+	request.AffinityKey = "session-1"
+	reply, err := s.Assign(context.Background(), request)
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if reply.Assigment.InstanceId != bound.Assigment.InstanceId {
+		t.Fatalf("got instance %s, want affinity instance %s", reply.Assigment.InstanceId, bound.Assigment.InstanceId)
+	}
+}
+
+func TestAffinityExpires(t *testing.T) {
+	cfg := testConfig()
+	cfg.AffinityTimeout = 20 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	bound, other := assignAffinityPair(t, s, "session-1")
+	time.Sleep(40 * time.Millisecond)
+
+	// 过期后不再按 affinity key 路由，按默认顺序选择队首的实例
+	s.idleMu.Lock()
+	element := s.affinityIdleInstance("session-1")
+	s.idleMu.Unlock()
+	if element != nil {
+		t.Fatalf("expired affinity key should not match")
+	}
+	s.expireAffinity()
+	s.idleMu.Lock()
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		if instance := element.Value.(*model2.Instance); instance.AffinityKey != "" {
+			s.idleMu.Unlock()
+			t.Fatalf("instance %s keeps expired affinity key %s", instance.Id, instance.AffinityKey)
+		}
+	}
+	s.idleMu.Unlock()
+
+	request := assignRequest("next")
+	request.AffinityKey = "session-1"
+	reply, err := s.Assign(context.Background(), request)
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if reply.Assigment.InstanceId != other.Assigment.InstanceId {
+		t.Fatalf("got instance %s, want front instance %s instead of expired %s", reply.Assigment.InstanceId, other.Assigment.InstanceId, bound.Assigment.InstanceId)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// AssignHint 调用方对空闲实例选择的提示，无法满足时退回默认的选择逻辑
+type AssignHint struct {
+	// 优先选择的实例
+	PreferInstanceId string
+	// 实例的 CustomData 中必须包含这些字符串值
+	RequireLabels map[string]string
+	// 不选择这些实例
+	AvoidInstanceIds []string
+	// 只选择空闲时间不超过该值的实例，0 表示不限制
+	MaxIdleAgeMs int64
+}
+
+func (h *AssignHint) isZero() bool {
+	return h.PreferInstanceId == "" && len(h.RequireLabels) == 0 && len(h.AvoidInstanceIds) == 0 && h.MaxIdleAgeMs <= 0
+}
+
+// 实例是否满足 RequireLabels、AvoidInstanceIds 和 MaxIdleAgeMs
+func (h *AssignHint) matches(instance *model2.Instance) bool {
+	for _, id := range h.AvoidInstanceIds {
+		if instance.Id == id {
+			return false
+		}
+	}
+	if h.MaxIdleAgeMs > 0 && time.Since(instance.LastIdleTime) > time.Duration(h.MaxIdleAgeMs)*time.Millisecond {
+		return false
+	}
+	for key, want := range h.RequireLabels {
+		value, ok := instance.GetCustomData(key)
+		if !ok {
+			return false
+		}
+		if got, isString := value.(string); !isString || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// 按提示挑选空闲实例，PreferInstanceId 满足条件时优先，否则选择第一个满足条件的实例，调用方需持有 idleMu
+func (s *Simple) selectIdleByHint(hint *AssignHint) *list.Element {
+	if len(hint.RequireLabels) > 0 {
+		return s.selectIdleByLabels(hint)
+	}
+	var first *list.Element
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		if !hint.matches(instance) {
+			continue
+		}
+		if hint.PreferInstanceId == "" || instance.Id == hint.PreferInstanceId {
+			return element
+		}
+		if first == nil {
+			first = element
+		}
+	}
+	return first
+}
+
+// 通过标签索引查找满足 RequireLabels 的实例，再检查其余条件
+func (s *Simple) selectIdleByLabels(hint *AssignHint) *list.Element {
+	var first *list.Element
+	for _, element := range s.labelIndex.lookup(hint.RequireLabels) {
+		instance := element.Value.(*model2.Instance)
+		if !hint.matches(instance) {
+			continue
+		}
+		if hint.PreferInstanceId == "" || instance.Id == hint.PreferInstanceId {
+			return element
+		}
+		if first == nil {
+			first = element
+		}
+	}
+	return first
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionDelete = "delete"
+	AuditActionReset  = "reset"
+)
+
+// 审计日志的一行
+type auditRecord struct {
+	Time       string `json:"time"`
+	Action     string `json:"action"`
+	SlotId     string `json:"slotId"`
+	InstanceId string `json:"instanceId"`
+	Reason     string `json:"reason"`
+}
+
+// auditLog 以 JSON 行追加写入 slot 的创建和删除记录，文件超过 maxSize 时重命名为 path.1 后重新创建
+type auditLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	// 最后一个使用者释放后置为 true，之后仍在进行的删除每次写入后立即关闭文件
+	closed bool
+	// 使用该审计日志的 scaler 数，由 auditLogsMu 保护
+	refs int
+}
+
+var (
+	auditLogsMu sync.Mutex
+	// 同一路径的审计日志在所有 scaler 间共享，保证写入和轮转串行
+	auditLogs = make(map[string]*auditLog)
+)
+
+// 打开 path 的审计日志，同一路径已经打开时共享同一个实例，轮转大小取第一个打开该路径的 scaler 的配置
+// 使用完后调用 release
+func openAuditLog(path string, maxSizeMb int64) *auditLog {
+	auditLogsMu.Lock()
+	defer auditLogsMu.Unlock()
+	if a, ok := auditLogs[path]; ok {
+		a.refs++
+		return a
+	}
+	a := &auditLog{path: path, maxSize: maxSizeMb * 1024 * 1024, refs: 1}
+	auditLogs[path] = a
+	return a
+}
+
+// WithAuditLog 把 slot 的创建和删除记录写入 path，覆盖 Config.AuditLogPath
+func WithAuditLog(path string) Option {
+	return func(s *Simple) {
+		if s.auditLog != nil {
+			s.auditLog.release()
+			s.auditLog = nil
+		}
+		if path != "" {
+			s.auditLog = openAuditLog(path, s.cfg().AuditLogMaxSizeMb)
+		}
+	}
+}
+
+// 释放一次引用，最后一个使用者释放时关闭文件，之后再打开同一路径会重新读取配置
+func (a *auditLog) release() error {
+	auditLogsMu.Lock()
+	a.refs--
+	last := a.refs == 0
+	if last {
+		delete(auditLogs, a.path)
+	}
+	auditLogsMu.Unlock()
+	if !last {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file, a.size = nil, 0
+	return err
+}
+
+func (s *Simple) audit(action, slotId, instanceId, reason string) {
+	if s.auditLog == nil {
+		return
+	}
+	if err := s.auditLog.write(auditRecord{
+		Time:       time.Now().Format(time.RFC3339Nano),
+		Action:     action,
+		SlotId:     slotId,
+		InstanceId: instanceId,
+		Reason:     reason,
+	}); err != nil {
+		s.logger.Error("write audit log failed", "path", s.auditLog.path, "error", err)
+	}
+}
+
+func (a *auditLog) write(record auditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file != nil && a.maxSize > 0 && a.size+int64(len(line)) > a.maxSize {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+	if a.file == nil {
+		if err := a.open(); err != nil {
+			return err
+		}
+	}
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	if a.closed {
+		if closeErr := a.file.Close(); err == nil {
+			err = closeErr
+		}
+		a.file, a.size = nil, 0
+	}
+	return err
+}
+
+func (a *auditLog) open() error {
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	a.file, a.size = file, info.Size()
+	return nil
+}
+
+// 关闭当前文件并重命名为 path.1，已有的 path.1 会被覆盖
+func (a *auditLog) rotate() error {
+	err := a.file.Close()
+	a.file, a.size = nil, 0
+	if err != nil {
+		return err
+	}
+	return os.Rename(a.path, a.path+".1")
+}
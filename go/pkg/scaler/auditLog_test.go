@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func readAuditLog(t *testing.T, path string) []auditRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer file.Close()
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("unmarshal %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// 创建和删除记录带有相同的 slot id 和实例 id，Close 后关闭文件
+func TestAuditLogCreateDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := testConfig()
+	cfg.AuditLogPath = path
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	records := readAuditLog(t, path)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2: %+v", len(records), records)
+	}
+	assignment := reply.Assigment
+	for i, action := range []string{AuditActionCreate, AuditActionDelete} {
+		record := records[i]
+		if record.Action != action || record.SlotId != assignment.SlotId || record.InstanceId != assignment.InstanceId {
+			t.Fatalf("record %d is %+v, want %s of slot %s instance %s", i, record, action, assignment.SlotId, assignment.InstanceId)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, record.Time); err != nil {
+			t.Fatalf("record %d has invalid time: %v", i, err)
+		}
+	}
+	auditLogsMu.Lock()
+	_, open := auditLogs[path]
+	auditLogsMu.Unlock()
+	if open || s.auditLog.file != nil {
+		t.Fatalf("audit log is still open after close")
+	}
+}
+
+// Init 失败时删除记录与创建记录使用同一个实例 id
+func TestAuditLogInitFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := testConfig()
+	cfg.AuditLogPath = path
+	platform := newFakePlatform()
+	platform.setInitErr(status.Error(codes.Internal, "init failed"))
+	s := newTestSimple(t, cfg, platform)
+	if _, err := s.Assign(context.Background(), assignRequest("r1")); err == nil {
+		t.Fatalf("assign should fail when init fails")
+	}
+	s.Close(context.Background())
+
+	records := readAuditLog(t, path)
+	if len(records) != 2 || records[0].Action != AuditActionCreate || records[1].Action != AuditActionDelete {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if records[0].InstanceId == "" || records[0].InstanceId != records[1].InstanceId || records[1].Reason != "init failed" {
+		t.Fatalf("init failure records do not match: %+v", records)
+	}
+}
+
+// 同一路径在 scaler 间共享，轮转大小取第一个打开的配置，最后一个 scaler 关闭后才关闭文件
+func TestAuditLogSharedPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	first := testConfig()
+	first.AuditLogPath = path
+	first.AuditLogMaxSizeMb = 1
+	second := *first
+	second.AuditLogMaxSizeMb = 5
+	s1 := newTestSimple(t, first, newFakePlatform())
+	s2 := newTestSimple(t, &second, newFakePlatform())
+	if s1.auditLog != s2.auditLog || s2.auditLog.maxSize != 1024*1024 {
+		t.Fatalf("scalers on the same path should share the first audit log")
+	}
+	assignAll(t, s2, assignRequest("r1"))
+
+	s1.Close(context.Background())
+	auditLogsMu.Lock()
+	_, open := auditLogs[path]
+	auditLogsMu.Unlock()
+	if !open || s2.auditLog.file == nil {
+		t.Fatalf("audit log was closed while still in use")
+	}
+	s2.Close(context.Background())
+	auditLogsMu.Lock()
+	_, open = auditLogs[path]
+	auditLogsMu.Unlock()
+	if open || s2.auditLog.file != nil {
+		t.Fatalf("audit log is still open after the last scaler closed")
+	}
+}
+
+// 超过轮转大小后旧文件重命名为 path.1
+func TestAuditLogRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a := openAuditLog(path, 0)
+	defer a.release()
+	a.maxSize = 300
+	for i := 0; i < 5; i++ {
+		if err := a.write(auditRecord{Action: AuditActionCreate, SlotId: "slot", InstanceId: "instance"}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	rotated := readAuditLog(t, path+".1")
+	current := readAuditLog(t, path)
+	if len(rotated) == 0 || len(rotated)+len(current) != 5 {
+		t.Fatalf("rotated %d and kept %d records, want 5 in total", len(rotated), len(current))
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() > a.maxSize {
+		t.Fatalf("current file exceeds the max size: %v %v", info, err)
+	}
+}
+
+// 多次 Close 只释放一次引用，不影响共用同一路径的其他 scaler
+func TestAuditLogCloseTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := testConfig()
+	cfg.AuditLogPath = path
+	s1 := newTestSimple(t, cfg, newFakePlatform())
+	s2 := newTestSimple(t, cfg, newFakePlatform())
+	s1.Close(context.Background())
+	s1.Close(context.Background())
+	auditLogsMu.Lock()
+	refs := s2.auditLog.refs
+	auditLogsMu.Unlock()
+	if refs != 1 {
+		t.Fatalf("got %d references after closing one scaler twice, want 1", refs)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+// BatchAssign 一次分配 count 个不同的实例，第 i 个实例按子请求 <request id>-<i> 走 Assign 的完整流程
+// 子请求并发执行，任一失败时通过 Idle 归还已分配的实例并返回错误
+// 多槽位实例可能同时分给多个子请求，重复的子请求归还后改为 Cold 重新分配
+func (s *Simple) BatchAssign(ctx context.Context, request *pb.BatchAssignRequest) (*pb.BatchAssignReply, error) {
+	if request.MetaData == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "app meta is nil")
+	}
+	count := int(request.Count)
+	if count <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "request id %s, count must be positive", request.RequestId)
+	}
+	if limit := s.cfg().MaxInstances; limit > 0 && count > limit {
+		return nil, status.Errorf(codes.ResourceExhausted, "request id %s, count %d exceeds max instances %d", request.RequestId, count, limit)
+	}
+	s.logger.InfoContext(ctx, "batch assign", "requestId", request.RequestId, "count", count)
+	items := make([]*pb.AssignRequest, count)
+	pending := make([]int, count)
+	for i := range items {
+		items[i] = &pb.AssignRequest{
+			RequestId:    fmt.Sprintf("%s-%d", request.RequestId, i),
+			Timestamp:    request.Timestamp,
+			MetaData:     request.MetaData,
+			ClientId:     request.ClientId,
+			RequiredTags: request.RequiredTags,
+		}
+		pending[i] = i
+	}
+	replies := make([]*pb.AssignReply, count)
+	var err error
+	// 重复分配只发生在多槽位实例上，最多重试 count 轮
+	for round := 0; len(pending) > 0; round++ {
+		if round == count {
+			err = status.Errorf(codes.Unavailable, "request id %s, can not assign %d distinct instances", request.RequestId, count)
+			break
+		}
+		if err = s.assignBatchItems(ctx, items, pending, replies); err != nil {
+			break
+		}
+		pending = s.releaseDuplicates(ctx, items, replies)
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "batch assign failed, return assigned instances", "requestId", request.RequestId, "count", count, "error", err)
+		for _, reply := range replies {
+			if reply != nil {
+				s.Idle(ctx, &pb.IdleRequest{Assigment: reply.Assigment})
+			}
+		}
+		return nil, err
+	}
+	reply := &pb.BatchAssignReply{Status: pb.Status_Ok}
+	for _, item := range replies {
+		reply.Assigments = append(reply.Assigments, item.Assigment)
+	}
+	return reply, nil
+}
+
+// 并发分配 pending 中的子请求，结果写入 replies，第一个失败的子请求取消其余的子请求并返回其错误
+func (s *Simple) assignBatchItems(ctx context.Context, items []*pb.AssignRequest, pending []int, replies []*pb.AssignReply) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, i := range pending {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := s.Assign(ctx, items[i])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			replies[i] = reply
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// 归还与前面的子请求分到同一实例的子请求，返回其下标，这些子请求下一轮改为 Cold
+func (s *Simple) releaseDuplicates(ctx context.Context, items []*pb.AssignRequest, replies []*pb.AssignReply) []int {
+	var duplicates []int
+	seen := make(map[string]bool, len(replies))
+	for i, reply := range replies {
+		instanceId := reply.Assigment.InstanceId
+		if !seen[instanceId] {
+			seen[instanceId] = true
+			continue
+		}
+		s.Idle(ctx, &pb.IdleRequest{Assigment: reply.Assigment})
+		replies[i] = nil
+		items[i].PoolPreference = pb.PoolPreference_Cold
+		duplicates = append(duplicates, i)
+	}
+	return duplicates
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func batchAssignRequest(requestId string, count uint32) *pb.BatchAssignRequest {
+	meta := testMeta()
+	return &pb.BatchAssignRequest{
+		RequestId: requestId,
+		Timestamp: uint64(time.Now().UnixMilli()),
+		MetaData:  &meta.Meta,
+		Count:     count,
+	}
+}
+
+// 批量分配返回的实例释放后放回空闲队列
+func idleBatch(tb testing.TB, s *Simple, reply *pb.BatchAssignReply) {
+	tb.Helper()
+	for _, assignment := range reply.Assigments {
+		if _, err := s.Idle(context.Background(), &pb.IdleRequest{Assigment: assignment}); err != nil {
+			tb.Fatalf("idle: %v", err)
+		}
+	}
+}
+
+// 先用空闲实例，不足的部分创建新实例，每个实例对应一个 <request id>-<i> 的子请求
+func TestBatchAssign(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idleN(t, s, 1)
+
+	reply, err := s.BatchAssign(context.Background(), batchAssignRequest("batch", 3))
+	if err != nil {
+		t.Fatalf("batch assign: %v", err)
+	}
+	if len(reply.Assigments) != 3 {
+		t.Fatalf("got %d assignments, want 3", len(reply.Assigments))
+	}
+	instances := make(map[string]bool)
+	for i, assignment := range reply.Assigments {
+		instances[assignment.InstanceId] = true
+		if want := fmt.Sprintf("batch-%d", i); assignment.RequestId != want {
+			t.Fatalf("got request id %s, want %s", assignment.RequestId, want)
+		}
+	}
+	if len(instances) != 3 {
+		t.Fatalf("instances are not distinct: %v", reply.Assigments)
+	}
+	if creates := atomic.LoadInt64(&platform.creates); creates != 3 {
+		t.Fatalf("got %d creates, want 3 with one idle instance reused", creates)
+	}
+	if n := atomic.LoadInt64(&s.inFlight); n != 3 {
+		t.Fatalf("got %d in-flight requests, want 3", n)
+	}
+	idleBatch(t, s, reply)
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 3 })
+	if n := atomic.LoadInt64(&s.inFlight); n != 0 {
+		t.Fatalf("got %d in-flight requests after idle, want 0", n)
+	}
+}
+
+// 有实例创建失败时归还已分配的实例，不留下在途请求
+func TestBatchAssignRollback(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idleN(t, s, 1)
+	platform.setCreateErr(status.Error(codes.Internal, "create failed"))
+
+	if _, err := s.BatchAssign(context.Background(), batchAssignRequest("batch", 3)); err == nil {
+		t.Fatalf("batch assign should fail when creation fails")
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	if n := atomic.LoadInt64(&s.inFlight); n != 0 {
+		t.Fatalf("got %d in-flight requests after rollback, want 0", n)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("got %d waiting requests after rollback, want 0", n)
+	}
+}
+
+// ctx 结束前实例没有全部就绪时同样回滚
+func TestBatchAssignDeadline(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 2
+	s := newTestSimple(t, cfg, newFakePlatform())
+	busy := assignAll(t, s, assignRequest("busy"))[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.BatchAssign(ctx, batchAssignRequest("batch", 2)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	if n := atomic.LoadInt64(&s.inFlight); n != 1 {
+		t.Fatalf("got %d in-flight requests, want only the busy one", n)
+	}
+	idleInOrder(t, s, busy)
+
+	if _, err := s.BatchAssign(context.Background(), batchAssignRequest("large", 3)); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted for count above MaxInstances", err)
+	}
+}
+
+// 多槽位实例不会在同一批中分配两次
+func TestBatchAssignDistinctMultiSlot(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceCapacity = 4
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	// 空闲队列中有一个还剩 4 个槽位的实例
+	first := assignAll(t, s, assignRequest("first"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(first, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 && atomic.LoadInt64(&s.inFlight) == 0 })
+
+	reply, err := s.BatchAssign(context.Background(), batchAssignRequest("batch", 3))
+	if err != nil {
+		t.Fatalf("batch assign: %v", err)
+	}
+	instances := make(map[string]bool)
+	for _, assignment := range reply.Assigments {
+		instances[assignment.InstanceId] = true
+	}
+	if len(instances) != 3 {
+		t.Fatalf("instances are not distinct: %v", reply.Assigments)
+	}
+	idleBatch(t, s, reply)
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.inFlight) == 0 })
+}
+
+// 每个子请求都经过客户端限流，超过限额时整批失败
+func TestBatchAssignClientRateLimit(t *testing.T) {
+	cfg := testConfig()
+	cfg.ClientRateLimits = map[string]float64{"tenant": 2}
+	s := newTestSimple(t, cfg, newFakePlatform())
+	request := batchAssignRequest("batch", 3)
+	request.ClientId = "tenant"
+	if _, err := s.BatchAssign(context.Background(), request); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.inFlight) == 0 })
+}
+
+// 带标签的批量请求只使用标签满足的空闲实例
+func TestBatchAssignRequiredTags(t *testing.T) {
+	platform := newFakeTagPlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	tags := map[string]string{"gpu": "a10"}
+	untagged := assignAll(t, s, assignRequest("untagged"))[0]
+	tagged := assignAll(t, s, taggedAssignRequest("tagged", tags))[0]
+	idleInOrder(t, s, untagged, tagged)
+
+	request := batchAssignRequest("batch", 2)
+	request.RequiredTags = tags
+	reply, err := s.BatchAssign(context.Background(), request)
+	if err != nil {
+		t.Fatalf("batch assign: %v", err)
+	}
+	for _, assignment := range reply.Assigments {
+		if assignment.InstanceId == untagged.Assigment.InstanceId {
+			t.Fatalf("untagged instance was assigned to a tagged batch")
+		}
+	}
+	if ids := idleIds(s); len(ids) != 1 || ids[0] != untagged.Assigment.InstanceId {
+		t.Fatalf("untagged instance should stay idle, got %v", ids)
+	}
+}
+
+// 下线等待批量分配的实例全部释放
+func TestBatchAssignHoldsDrain(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	reply, err := s.BatchAssign(context.Background(), batchAssignRequest("batch", 2))
+	if err != nil {
+		t.Fatalf("batch assign: %v", err)
+	}
+	s.Drain()
+	if _, err := s.BatchAssign(context.Background(), batchAssignRequest("late", 1)); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want FailedPrecondition while draining", err)
+	}
+	if drainCompleted(s) {
+		t.Fatalf("drain completed while batch instances are in use")
+	}
+	idleBatch(t, s, reply)
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete after the batch is idle")
+	}
+}
+
+// 子请求与 Assign 一样写入请求日志
+func TestBatchAssignJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	s := newTestSimple(t, testConfig(), newFakePlatform(), WithJournal(path))
+	reply, err := s.BatchAssign(context.Background(), batchAssignRequest("batch", 2))
+	if err != nil {
+		t.Fatalf("batch assign: %v", err)
+	}
+	if err := s.journal.Close(); err != nil {
+		t.Fatalf("close journal: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	logged := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record JournalRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("malformed record %q: %v", line, err)
+		}
+		if record.Event == JournalEventAssign {
+			logged[record.RequestId] = record.InstanceId
+		}
+	}
+	for _, assignment := range reply.Assigments {
+		if logged[assignment.RequestId] != assignment.InstanceId {
+			t.Fatalf("assignment %v is not journaled, got %v", assignment, logged)
+		}
+	}
+}
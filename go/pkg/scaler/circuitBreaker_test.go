@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Hour)
+	failure := errors.New("platform down")
+
+	if !b.Allow() {
+		t.Fatalf("closed breaker should allow calls")
+	}
+	b.Record(failure)
+	if b.State() != CircuitClosed {
+		t.Fatalf("state %s after 1 failure, want closed", b.State())
+	}
+	// 成功会清零连续失败次数
+	b.Allow()
+	b.Record(nil)
+	b.Allow()
+	b.Record(failure)
+	if b.State() != CircuitClosed {
+		t.Fatalf("state %s, failures should reset after success", b.State())
+	}
+	b.Allow()
+	b.Record(failure)
+	if b.State() != CircuitOpen {
+		t.Fatalf("state %s after threshold failures, want open", b.State())
+	}
+	if b.Allow() {
+		t.Fatalf("open breaker should reject calls before recovery")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	b := NewCircuitBreaker(1, 10*time.Millisecond)
+	b.Allow()
+	b.Record(errors.New("platform down"))
+	time.Sleep(20 * time.Millisecond)
+
+	// recovery 之后只放行一次探测
+	if !b.Allow() {
+		t.Fatalf("breaker should allow a probe after recovery")
+	}
+	if b.State() != CircuitHalfOpen {
+		t.Fatalf("state %s, want half_open", b.State())
+	}
+	if b.Allow() {
+		t.Fatalf("half open breaker should allow only one probe")
+	}
+	// 探测没有到达平台，下一次调用可以继续探测
+	b.Release()
+	if !b.Allow() {
+		t.Fatalf("breaker should allow a new probe after release")
+	}
+	b.Record(nil)
+	if b.State() != CircuitClosed {
+		t.Fatalf("state %s after successful probe, want closed", b.State())
+	}
+}
+
+func TestCircuitBreakerProbeFailureReopens(t *testing.T) {
+	b := NewCircuitBreaker(3, 10*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		b.Allow()
+		b.Record(errors.New("platform down"))
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatalf("breaker should allow a probe after recovery")
+	}
+	// 半开状态下一次失败就重新打开
+	b.Record(errors.New("still down"))
+	if b.State() != CircuitOpen {
+		t.Fatalf("state %s after failed probe, want open", b.State())
+	}
+	if b.Allow() {
+		t.Fatalf("reopened breaker should reject calls before recovery")
+	}
+}
+
+func TestNilCircuitBreaker(t *testing.T) {
+	var b *CircuitBreaker
+	if !b.Allow() {
+		t.Fatalf("nil breaker should allow calls")
+	}
+	b.Record(errors.New("platform down"))
+	b.Release()
+	if b.State() != CircuitClosed {
+		t.Fatalf("nil breaker state %s, want closed", b.State())
+	}
+}
+
+// 熔断打开后 Assign 不再调用平台，直接返回 Unavailable
+func TestAssignFailsFastWhenCircuitOpen(t *testing.T) {
+	cfg := testConfig()
+	cfg.CircuitBreakerThreshold = 2
+	cfg.CircuitBreakerRecovery = time.Hour
+	platform := newFakePlatform()
+	platform.setCreateErr(status.Error(codes.Internal, "platform down"))
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Assign(ctx, assignRequest("fail")); err == nil {
+			t.Fatalf("assign should fail while platform is down")
+		}
+	}
+	if state := s.Stats().CircuitState; state != "open" {
+		t.Fatalf("circuit state %s, want open", state)
+	}
+	creates := atomic.LoadInt64(&platform.creates)
+	_, err := s.Assign(ctx, assignRequest("rejected"))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != creates {
+		t.Fatalf("open breaker should not call the platform, creates %d -> %d", creates, n)
+	}
+}
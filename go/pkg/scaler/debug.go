@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const debugPathPrefix = "/debug/scaler/"
+
+// 调试接口分页返回空闲实例时的默认页大小
+const defaultIdlePageLimit = 50
+
+type debugInstance struct {
+	InstanceId   string    `json:"instanceId"`
+	Busy         bool      `json:"busy"`
+	LastIdleTime time.Time `json:"lastIdleTime"`
+	RecycleCount int       `json:"recycleCount"`
+}
+
+type debugRuntimeStatus struct {
+	RequestCostTimeMs int64 `json:"requestCostTimeMs"`
+	MaxRequestNum     int64 `json:"maxRequestNum"`
+	CurrentRequestNum int64 `json:"currentRequestNum"`
+}
+
+// 调试接口返回的 scaler 状态
+type debugState struct {
+	MetaKey       string             `json:"metaKey"`
+	Instances     []debugInstance    `json:"instances"`
+	IdleInstances int                `json:"idleInstances"`
+	LongPolling   int                `json:"longPolling"`
+	Creating      int64              `json:"creating"`
+	RuntimeStatus debugRuntimeStatus `json:"runtimeStatus"`
+}
+
+// 在各自的锁内复制状态，编码时不持有锁
+func (s *Simple) debugState() debugState {
+	state := debugState{
+		MetaKey:  s.metaData.Key,
+		Creating: atomic.LoadInt64(&s.creatingNum),
+		RuntimeStatus: debugRuntimeStatus{
+			RequestCostTimeMs: s.runtimeStatus.GetRequestCostTime().Milliseconds(),
+			MaxRequestNum:     s.runtimeStatus.getMaxRequestBNum(),
+			CurrentRequestNum: s.runtimeStatus.getCurrentRequestBNum(),
+		},
+	}
+	s.instancesMu.RLock()
+	state.Instances = make([]debugInstance, 0, len(s.instances))
+	for _, instance := range s.instances {
+		state.Instances = append(state.Instances, debugInstance{
+			InstanceId:   instance.Id,
+			Busy:         instance.IsBusy(),
+			LastIdleTime: instance.LastIdleTime,
+			RecycleCount: instance.RecycleCount,
+		})
+	}
+	s.instancesMu.RUnlock()
+	s.idleMu.Lock()
+	state.IdleInstances = s.idleInstance.Len()
+	s.idleMu.Unlock()
+	s.longPollingMu.Lock()
+	state.LongPolling = s.longPollingHeap.Len()
+	s.longPollingMu.Unlock()
+	return state
+}
+
+// DebugHandler 以 JSON 返回 scaler 当前的实例、队列和运行时统计
+// 带有 offset 或 limit 参数时改为返回一页空闲实例，limit 默认为 50；format=text 时返回 DumpStats 的文本
+func (s *Simple) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("format") == "text" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			if err := s.DumpStats(w); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if !query.Has("offset") && !query.Has("limit") {
+			writeDebugJSON(w, s.debugState())
+			return
+		}
+		offset, limit := 0, defaultIdlePageLimit
+		var err error
+		if value := query.Get("offset"); value != "" {
+			if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+				http.Error(w, "invalid offset", http.StatusBadRequest)
+				return
+			}
+		}
+		if value := query.Get("limit"); value != "" {
+			if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		page := s.GetIdlePage(offset, limit)
+		if page == nil {
+			page = []*InstanceDetail{}
+		}
+		writeDebugJSON(w, page)
+	})
+}
+
+// DebugMux 在 /debug/scaler/{metaKey} 下返回该 meta key 所有未关闭 scaler 的状态数组，分片时每个分片一项
+func DebugMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(debugPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		metaKey := strings.TrimPrefix(r.URL.Path, debugPathPrefix)
+		states := []debugState{}
+		liveScalers.Range(func(key, _ any) bool {
+			if s := key.(*Simple); s.metaData.Key == metaKey {
+				states = append(states, s.debugState())
+			}
+			return true
+		})
+		if len(states) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		writeDebugJSON(w, states)
+	})
+	return mux
+}
+
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func TestDebugHandlerIdlePage(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	idleN(t, s, 3)
+	ids := idleIds(s)
+	handler := s.DebugHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug?offset=1&limit=5", nil))
+	var page []InstanceDetail
+	if err := json.NewDecoder(recorder.Body).Decode(&page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	if len(page) != 2 || page[0].InstanceId != ids[1] || page[1].InstanceId != ids[2] {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	// 没有分页参数时返回完整状态
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug", nil))
+	var state debugState
+	if err := json.NewDecoder(recorder.Body).Decode(&state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if state.IdleInstances != 3 || len(state.Instances) != 3 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug?limit=abc", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want 400", recorder.Code)
+	}
+}
+
+func TestDebugHandlerText(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	recorder := httptest.NewRecorder()
+	s.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug?format=text", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, "idle_instances:") || !strings.Contains(body, "p95_latency_ms:") {
+		t.Fatalf("unexpected text stats:\n%s", body)
+	}
+}
+
+// 完整状态包含所有字段且数值非负，忙碌实例和等待中的请求反映在状态中
+func TestDebugHandlerState(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idleN(t, s, 2)
+	busy := assignAll(t, s, assignRequest("busy"))[0]
+	observeCost(s.runtimeStatus, "cost", 50*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	s.DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug", nil))
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("got status %d content type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	for _, key := range []string{"metaKey", "instances", "idleInstances", "longPolling", "creating", "runtimeStatus"} {
+		if _, ok := raw[key]; !ok {
+			t.Fatalf("state has no %q: %s", key, recorder.Body.String())
+		}
+	}
+	var runtime map[string]int64
+	if err := json.Unmarshal(raw["runtimeStatus"], &runtime); err != nil {
+		t.Fatalf("decode runtime status: %v", err)
+	}
+	for _, key := range []string{"requestCostTimeMs", "maxRequestNum", "currentRequestNum"} {
+		if value, ok := runtime[key]; !ok || value < 0 {
+			t.Fatalf("runtime status %q is %d, present %v", key, value, ok)
+		}
+	}
+
+	var state debugState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if state.MetaKey != s.metaData.Key || state.IdleInstances != 1 || state.LongPolling != 0 || state.Creating != 0 || len(state.Instances) != 2 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	if state.RuntimeStatus.RequestCostTimeMs == 0 {
+		t.Fatalf("request cost %dms should include the observed request", state.RuntimeStatus.RequestCostTimeMs)
+	}
+	for _, instance := range state.Instances {
+		if instance.Busy != (instance.InstanceId == busy.Assigment.InstanceId) || instance.LastIdleTime.IsZero() {
+			t.Fatalf("unexpected instance %+v", instance)
+		}
+	}
+}
+
+// DebugMux 按 meta key 返回未关闭的 scaler，关闭后返回 404
+func TestDebugMux(t *testing.T) {
+	meta := &model2.Meta{Meta: pb.Meta{Key: "debug-mux", Runtime: "go", TimeoutInSecs: 10, MemoryInMb: 128}}
+	s := newSimple(meta, testConfig(), withPlatformClient(newFakePlatform()), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	mux := DebugMux()
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, debugPathPrefix+meta.Key, nil))
+	var states []debugState
+	if err := json.NewDecoder(recorder.Body).Decode(&states); err != nil {
+		t.Fatalf("decode states: %v", err)
+	}
+	if len(states) != 1 || states[0].MetaKey != meta.Key {
+		t.Fatalf("unexpected states %+v", states)
+	}
+
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, debugPathPrefix+"unknown", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for an unknown key, want 404", recorder.Code)
+	}
+
+	s.Close(context.Background())
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, debugPathPrefix+meta.Key, nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for a closed scaler, want 404", recorder.Code)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// Drain 标记 scaler 为下线中，之后的 Assign 直接返回 FailedPrecondition，Idle 不受影响
+// 空闲实例立即回收；之后请求结束归还的实例和创建完成的实例在没有等待的请求时也直接回收，不再放回空闲队列
+func (s *Simple) Drain() {
+	if atomic.CompareAndSwapInt32(&s.draining, 0, 1) {
+		s.logger.Info("scaler is draining", "metaKey", s.metaData.Key)
+		s.evictIdleForDrain()
+	}
+	s.checkDrainComplete()
+}
+
+// 回收空闲队列中没有请求在处理的实例，仍有请求在处理的多槽位实例在最后一个请求 Idle 时回收
+func (s *Simple) evictIdleForDrain() {
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	s.idleMu.Lock()
+	var drained []*model2.Instance
+	for element := s.idleInstance.Front(); element != nil; {
+		next := element.Next()
+		if instance := element.Value.(*model2.Instance); !instance.IsBusy() {
+			s.removeIdleElement(element)
+			delete(s.instances, instance.Id)
+			drained = append(drained, instance)
+		}
+		element = next
+	}
+	s.idleMu.Unlock()
+	s.logger.Info("destroy idle instances of draining scaler", "metaKey", s.metaData.Key, "count", len(drained))
+	for _, instance := range drained {
+		s.goDestroy(instance, EvictReasonDrained)
+	}
+}
+
+// IsDraining 是否已经调用过 Drain
+func (s *Simple) IsDraining() bool {
+	return atomic.LoadInt32(&s.draining) == 1
+}
+
+// DrainComplete 下线中且没有空闲实例、创建中的实例和已分配未 Idle 的请求时关闭
+func (s *Simple) DrainComplete() <-chan struct{} {
+	return s.drainDone
+}
+
+// 满足下线完成的条件时关闭 drainDone，Drain、Idle、释放创建额度和每次 gc 扫描之后调用
+func (s *Simple) checkDrainComplete() {
+	if !s.IsDraining() {
+		return
+	}
+	s.idleMu.Lock()
+	idle := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	if idle > 0 || atomic.LoadInt64(&s.creatingNum) > 0 || atomic.LoadInt64(&s.inFlight) > 0 {
+		return
+	}
+	s.drainOnce.Do(func() {
+		s.logger.Info("scaler is drained", "metaKey", s.metaData.Key)
+		close(s.drainDone)
+	})
+}
+
+// Retire 下线并关闭 scaler，同时销毁全部实例，用于被替换的 scaler
+// 先等待已分配的请求 Idle，直到 ctx 结束；之后停止后台任务，不论实例是否忙碌全部销毁，最后调用 Close
+// 与 Close 不同，Retire 之后平台上不会留下属于该 scaler 的 slot
+func (s *Simple) Retire(ctx context.Context) error {
+	s.Drain()
+	if !s.waitInFlight(ctx) {
+		s.logger.Warn("retire scaler before requests finished", "metaKey", s.metaData.Key, "count", atomic.LoadInt64(&s.inFlight))
+	}
+	// 不再使用调用方的 ctx，避免已经超时的 ctx 让销毁和关闭直接失败
+	closeCtx, cancel := context.WithTimeout(context.Background(), s.cfg().ShutdownTimeout)
+	defer cancel()
+	// 先等待正在进行的创建完成，之后不会再有新的实例
+	if err := s.Shutdown(closeCtx); err != nil {
+		s.logger.Warn("shutdown retired scaler timeout", "metaKey", s.metaData.Key, "error", err)
+	}
+	s.instancesMu.Lock()
+	instances := make([]*model2.Instance, 0, len(s.instances))
+	for _, instance := range s.instances {
+		instances = append(instances, instance)
+	}
+	s.instances = make(map[string]*model2.Instance)
+	s.idleMu.Lock()
+	for element := s.idleInstance.Front(); element != nil; element = s.idleInstance.Front() {
+		s.removeIdleElement(element)
+	}
+	s.idleMu.Unlock()
+	s.instancesMu.Unlock()
+	s.logger.Info("destroy instances of retired scaler", "metaKey", s.metaData.Key, "count", len(instances))
+	var wg sync.WaitGroup
+	for _, instance := range instances {
+		wg.Add(1)
+		go func(instance *model2.Instance) {
+			defer wg.Done()
+			s.destroyInstance(instance, EvictReasonRetired)
+		}(instance)
+	}
+	wg.Wait()
+	return s.Close(closeCtx)
+}
+
+// 等待已分配的请求全部 Idle，ctx 先结束时返回 false
+func (s *Simple) waitInFlight(ctx context.Context) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&s.inFlight) > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func drainCompleted(s *Simple) bool {
+	select {
+	case <-s.DrainComplete():
+		return true
+	default:
+		return false
+	}
+}
+
+// 没有实例时 Drain 立即完成
+func TestDrainEmpty(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	if s.IsDraining() {
+		t.Fatalf("new scaler should not be draining")
+	}
+	s.Drain()
+	if !s.IsDraining() || !drainCompleted(s) {
+		t.Fatalf("empty scaler should finish draining at once")
+	}
+	// 重复调用不会再次关闭 channel
+	s.Drain()
+}
+
+// Drain 立即回收空闲实例，之后的 Assign 返回 FailedPrecondition 且不创建实例
+// 正在处理的请求 Idle 后实例被回收，DrainComplete 随之关闭，不需要等 gc
+func TestDrainDestroysIdleAndFollowsInFlight(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	replies := assignAll(t, s, assignRequest("idle"), assignRequest("busy"))
+	if _, err := s.Idle(context.Background(), idleRequest(replies[0], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+
+	s.Drain()
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroys) == 1 })
+	if stats := s.Stats(); stats.TotalInstance != 1 || stats.TotalIdleInstance != 0 {
+		t.Fatalf("idle instance should be destroyed on drain: %+v", stats)
+	}
+	if drainCompleted(s) {
+		t.Fatalf("drain completed while a request is in flight")
+	}
+	if _, err := s.Assign(context.Background(), assignRequest("new")); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want FailedPrecondition", err)
+	}
+	if creates := atomic.LoadInt64(&platform.creates); creates != 2 {
+		t.Fatalf("assign during drain created an instance, creates %d", creates)
+	}
+
+	if _, err := s.Idle(context.Background(), idleRequest(replies[1], false)); err != nil {
+		t.Fatalf("idle during drain: %v", err)
+	}
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete after the last request")
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if stats := s.Stats(); stats.TotalInstance != 0 {
+		t.Fatalf("instances left after drain: %+v", stats)
+	}
+}
+
+// 下线前已经在等待的请求仍然拿到创建完成的实例，请求结束后实例被回收
+func TestDrainWaitsForCreation(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 100 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	replies := make(chan error, 1)
+	var reply *pb.AssignReply
+	go func() {
+		var err error
+		reply, err = s.Assign(context.Background(), assignRequest("waiting"))
+		replies <- err
+	}()
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingNum) == 1 })
+
+	s.Drain()
+	if drainCompleted(s) {
+		t.Fatalf("drain completed while an instance is being created")
+	}
+	if err := <-replies; err != nil {
+		t.Fatalf("waiting assign failed: %v", err)
+	}
+	if drainCompleted(s) {
+		t.Fatalf("drain completed while the created instance is in use")
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete after the created instance was released")
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+}
+
+// 下线中创建完成且没有等待请求的实例不放回空闲队列
+func TestDrainDestroysInstanceCreatedWithoutWaiter(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 100 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Assign(ctx, assignRequest("gave-up")); err == nil {
+		t.Fatalf("assign should fail when the caller gives up")
+	}
+	s.Drain()
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete after the creation finished")
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if n := len(idleIds(s)); n != 0 {
+		t.Fatalf("created instance was put back to the idle pool during drain")
+	}
+}
+
+// 仍有请求在处理的多槽位实例在最后一个请求 Idle 时回收
+func TestDrainMultiSlotInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceCapacity = 2
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"))
+	if replies[0].Assigment.InstanceId != replies[1].Assigment.InstanceId {
+		t.Fatalf("first two requests should share an instance")
+	}
+
+	s.Drain()
+	// r2 的实例还有一个空闲槽位，仍在空闲队列中但有请求在处理，不回收
+	time.Sleep(20 * time.Millisecond)
+	if destroys := atomic.LoadInt64(&platform.destroys); destroys != 0 {
+		t.Fatalf("busy instances were destroyed on drain: %d", destroys)
+	}
+	for i, reply := range replies {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle %d: %v", i, err)
+		}
+		if i == 0 && drainCompleted(s) {
+			t.Fatalf("drain completed while the shared instance is still in use")
+		}
+	}
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete")
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	platform_client2 "github.com/AliyunContainerService/scaler/go/pkg/platform_client"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+// fakePlatform 在内存中模拟平台，记录 slot 的创建和销毁
+type fakePlatform struct {
+	mu sync.Mutex
+	// 未销毁的 slot
+	slots map[string]bool
+	// 调用 createErr/initErr 返回的错误，nil 表示成功
+	createErr error
+	initErr   error
+	pingErr   error
+	// CreateSlot 和 Init 的耗时
+	createDelay  time.Duration
+	initDelay    time.Duration
+	destroyDelay time.Duration
+	// 新建 slot 的网络等级
+	networkTier string
+	// 依次作为 Init 返回实例的 InitDurationInMs，用完后为 0
+	initDurations []int64
+	// 接下来失败的 CreateSlot 调用次数，失败时返回 createErr 或默认错误
+	createFailures int
+
+	nextId   int64
+	creates  int64
+	inits    int64
+	destroys int64
+	pings    int64
+	// 已经返回的 DestroySLot 调用次数，及其中因 ctx 结束而返回的次数
+	destroyReturns  int64
+	destroyCanceled int64
+}
+
+func newFakePlatform() *fakePlatform {
+	return &fakePlatform{slots: make(map[string]bool)}
+}
+
+func (p *fakePlatform) CreateSlot(ctx context.Context, requestId string, slotResourceConfig *model2.SlotResourceConfig) (*model2.Slot, error) {
+	atomic.AddInt64(&p.creates, 1)
+	if p.createDelay > 0 {
+		select {
+		case <-time.After(p.createDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.createFailures > 0 {
+		p.createFailures--
+		return nil, errors.New("injected create failure")
+	}
+	if p.createErr != nil {
+		return nil, p.createErr
+	}
+	return p.newSlotLocked(slotResourceConfig), nil
+}
+
+// 分配一个新的 slot，调用方需持有 mu
+func (p *fakePlatform) newSlotLocked(slotResourceConfig *model2.SlotResourceConfig) *model2.Slot {
+	p.nextId++
+	id := fmt.Sprintf("slot-%d", p.nextId)
+	p.slots[id] = true
+	return &model2.Slot{
+		Slot: pb.Slot{
+			Id:             id,
+			ResourceConfig: &slotResourceConfig.ResourceConfig,
+			CreateTime:     uint64(time.Now().UnixMilli()),
+			NetworkTier:    p.networkTier,
+		},
+	}
+}
+
+func (p *fakePlatform) DestroySLot(ctx context.Context, requestId, slotId, reason string) error {
+	atomic.AddInt64(&p.destroys, 1)
+	defer atomic.AddInt64(&p.destroyReturns, 1)
+	if p.destroyDelay > 0 {
+		select {
+		case <-time.After(p.destroyDelay):
+		case <-ctx.Done():
+			atomic.AddInt64(&p.destroyCanceled, 1)
+			return ctx.Err()
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.slots, slotId)
+	return nil
+}
+
+func (p *fakePlatform) Init(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta) (*model2.Instance, error) {
+	atomic.AddInt64(&p.inits, 1)
+	if p.initDelay > 0 {
+		select {
+		case <-time.After(p.initDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	p.mu.Lock()
+	err := p.initErr
+	var initDuration int64
+	if err == nil && len(p.initDurations) > 0 {
+		initDuration, p.initDurations = p.initDurations[0], p.initDurations[1:]
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	instance := &model2.Instance{}
+	instance.InitDurationInMs = initDuration
+	instance.Id = instanceId
+	instance.Slot = slot
+	instance.Meta = meta
+	instance.LastIdleTime = time.Now()
+	instance.InstanceCapacity = 1
+	instance.NetworkTier = slot.NetworkTier
+	return instance, nil
+}
+
+func (p *fakePlatform) Ping(ctx context.Context) error {
+	atomic.AddInt64(&p.pings, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pingErr
+}
+
+func (p *fakePlatform) Close() error {
+	return nil
+}
+
+func (p *fakePlatform) setInitErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.initErr = err
+}
+
+func (p *fakePlatform) setCreateErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.createErr = err
+}
+
+// 让接下来的 n 次 CreateSlot 失败
+func (p *fakePlatform) failCreates(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.createFailures = n
+}
+
+func (p *fakePlatform) setNetworkTier(tier string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.networkTier = tier
+}
+
+func (p *fakePlatform) setInitDurations(durations ...int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.initDurations = durations
+}
+
+func (p *fakePlatform) setPingErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pingErr = err
+}
+
+// 平台上仍然存在的 slot 数
+func (p *fakePlatform) liveSlots() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.slots)
+}
+
+// 替换 newSimple 创建的平台客户端，测试中在 Simple 启动后台循环前生效
+func withPlatformClient(client platform_client2.Client) Option {
+	return func(s *Simple) {
+		s.platformClient.Close()
+		s.platformClient = client
+	}
+}
+
+func testMeta() *model2.Meta {
+	return &model2.Meta{
+		Meta: pb.Meta{
+			Key:           "app",
+			Runtime:       "go",
+			TimeoutInSecs: 10,
+			MemoryInMb:    128,
+		},
+	}
+}
+
+// 测试用配置：不探测平台、不重试、不检查存活，gc 间隔足够长，避免干扰断言
+func testConfig() *config.Config {
+	cfg := *config.DefaultConfig
+	cfg.StartupTimeout = 0
+	cfg.LivenessTimeout = 0
+	cfg.MaxCreateRetries = 0
+	cfg.GcInterval = time.Hour
+	cfg.ShutdownTimeout = 5 * time.Second
+	return &cfg
+}
+
+func newTestSimple(tb testing.TB, cfg *config.Config, platform platform_client2.Client, opts ...Option) *Simple {
+	tb.Helper()
+	opts = append([]Option{
+		withPlatformClient(platform),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}, opts...)
+	s := newSimple(testMeta(), cfg, opts...)
+	tb.Cleanup(func() {
+		s.Close(context.Background())
+	})
+	return s
+}
+
+func assignRequest(requestId string) *pb.AssignRequest {
+	meta := testMeta()
+	return &pb.AssignRequest{
+		RequestId: requestId,
+		Timestamp: uint64(time.Now().UnixMilli()),
+		MetaData:  &meta.Meta,
+	}
+}
+
+func idleRequest(reply *pb.AssignReply, needDestroy bool) *pb.IdleRequest {
+	return &pb.IdleRequest{
+		Assigment: reply.Assigment,
+		Result:    &pb.Result{NeedDestroy: &needDestroy},
+	}
+}
+
+// 在 timeout 内等待 cond 成立
+func waitFor(tb testing.TB, timeout time.Duration, cond func() bool) {
+	tb.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			tb.Fatalf("condition not met in %s", timeout)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// 按顺序归还实例，每次等待实例回到空闲队列，最后归还的实例位于空闲队列队首
+func idleInOrder(tb testing.TB, s *Simple, replies ...*pb.AssignReply) {
+	tb.Helper()
+	for _, reply := range replies {
+		want := s.Stats().TotalIdleInstance + 1
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			tb.Fatalf("idle: %v", err)
+		}
+		waitFor(tb, time.Second, func() bool { return s.Stats().TotalIdleInstance == want })
+	}
+}
+
+// 依次分配请求并返回结果，任一请求失败时结束测试
+func assignAll(tb testing.TB, s *Simple, requests ...*pb.AssignRequest) []*pb.AssignReply {
+	tb.Helper()
+	replies := make([]*pb.AssignReply, 0, len(requests))
+	for _, request := range requests {
+		reply, err := s.Assign(context.Background(), request)
+		if err != nil {
+			tb.Fatalf("assign %s: %v", request.RequestId, err)
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+// fakeGroupPlatform 支持 CreateSlotGroup 的 fakePlatform，groupErr 在创建 Simple 之前设置
+type fakeGroupPlatform struct {
+	*fakePlatform
+	groupErr   error
+	groupCalls int64
+}
+
+func newFakeGroupPlatform() *fakeGroupPlatform {
+	return &fakeGroupPlatform{fakePlatform: newFakePlatform()}
+}
+
+func (p *fakeGroupPlatform) CreateSlotGroup(ctx context.Context, requestId string, count int, slotResourceConfig *model2.SlotResourceConfig) ([]*model2.Slot, error) {
+	atomic.AddInt64(&p.groupCalls, 1)
+	if p.createDelay > 0 {
+		select {
+		case <-time.After(p.createDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if p.groupErr != nil {
+		return nil, p.groupErr
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slots := make([]*model2.Slot, 0, count)
+	for i := 0; i < count; i++ {
+		slots = append(slots, p.newSlotLocked(slotResourceConfig))
+	}
+	return slots, nil
+}
+
+// fakeResetPlatform 支持 ResetSlot 的 fakePlatform，重置成功的 slot 仍然存在
+type fakeResetPlatform struct {
+	*fakePlatform
+	resetErr error
+	resets   int64
+}
+
+func newFakeResetPlatform() *fakeResetPlatform {
+	return &fakeResetPlatform{fakePlatform: newFakePlatform()}
+}
+
+func (p *fakeResetPlatform) ResetSlot(ctx context.Context, requestId, slotId string) error {
+	atomic.AddInt64(&p.resets, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resetErr != nil {
+		return p.resetErr
+	}
+	if !p.slots[slotId] {
+		return fmt.Errorf("slot %s not found", slotId)
+	}
+	return nil
+}
+
+func (p *fakeResetPlatform) setResetErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetErr = err
+}
+
+// fakeRecyclePlatform 支持 ResetInstance 的 fakePlatform，记录被重置的 slot
+type fakeRecyclePlatform struct {
+	*fakePlatform
+	recycleErr    error
+	recycledSlots []string
+}
+
+func newFakeRecyclePlatform() *fakeRecyclePlatform {
+	return &fakeRecyclePlatform{fakePlatform: newFakePlatform()}
+}
+
+func (p *fakeRecyclePlatform) ResetInstance(ctx context.Context, requestId, slotId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recycledSlots = append(p.recycledSlots, slotId)
+	if p.recycleErr != nil {
+		return p.recycleErr
+	}
+	if !p.slots[slotId] {
+		return fmt.Errorf("slot %s not found", slotId)
+	}
+	return nil
+}
+
+func (p *fakeRecyclePlatform) setRecycleErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recycleErr = err
+}
+
+func (p *fakeRecyclePlatform) recycled() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.recycledSlots...)
+}
+
+// fakeTagPlatform 支持 InitWithTags 的 fakePlatform，实例带上初始化时的标签
+type fakeTagPlatform struct {
+	*fakePlatform
+}
+
+func newFakeTagPlatform() *fakeTagPlatform {
+	return &fakeTagPlatform{fakePlatform: newFakePlatform()}
+}
+
+func (p *fakeTagPlatform) InitWithTags(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta, tags map[string]string) (*model2.Instance, error) {
+	instance, err := p.Init(ctx, requestId, instanceId, slot, meta)
+	if err != nil {
+		return nil, err
+	}
+	instance.Tags = make(map[string]string, len(tags))
+	for key, value := range tags {
+		instance.Tags[key] = value
+	}
+	return instance, nil
+}
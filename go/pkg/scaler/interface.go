@@ -15,19 +15,75 @@ package scaler
 
 import (
 	"context"
+	"time"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 )
 
 type Stats struct {
 	TotalInstance     int
 	TotalIdleInstance int
+	AllocatedMemoryMb int64
+	// 平台调用熔断器的状态
+	CircuitState string
+	// 当前生效的 gc 间隔
+	GcInterval time.Duration
+}
+
+// DetailedStats 在 Stats 的基础上附带最近成功 Assign 的耗时分位数
+type DetailedStats struct {
+	Stats
+	AssignP50 time.Duration
+	AssignP95 time.Duration
+	AssignP99 time.Duration
+	// 创建成功的实例的 Init 耗时分布，key 是分桶的名字
+	InitDurationHistogram map[string]int64
+	// 请求速率窗口内的平均每秒请求数和峰值
+	CurrentRPS float64
+	PeakRPS    float64
+}
+
+// ScalingMetrics 提供给外部 autoscaler 的伸缩指标
+type ScalingMetrics struct {
+	// ceil(requestCostTime * rps * safetyFactor)
+	DesiredInstances int64
+	// 已初始化和创建中的实例数
+	CurrentInstances int64
+	// 空闲队列中可以立即接收请求的实例数
+	ReadyInstances int64
+	// 等待实例的请求数
+	QueueDepth int64
+}
+
+// InstanceDetail 单个实例的调试信息
+type InstanceDetail struct {
+	InstanceId       string                 `json:"instanceId"`
+	SlotId           string                 `json:"slotId"`
+	MetaKey          string                 `json:"metaKey"`
+	NetworkTier      string                 `json:"networkTier"`
+	Tags             map[string]string      `json:"tags,omitempty"`
+	UsedSlots        int32                  `json:"usedSlots"`
+	InstanceCapacity int                    `json:"instanceCapacity"`
+	PendingEviction  bool                   `json:"pendingEviction"`
+	LastIdleTime     time.Time              `json:"lastIdleTime"`
+	CustomData       map[string]interface{} `json:"customData,omitempty"`
 }
 
 type Scaler interface {
 	Assign(ctx context.Context, request *pb.AssignRequest) (*pb.AssignReply, error)
 	Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error)
+	// 一次分配 count 个不同的实例，全部成功或全部失败
+	BatchAssign(ctx context.Context, request *pb.BatchAssignRequest) (*pb.BatchAssignReply, error)
 	Stats() Stats
 	Clear(rate float64)
 	CheckLive() bool
+	GetScalingMetrics() ScalingMetrics
+	Close(ctx context.Context) error
+	InstanceMeta() *model2.Meta
+	// Drain 之后拒绝新的 Assign，IsDraining 返回是否已经调用过 Drain
+	Drain()
+	IsDraining() bool
+	// 下线中且已分配的请求全部结束、实例全部回收后关闭
+	DrainComplete() <-chan struct{}
 }
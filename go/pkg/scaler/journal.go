@@ -0,0 +1,241 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+const (
+	JournalEventAssign = "assign"
+	JournalEventIdle   = "idle"
+
+	// 写入 goroutine 来不及处理时最多缓存的记录数，超出后丢弃
+	journalBufferSize = 4096
+	// 回放时单次调用的超时时间
+	replayCallTimeout = 30 * time.Second
+)
+
+// JournalRecord 请求日志的一行，包含回放 Assign 和 Idle 需要的请求字段
+type JournalRecord struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	RequestId  string    `json:"requestId"`
+	InstanceId string    `json:"instanceId,omitempty"`
+	// 成功时为空，失败时为返回的错误
+	Error string `json:"error,omitempty"`
+	// Assign 请求的 meta
+	MetaKey       string `json:"metaKey,omitempty"`
+	Runtime       string `json:"runtime,omitempty"`
+	MemoryInMb    uint64 `json:"memoryInMb,omitempty"`
+	TimeoutInSecs uint32 `json:"timeoutInSecs,omitempty"`
+	// Idle 请求是否要求销毁实例
+	NeedDestroy bool `json:"needDestroy,omitempty"`
+}
+
+// Journal 把 Assign 和 Idle 的调用结果以 JSON 行追加写入文件，用于排查问题时按顺序回放
+// 写入由后台 goroutine 完成，不阻塞请求；缓冲区满时丢弃记录并计数
+type Journal struct {
+	path    string
+	file    *os.File
+	records chan JournalRecord
+	dropped uint64
+	// 保护 records 的关闭，Close 之后的记录直接丢弃
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// OpenJournal 以追加方式打开 path 并启动写入 goroutine
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{path: path, file: file, records: make(chan JournalRecord, journalBufferSize)}
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.writeLoop()
+	}()
+	return j, nil
+}
+
+// 每条记录单独写入，多个 scaler 追加同一个文件时行不会交错
+func (j *Journal) writeLoop() {
+	for record := range j.records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			continue
+		}
+		j.file.Write(append(line, '\n'))
+	}
+}
+
+func (j *Journal) record(record JournalRecord) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.closed {
+		return
+	}
+	select {
+	case j.records <- record:
+	default:
+		atomic.AddUint64(&j.dropped, 1)
+	}
+}
+
+// Dropped 缓冲区已满而丢弃的记录数
+func (j *Journal) Dropped() uint64 {
+	return atomic.LoadUint64(&j.dropped)
+}
+
+// Close 写完已缓存的记录后关闭文件
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	if j.closed {
+		j.mu.Unlock()
+		return nil
+	}
+	j.closed = true
+	close(j.records)
+	j.mu.Unlock()
+	j.wg.Wait()
+	return j.file.Close()
+}
+
+// WithJournal 把 Assign 和 Idle 的调用记录写入 path，打开失败时只输出日志，不记录
+func WithJournal(path string) Option {
+	return func(s *Simple) {
+		journal, err := OpenJournal(path)
+		if err != nil {
+			s.logger.Error("open journal failed", "path", path, "error", err)
+			return
+		}
+		s.journal = journal
+	}
+}
+
+func (s *Simple) journalAssign(request *pb.AssignRequest, reply *pb.AssignReply, err error) {
+	if s.journal == nil || request.DryRun {
+		return
+	}
+	record := JournalRecord{
+		Time:          time.Now(),
+		Event:         JournalEventAssign,
+		RequestId:     request.RequestId,
+		MetaKey:       request.MetaData.GetKey(),
+		Runtime:       request.MetaData.GetRuntime(),
+		MemoryInMb:    request.MetaData.GetMemoryInMb(),
+		TimeoutInSecs: request.MetaData.GetTimeoutInSecs(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.InstanceId = reply.GetAssigment().GetInstanceId()
+	}
+	s.journal.record(record)
+}
+
+func (s *Simple) journalIdle(request *pb.IdleRequest, err error) {
+	if s.journal == nil {
+		return
+	}
+	record := JournalRecord{
+		Time:        time.Now(),
+		Event:       JournalEventIdle,
+		RequestId:   request.GetAssigment().GetRequestId(),
+		InstanceId:  request.GetAssigment().GetInstanceId(),
+		MetaKey:     request.GetAssigment().GetMetaKey(),
+		NeedDestroy: request.GetResult().GetNeedDestroy(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	s.journal.record(record)
+}
+
+// ReplayJournal 按顺序把 path 中的记录作为 Assign 和 Idle 调用发给 scaler
+// 平台调用由 scaler 自己的平台客户端完成，回放时应让 ClientAddr 指向模拟的平台
+// 回放分配到的实例 id 与记录中的不同，Idle 按记录中的 instance id 映射到回放时分配的实例；
+// 原本失败的调用和找不到对应 Assign 的 Idle 不回放。调用一个接一个执行，原本并发等待实例的 Assign 可能等到超时
+func ReplayJournal(path string, scaler Scaler) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// 记录中的 instance id 到回放时分配的 instance id
+	instances := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var record JournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("journal %s line %d: %w", path, line, err)
+		}
+		if record.Error != "" {
+			continue
+		}
+		switch record.Event {
+		case JournalEventAssign:
+			ctx, cancel := context.WithTimeout(context.Background(), replayCallTimeout)
+			reply, err := scaler.Assign(ctx, &pb.AssignRequest{
+				RequestId: record.RequestId,
+				Timestamp: uint64(record.Time.UnixMilli()),
+				MetaData: &pb.Meta{
+					Key:           record.MetaKey,
+					Runtime:       record.Runtime,
+					MemoryInMb:    record.MemoryInMb,
+					TimeoutInSecs: record.TimeoutInSecs,
+				},
+			})
+			cancel()
+			if err != nil {
+				return fmt.Errorf("journal %s line %d: replay assign %s: %w", path, line, record.RequestId, err)
+			}
+			instances[record.InstanceId] = reply.Assigment.InstanceId
+		case JournalEventIdle:
+			instanceId, ok := instances[record.InstanceId]
+			if !ok {
+				continue
+			}
+			delete(instances, record.InstanceId)
+			ctx, cancel := context.WithTimeout(context.Background(), replayCallTimeout)
+			_, err := scaler.Idle(ctx, &pb.IdleRequest{
+				Assigment: &pb.Assignment{
+					RequestId:  record.RequestId,
+					MetaKey:    record.MetaKey,
+					InstanceId: instanceId,
+				},
+				Result: &pb.Result{NeedDestroy: &record.NeedDestroy},
+			})
+			cancel()
+			if err != nil {
+				return fmt.Errorf("journal %s line %d: replay idle %s: %w", path, line, record.RequestId, err)
+			}
+		default:
+			return fmt.Errorf("journal %s line %d: unknown event %q", path, line, record.Event)
+		}
+	}
+	return scanner.Err()
+}
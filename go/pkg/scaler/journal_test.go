@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 关闭日志并按行读出全部记录
+func readJournal(tb testing.TB, s *Simple, path string) []JournalRecord {
+	tb.Helper()
+	if err := s.journal.Close(); err != nil {
+		tb.Fatalf("close journal: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("read journal: %v", err)
+	}
+	var records []JournalRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record JournalRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			tb.Fatalf("malformed record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// 成功和失败的调用都按顺序记录，dry run 不记录
+func TestJournalRecordsAssignAndIdle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform, WithJournal(path))
+	reply, err := s.Assign(context.Background(), assignRequest("a"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	dryRun := assignRequest("dry")
+	dryRun.DryRun = true
+	if _, err := s.Assign(context.Background(), dryRun); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	platform.setCreateErr(errors.New("create failed"))
+	if _, err := s.Assign(context.Background(), assignRequest("b")); err == nil {
+		t.Fatalf("assign should fail while create fails")
+	}
+
+	records := readJournal(t, s, path)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3: %+v", len(records), records)
+	}
+	assign, idle, failed := records[0], records[1], records[2]
+	if assign.Event != JournalEventAssign || assign.RequestId != "a" || assign.InstanceId != reply.Assigment.InstanceId ||
+		assign.MetaKey != "app" || assign.Runtime != "go" || assign.MemoryInMb != 128 || assign.TimeoutInSecs != 10 || assign.Error != "" {
+		t.Fatalf("unexpected assign record %+v", assign)
+	}
+	if idle.Event != JournalEventIdle || idle.RequestId != "a" || idle.InstanceId != reply.Assigment.InstanceId ||
+		idle.MetaKey != "app" || !idle.NeedDestroy || idle.Error != "" {
+		t.Fatalf("unexpected idle record %+v", idle)
+	}
+	if failed.Event != JournalEventAssign || failed.RequestId != "b" || failed.InstanceId != "" || failed.Error == "" {
+		t.Fatalf("unexpected failed assign record %+v", failed)
+	}
+	if failed.Time.Before(assign.Time) {
+		t.Fatalf("records out of order: %v after %v", assign.Time, failed.Time)
+	}
+}
+
+// 关闭后的记录直接丢弃，不计入 Dropped
+func TestJournalRecordAfterClose(t *testing.T) {
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal"))
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("close journal: %v", err)
+	}
+	journal.record(JournalRecord{Event: JournalEventAssign, RequestId: "late"})
+	if journal.Dropped() != 0 {
+		t.Fatalf("got %d dropped, want 0", journal.Dropped())
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+}
+
+// 回放到新的 scaler 上重现相同的创建和销毁
+func TestReplayJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	original := newFakePlatform()
+	s := newTestSimple(t, testConfig(), original, WithJournal(path))
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"), assignRequest("c"))
+	if _, err := s.Idle(context.Background(), idleRequest(replies[0], false)); err != nil {
+		t.Fatalf("idle a: %v", err)
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(replies[1], true)); err != nil {
+		t.Fatalf("idle b: %v", err)
+	}
+	// 失败的调用不回放
+	if _, err := s.Idle(context.Background(), idleRequest(replies[1], false)); err == nil {
+		t.Fatalf("second idle of b should fail")
+	}
+	readJournal(t, s, path)
+
+	replayed := newFakePlatform()
+	r := newTestSimple(t, testConfig(), replayed)
+	if err := ReplayJournal(path, r); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := atomic.LoadInt64(&replayed.creates); got != 3 {
+		t.Fatalf("replay created %d slots, want 3", got)
+	}
+	waitFor(t, time.Second, func() bool {
+		return atomic.LoadInt64(&replayed.destroys) == 1 && len(idleIds(r)) == 1
+	})
+	r.instancesMu.Lock()
+	instances := len(r.instances)
+	r.instancesMu.Unlock()
+	if instances != 2 {
+		t.Fatalf("replay left %d instances, want 2", instances)
+	}
+}
+
+// 原本失败的 Assign 不回放
+func TestReplayJournalSkipsFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	record, err := json.Marshal(JournalRecord{Event: JournalEventAssign, RequestId: "a", MetaKey: "app", Error: "create failed"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.WriteFile(path, append(record, '\n'), 0o644); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+	platform := newFakePlatform()
+	if err := ReplayJournal(path, newTestSimple(t, testConfig(), platform)); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := atomic.LoadInt64(&platform.creates); got != 0 {
+		t.Fatalf("replay created %d slots for a failed assign", got)
+	}
+}
+
+func TestReplayJournalMalformed(t *testing.T) {
+	dir := t.TempDir()
+	record, err := json.Marshal(JournalRecord{Event: JournalEventIdle, RequestId: "a", InstanceId: "unknown"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for name, content := range map[string]string{
+		"malformed":     string(record) + "\n{not json\n",
+		"unknown event": string(record) + "\n{\"event\":\"destroy\"}\n",
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-"))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write journal: %v", err)
+		}
+		s := newTestSimple(t, testConfig(), newFakePlatform())
+		err := ReplayJournal(path, s)
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("%s: got %v, want an error at line 2", name, err)
+		}
+	}
+	if err := ReplayJournal(filepath.Join(dir, "missing"), newTestSimple(t, testConfig(), newFakePlatform())); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want ErrNotExist", err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+// KedaAdapter 把 ScalingMetrics 转换为 KEDA external scaler 的 IsActive/GetMetricSpec/GetMetrics 语义，
+// 由外部的 gRPC 服务转发调用
+type KedaAdapter struct {
+	scaler     Scaler
+	metricName string
+}
+
+func NewKedaAdapter(scaler Scaler, metricName string) *KedaAdapter {
+	return &KedaAdapter{
+		scaler:     scaler,
+		metricName: metricName,
+	}
+}
+
+// IsActive 有排队请求或期望实例数大于 0 时需要保持副本
+func (a *KedaAdapter) IsActive() bool {
+	metrics := a.scaler.GetScalingMetrics()
+	return metrics.QueueDepth > 0 || metrics.DesiredInstances > 0
+}
+
+// GetMetricSpec 每个副本承载一个实例
+func (a *KedaAdapter) GetMetricSpec() (string, int64) {
+	return a.metricName, 1
+}
+
+// GetMetrics 返回期望的实例数
+func (a *KedaAdapter) GetMetrics() (string, int64) {
+	return a.metricName, a.scaler.GetScalingMetrics().DesiredInstances
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// labelIndex 空闲队列的标签索引，key 为 "标签=值"，标签取自实例进入空闲队列时 CustomData 中的字符串值
+// 之后再修改的 CustomData 不会进入索引，由 idleMu 保护
+type labelIndex struct {
+	elements map[string]map[*list.Element]struct{}
+	// 每个元素建索引时使用的 key，删除时按此清理
+	labels map[*list.Element][]string
+}
+
+func newLabelIndex() *labelIndex {
+	return &labelIndex{
+		elements: make(map[string]map[*list.Element]struct{}),
+		labels:   make(map[*list.Element][]string),
+	}
+}
+
+func labelKey(key, value string) string {
+	return key + "=" + value
+}
+
+func (idx *labelIndex) add(element *list.Element) {
+	var keys []string
+	for key, value := range element.Value.(*model2.Instance).CustomDataSnapshot() {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		k := labelKey(key, str)
+		set := idx.elements[k]
+		if set == nil {
+			set = make(map[*list.Element]struct{})
+			idx.elements[k] = set
+		}
+		set[element] = struct{}{}
+		keys = append(keys, k)
+	}
+	if len(keys) > 0 {
+		idx.labels[element] = keys
+	}
+}
+
+func (idx *labelIndex) remove(element *list.Element) {
+	for _, k := range idx.labels[element] {
+		set := idx.elements[k]
+		delete(set, element)
+		if len(set) == 0 {
+			delete(idx.elements, k)
+		}
+	}
+	delete(idx.labels, element)
+}
+
+// 返回同时带有 labels 中所有标签的元素，从最小的集合开始求交集
+func (idx *labelIndex) lookup(labels map[string]string) []*list.Element {
+	var smallest map[*list.Element]struct{}
+	for key, value := range labels {
+		set := idx.elements[labelKey(key, value)]
+		if len(set) == 0 {
+			return nil
+		}
+		if smallest == nil || len(set) < len(smallest) {
+			smallest = set
+		}
+	}
+	var result []*list.Element
+	for element := range smallest {
+		matched := true
+		for key, value := range labels {
+			if _, ok := idx.elements[labelKey(key, value)][element]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// 把实例放到空闲队列队首并建立标签索引，调用方需持有 idleMu
+func (s *Simple) pushIdleFront(instance *model2.Instance) *list.Element {
+	element := s.idleInstance.PushFront(instance)
+	s.trackIdle(element)
+	return element
+}
+
+// 记录刚插入空闲队列的元素，建立标签索引和成员关系，调用方需持有 idleMu
+func (s *Simple) trackIdle(element *list.Element) {
+	s.labelIndex.add(element)
+	s.idleMembers[element.Value.(*model2.Instance)] = element
+}
+
+// 从空闲队列删除元素并清理标签索引，调用方需持有 idleMu
+func (s *Simple) removeIdleElement(element *list.Element) {
+	s.labelIndex.remove(element)
+	delete(s.idleMembers, element.Value.(*model2.Instance))
+	s.idleInstance.Remove(element)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+	"fmt"
+	"testing"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// 5 个标签取值的个数，l4 几乎每个实例都不同
+var labelCardinality = []int{2, 3, 5, 7, 500}
+
+// 带有 5 个标签的实例，第 j 个标签的值为 i % labelCardinality[j]
+func labeledInstance(i int) *model2.Instance {
+	instance := &model2.Instance{Id: fmt.Sprintf("i%d", i)}
+	for j, n := range labelCardinality {
+		instance.SetCustomData(fmt.Sprintf("l%d", j), fmt.Sprint(i%n))
+	}
+	return instance
+}
+
+func lookupIds(idx *labelIndex, labels map[string]string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, element := range idx.lookup(labels) {
+		ids[element.Value.(*model2.Instance).Id] = true
+	}
+	return ids
+}
+
+// 多个标签取交集，删除元素后不再命中，非字符串值不进入索引
+func TestLabelIndexLookup(t *testing.T) {
+	l := list.New()
+	idx := newLabelIndex()
+	var elements []*list.Element
+	for i := 0; i < 12; i++ {
+		element := l.PushBack(labeledInstance(i))
+		idx.add(element)
+		elements = append(elements, element)
+	}
+	numeric := &model2.Instance{Id: "numeric"}
+	numeric.SetCustomData("l0", 0)
+	idx.add(l.PushBack(numeric))
+
+	// l0=0 且 l1=0 即 i 同时是 2 和 3 的倍数
+	if got := lookupIds(idx, map[string]string{"l0": "0", "l1": "0"}); len(got) != 2 || !got["i0"] || !got["i6"] {
+		t.Fatalf("unexpected intersection %v", got)
+	}
+	if got := lookupIds(idx, map[string]string{"l0": "0", "missing": "x"}); len(got) != 0 {
+		t.Fatalf("unknown label should match nothing, got %v", got)
+	}
+	idx.remove(elements[6])
+	if got := lookupIds(idx, map[string]string{"l0": "0", "l1": "0"}); len(got) != 1 || !got["i0"] {
+		t.Fatalf("removed element is still indexed: %v", got)
+	}
+	idx.remove(elements[0])
+	if _, ok := idx.elements[labelKey("l0", "0")]; !ok {
+		t.Fatalf("label shared with remaining elements was dropped")
+	}
+	if len(idx.labels) != 10 {
+		t.Fatalf("index tracks %d elements, want 10", len(idx.labels))
+	}
+}
+
+// 命中标签的空闲实例被分配后从索引中删除，归还后重新建立索引
+func TestLabelIndexFollowsIdleList(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	reply := assignAll(t, s, assignRequest("r0"))[0]
+	s.instancesMu.RLock()
+	s.instances[reply.Assigment.InstanceId].SetCustomData("zone", "a")
+	s.instancesMu.RUnlock()
+	idleInOrder(t, s, reply)
+
+	labels := map[string]string{"zone": "a"}
+	if got := assignWithHint(t, s, "hit", AssignHint{RequireLabels: labels}); got != reply.Assigment.InstanceId {
+		t.Fatalf("got instance %s, want %s", got, reply.Assigment.InstanceId)
+	}
+	s.idleMu.Lock()
+	indexed := len(s.labelIndex.lookup(labels))
+	s.idleMu.Unlock()
+	if indexed != 0 {
+		t.Fatalf("assigned instance is still indexed")
+	}
+}
+
+// 1000 个空闲实例、每个 5 个标签时，按两个标签查找的线性扫描与索引对比，第一个满足条件的实例位于第 500 个
+func BenchmarkLabelLookup(b *testing.B) {
+	l := list.New()
+	idx := newLabelIndex()
+	for i := 0; i < 1000; i++ {
+		idx.add(l.PushBack(labeledInstance(i)))
+	}
+	hint := &AssignHint{RequireLabels: map[string]string{"l0": "1", "l4": "499"}}
+	b.Run("linear scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for element := l.Front(); element != nil; element = element.Next() {
+				if hint.matches(element.Value.(*model2.Instance)) {
+					break
+				}
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			idx.lookup(hint.RequireLabels)
+		}
+	})
+}
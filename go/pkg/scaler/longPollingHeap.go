@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/heap"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// 等待空闲实例的长轮询请求
+type longPollingRequest struct {
+	ch chan *model2.Instance
+	// 请求 ctx 的 deadline，零值表示没有 deadline
+	deadline time.Time
+	// 入队序号，deadline 相同时先到先得
+	seq uint64
+	// 入队时间
+	enqueuedAt time.Time
+	// 在堆中的下标，出堆后为 -1
+	index int
+	// 为该请求创建实例失败的原因，在关闭 ch 之前设置
+	err error
+	// 实例必须带有的标签
+	tags map[string]string
+	// 请求的 meta key 和内存，FairQueueing 时用于匹配实例
+	metaKey    string
+	memoryInMb uint64
+}
+
+// longPollingHeap 按 deadline 排序的长轮询队列，deadline 最近的请求最先被满足，没有 deadline 的视为无穷远
+type longPollingHeap struct {
+	items []*longPollingRequest
+	seq   uint64
+}
+
+func newLongPollingHeap() *longPollingHeap {
+	return &longPollingHeap{}
+}
+
+func (h *longPollingHeap) Len() int {
+	return len(h.items)
+}
+
+func (h *longPollingHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if !a.deadline.Equal(b.deadline) {
+		if a.deadline.IsZero() {
+			return false
+		}
+		if b.deadline.IsZero() {
+			return true
+		}
+		return a.deadline.Before(b.deadline)
+	}
+	return a.seq < b.seq
+}
+
+func (h *longPollingHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *longPollingHeap) Push(x any) {
+	request := x.(*longPollingRequest)
+	request.index = len(h.items)
+	h.items = append(h.items, request)
+}
+
+func (h *longPollingHeap) Pop() any {
+	n := len(h.items)
+	request := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	request.index = -1
+	return request
+}
+
+// 加入一个等待请求
+func (h *longPollingHeap) push(ch chan *model2.Instance, deadline time.Time) *longPollingRequest {
+	h.seq++
+	request := &longPollingRequest{
+		ch:         ch,
+		deadline:   deadline,
+		seq:        h.seq,
+		enqueuedAt: time.Now(),
+	}
+	heap.Push(h, request)
+	return request
+}
+
+// 按 deadline 顺序取出第一个满足 match 的请求，跳过的请求保留原来的入队序号放回队列，没有满足的请求时返回 nil
+func (h *longPollingHeap) popMatching(match func(request *longPollingRequest) bool) *longPollingRequest {
+	var skipped []*longPollingRequest
+	defer func() {
+		for _, request := range skipped {
+			heap.Push(h, request)
+		}
+	}()
+	for request := h.pop(); request != nil; request = h.pop() {
+		if match(request) {
+			return request
+		}
+		skipped = append(skipped, request)
+	}
+	return nil
+}
+
+// 取出 deadline 最近的等待请求，队列为空时返回 nil
+func (h *longPollingHeap) pop() *longPollingRequest {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return heap.Pop(h).(*longPollingRequest)
+}
+
+// 删除仍在队列中的请求，请求已经出堆时返回 false
+func (h *longPollingHeap) remove(request *longPollingRequest) bool {
+	if request.index < 0 {
+		return false
+	}
+	heap.Remove(h, request.index)
+	return true
+}
+
+// FairQueueing 时实例能否满足等待的请求：meta key 相同且实例内存不小于请求的内存
+func waiterCompatible(instance *model2.Instance, waiter *longPollingRequest) bool {
+	if waiter.metaKey != "" && instance.Meta.Key != waiter.metaKey {
+		return false
+	}
+	return instance.Meta.MemoryInMb >= waiter.memoryInMb
+}
+
+// 等待最久的请求的入队时间，队列为空时返回零值
+func (h *longPollingHeap) oldestEnqueuedAt() time.Time {
+	var oldest time.Time
+	for _, request := range h.items {
+		if oldest.IsZero() || request.enqueuedAt.Before(oldest) {
+			oldest = request.enqueuedAt
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func TestLongPollingHeapOrder(t *testing.T) {
+	h := newLongPollingHeap()
+	now := time.Now()
+	noDeadline := h.push(nil, time.Time{})
+	late := h.push(nil, now.Add(3*time.Second))
+	early := h.push(nil, now.Add(time.Second))
+	// deadline 相同时按入队顺序
+	lateAgain := h.push(nil, now.Add(3*time.Second))
+
+	want := []*longPollingRequest{early, late, lateAgain, noDeadline}
+	for i, expected := range want {
+		got := h.pop()
+		if got != expected {
+			t.Fatalf("pop %d: got seq %d, want seq %d", i, got.seq, expected.seq)
+		}
+		if got.index != -1 {
+			t.Fatalf("pop %d: index %d after pop, want -1", i, got.index)
+		}
+	}
+	if h.pop() != nil {
+		t.Fatalf("pop on empty heap should return nil")
+	}
+}
+
+func TestLongPollingHeapRemove(t *testing.T) {
+	h := newLongPollingHeap()
+	now := time.Now()
+	first := h.push(nil, now.Add(time.Second))
+	second := h.push(nil, now.Add(2*time.Second))
+	third := h.push(nil, now.Add(3*time.Second))
+
+	if !h.remove(second) {
+		t.Fatalf("remove queued request should succeed")
+	}
+	if h.remove(second) {
+		t.Fatalf("remove twice should fail")
+	}
+	if got := h.pop(); got != first {
+		t.Fatalf("got seq %d, want first", got.seq)
+	}
+	if h.remove(first) {
+		t.Fatalf("remove popped request should fail")
+	}
+	if got := h.pop(); got != third {
+		t.Fatalf("got seq %d, want third", got.seq)
+	}
+}
+
+func TestLongPollingHeapPopMatching(t *testing.T) {
+	h := newLongPollingHeap()
+	now := time.Now()
+	a := h.push(nil, now.Add(time.Second))
+	a.metaKey = "a"
+	b := h.push(nil, now.Add(2*time.Second))
+	b.metaKey = "b"
+	a2 := h.push(nil, now.Add(3*time.Second))
+	a2.metaKey = "a"
+
+	got := h.popMatching(func(request *longPollingRequest) bool { return request.metaKey == "b" })
+	if got != b {
+		t.Fatalf("popMatching returned %v, want b", got)
+	}
+	// 跳过的请求放回队列，保持原来的顺序
+	if h.Len() != 2 {
+		t.Fatalf("heap len %d after popMatching, want 2", h.Len())
+	}
+	if got := h.pop(); got != a || got.seq != 1 {
+		t.Fatalf("skipped request should keep its position")
+	}
+	if got := h.popMatching(func(*longPollingRequest) bool { return false }); got != nil {
+		t.Fatalf("popMatching without match should return nil")
+	}
+	if h.Len() != 1 || h.pop() != a2 {
+		t.Fatalf("unmatched request should stay queued")
+	}
+}
+
+func TestLongPollingHeapOldestEnqueuedAt(t *testing.T) {
+	h := newLongPollingHeap()
+	if !h.oldestEnqueuedAt().IsZero() {
+		t.Fatalf("empty heap should return zero time")
+	}
+	first := h.push(nil, time.Time{})
+	h.push(nil, time.Now().Add(time.Second))
+	if got := h.oldestEnqueuedAt(); !got.Equal(first.enqueuedAt) {
+		t.Fatalf("oldest %v, want %v", got, first.enqueuedAt)
+	}
+}
+
+func TestWaiterCompatible(t *testing.T) {
+	instance := &model2.Instance{Meta: &model2.Meta{Meta: pb.Meta{Key: "a", MemoryInMb: 256}}}
+	cases := []struct {
+		waiter longPollingRequest
+		want   bool
+	}{
+		{longPollingRequest{}, true},
+		{longPollingRequest{metaKey: "a", memoryInMb: 256}, true},
+		{longPollingRequest{metaKey: "b"}, false},
+		{longPollingRequest{metaKey: "a", memoryInMb: 512}, false},
+	}
+	for i, c := range cases {
+		if got := waiterCompatible(instance, &c.waiter); got != c.want {
+			t.Fatalf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+// 只有一个实例时，deadline 最近的等待请求最先拿到归还的实例
+func TestAssignServesEarliestDeadlineFirst(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	busy, err := s.Assign(context.Background(), assignRequest("busy"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+
+	type result struct {
+		id    string
+		reply *pb.AssignReply
+	}
+	results := make(chan result, 3)
+	for _, w := range []struct {
+		id      string
+		timeout time.Duration
+	}{{"slow", 5 * time.Second}, {"urgent", 2 * time.Second}, {"normal", 3 * time.Second}} {
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		t.Cleanup(cancel)
+		id := w.id
+		queued := queueLen(s)
+		go func() {
+			reply, err := s.Assign(ctx, assignRequest(id))
+			if err != nil {
+				reply = nil
+			}
+			results <- result{id: id, reply: reply}
+		}()
+		// 依次入队，保证 seq 与上面的顺序一致
+		waitFor(t, time.Second, func() bool { return queueLen(s) == queued+1 })
+	}
+
+	reply := busy
+	for _, want := range []string{"urgent", "normal", "slow"} {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+		got := <-results
+		if got.id != want || got.reply == nil {
+			t.Fatalf("got %s (reply %v), want %s", got.id, got.reply, want)
+		}
+		reply = got.reply
+	}
+}
+
+// 当前在长轮询队列中等待的请求数
+func queueLen(s *Simple) int {
+	s.longPollingMu.Lock()
+	defer s.longPollingMu.Unlock()
+	return s.longPollingHeap.Len()
+}
+
+// FairQueueing 时归还的小内存实例跳过排在前面的大内存请求；默认按 deadline 顺序分给第一个请求
+func TestFairQueueingMatchesMemory(t *testing.T) {
+	for _, fair := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fair=%v", fair), func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MaxInstances = 2
+			cfg.FairQueueing = fair
+			s := newTestSimple(t, cfg, newFakePlatform())
+			busy := assignAll(t, s, memoryAssignRequest("small", 128), memoryAssignRequest("large", 1024))
+			small, large := busy[0].Assigment.InstanceId, busy[1].Assigment.InstanceId
+
+			results := make(map[string]chan string)
+			for _, w := range []struct {
+				id     string
+				memory uint64
+			}{{"wants-large", 1024}, {"wants-small", 128}} {
+				ch := make(chan string, 1)
+				results[w.id] = ch
+				request := memoryAssignRequest(w.id, w.memory)
+				queued := queueLen(s)
+				go func() {
+					reply, err := s.Assign(context.Background(), request)
+					if err != nil {
+						ch <- err.Error()
+						return
+					}
+					ch <- reply.Assigment.InstanceId
+				}()
+				waitFor(t, time.Second, func() bool { return queueLen(s) == queued+1 })
+			}
+
+			if _, err := s.Idle(context.Background(), idleRequest(busy[0], false)); err != nil {
+				t.Fatalf("idle: %v", err)
+			}
+			first, want := "wants-large", small
+			if fair {
+				first = "wants-small"
+			}
+			if got := receive(t, results[first]); got != want {
+				t.Fatalf("%s got %s, want the small instance %s", first, got, want)
+			}
+			if fair {
+				if _, err := s.Idle(context.Background(), idleRequest(busy[1], false)); err != nil {
+					t.Fatalf("idle: %v", err)
+				}
+				if got := receive(t, results["wants-large"]); got != large {
+					t.Fatalf("wants-large got %s, want the large instance %s", got, large)
+				}
+			}
+		})
+	}
+}
+
+func receive(tb testing.TB, ch chan string) string {
+	tb.Helper()
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(time.Second):
+		tb.Fatalf("receive timeout")
+		return ""
+	}
+}
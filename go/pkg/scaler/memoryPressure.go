@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+	"math"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// MemoryPressureLevel 内存压力等级
+type MemoryPressureLevel int
+
+const (
+	MemoryPressureLow MemoryPressureLevel = iota
+	MemoryPressureMedium
+	MemoryPressureHigh
+	MemoryPressureCritical
+)
+
+func (l MemoryPressureLevel) String() string {
+	switch l {
+	case MemoryPressureLow:
+		return "low"
+	case MemoryPressureMedium:
+		return "medium"
+	case MemoryPressureHigh:
+		return "high"
+	case MemoryPressureCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// 各压力等级需要回收的空闲实例比例
+func drainFraction(level MemoryPressureLevel) float64 {
+	switch level {
+	case MemoryPressureMedium:
+		return 0.25
+	case MemoryPressureHigh:
+		return 0.5
+	case MemoryPressureCritical:
+		return 1
+	}
+	return 0
+}
+
+// Option 创建 Simple 时的可选配置
+type Option func(s *Simple)
+
+// WithMemoryPressureChannel 监听 ch 上的内存压力信号，收到后按等级回收空闲实例
+func WithMemoryPressureChannel(ch <-chan MemoryPressureLevel) Option {
+	return func(s *Simple) {
+		s.memoryPressureCh = ch
+	}
+}
+
+// NotifyMemoryPressure 按压力等级回收空闲实例，Medium 回收 25%，High 回收 50%，Critical 全部回收
+func (s *Simple) NotifyMemoryPressure(level MemoryPressureLevel) {
+	fraction := drainFraction(level)
+	if fraction <= 0 {
+		return
+	}
+	n := s.evictIdleFraction(fraction, EvictReasonMemoryPressure)
+	s.logger.Info("evict idle instances under memory pressure", "metaKey", s.metaData.Key, "level", level.String(), "count", n)
+}
+
+// 按比例回收空闲时间最长的空闲实例，跳过仍有请求在处理的实例，返回回收数量
+func (s *Simple) evictIdleFraction(fraction float64, reason string) int {
+	n := int(math.Ceil(fraction * float64(s.Stats().TotalIdleInstance)))
+	evicted := s.DrainIdle(func(instance *model2.Instance) bool {
+		n--
+		return n >= 0
+	})
+	for _, instance := range evicted {
+		s.goDestroy(instance, reason)
+	}
+	return len(evicted)
+}
+
+// TotalIdleMemoryMb 空闲队列中实例的内存总量
+func (s *Simple) TotalIdleMemoryMb() int64 {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	return s.idleMemoryMb()
+}
+
+// 调用方需持有 idleMu
+func (s *Simple) idleMemoryMb() int64 {
+	var total int64
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		total += instanceMemoryMb(element.Value.(*model2.Instance))
+	}
+	return total
+}
+
+func instanceMemoryMb(instance *model2.Instance) int64 {
+	return int64(instance.Slot.GetResourceConfig().GetMemoryInMegabytes())
+}
+
+// 空闲实例的内存总量超过 MaxIdleMemoryMb 时，依次回收内存最大且没有请求在处理的空闲实例，返回回收数量
+// 每回收一个实例扫描一次空闲队列，不受 MinIdleInstances 和 WarmPoolSize 的限制
+func (s *Simple) evictIdleOverMemory() int {
+	limit := s.cfg().MaxIdleMemoryMb
+	if limit <= 0 {
+		return 0
+	}
+	var evicted []*model2.Instance
+	s.instancesMu.Lock()
+	s.idleMu.Lock()
+	for total := s.idleMemoryMb(); total > limit; {
+		var largest *list.Element
+		for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+			instance := element.Value.(*model2.Instance)
+			if instance.IsBusy() {
+				continue
+			}
+			if largest == nil || instanceMemoryMb(instance) > instanceMemoryMb(largest.Value.(*model2.Instance)) {
+				largest = element
+			}
+		}
+		if largest == nil {
+			break
+		}
+		instance := largest.Value.(*model2.Instance)
+		s.removeIdleElement(largest)
+		delete(s.instances, instance.Id)
+		total -= instanceMemoryMb(instance)
+		evicted = append(evicted, instance)
+	}
+	s.idleMu.Unlock()
+	s.instancesMu.Unlock()
+	if len(evicted) > 0 {
+		s.logger.Info("evict idle instances over idle memory limit", "metaKey", s.metaData.Key, "limitMb", limit, "count", len(evicted))
+	}
+	for _, instance := range evicted {
+		s.goDestroy(instance, EvictReasonMemoryPressure)
+	}
+	return len(evicted)
+}
+
+// 监听内存压力信号直到 scaler 关闭
+func (s *Simple) memoryPressureLoop() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case level, ok := <-s.memoryPressureCh:
+			if !ok {
+				return
+			}
+			s.NotifyMemoryPressure(level)
+		}
+	}
+}
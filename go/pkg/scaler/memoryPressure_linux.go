@@ -0,0 +1,111 @@
+//go:build linux
+
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroup v2 的内存 PSI 文件
+const cgroupMemoryPressureFile = "/sys/fs/cgroup/memory.pressure"
+
+// WatchCgroupMemoryPressure 每隔 interval 读取 cgroup memory.pressure，等级变化时发送到返回的 channel
+// ctx 结束后 channel 被关闭；读取失败时只在第一次失败和恢复时输出日志，没有 cgroup v2 的机器上不会每次都报错
+func WatchCgroupMemoryPressure(ctx context.Context, interval time.Duration) <-chan MemoryPressureLevel {
+	ch := make(chan MemoryPressureLevel, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := MemoryPressureLow
+		failing := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			avg10, err := readMemoryPressure(cgroupMemoryPressureFile)
+			if err != nil {
+				if !failing {
+					slog.Warn("read memory pressure failed, stop logging until it recovers", "path", cgroupMemoryPressureFile, "error", err)
+					failing = true
+				}
+				continue
+			}
+			if failing {
+				slog.Info("read memory pressure recovered", "path", cgroupMemoryPressureFile)
+				failing = false
+			}
+			level := memoryPressureLevel(avg10)
+			if level == last {
+				continue
+			}
+			last = level
+			select {
+			case ch <- level:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// 按最近 10 秒内有任务因内存阻塞的时间占比划分等级
+func memoryPressureLevel(avg10 float64) MemoryPressureLevel {
+	switch {
+	case avg10 >= 60:
+		return MemoryPressureCritical
+	case avg10 >= 30:
+		return MemoryPressureHigh
+	case avg10 >= 10:
+		return MemoryPressureMedium
+	}
+	return MemoryPressureLow
+}
+
+// 读取 "some avg10=1.23 avg60=... avg300=... total=..." 中的 avg10
+func readMemoryPressure(path string) (float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(field, "avg10="); ok {
+				return strconv.ParseFloat(value, 64)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("avg10 not found in %s", path)
+}
@@ -0,0 +1,31 @@
+//go:build !linux
+
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"time"
+)
+
+// WatchCgroupMemoryPressure 非 Linux 平台没有 cgroup PSI，返回的 channel 在 ctx 结束后关闭，不会发送任何信号
+func WatchCgroupMemoryPressure(ctx context.Context, interval time.Duration) <-chan MemoryPressureLevel {
+	ch := make(chan MemoryPressureLevel)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func TestNotifyMemoryPressure(t *testing.T) {
+	cases := []struct {
+		level MemoryPressureLevel
+		want  int
+	}{
+		{MemoryPressureLow, 0},
+		{MemoryPressureMedium, 1},
+		{MemoryPressureHigh, 2},
+		{MemoryPressureCritical, 4},
+	}
+	for _, c := range cases {
+		t.Run(c.level.String(), func(t *testing.T) {
+			recorder := &evictionRecorder{}
+			cfg := testConfig()
+			cfg.EvictionCallback = recorder.callback
+			platform := newFakePlatform()
+			s := newTestSimple(t, cfg, platform)
+			replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"), assignRequest("r3"))
+			idleInOrder(t, s, replies...)
+
+			s.NotifyMemoryPressure(c.level)
+			if n := s.Stats().TotalIdleInstance; n != 4-c.want {
+				t.Fatalf("got %d idle instances, want %d", n, 4-c.want)
+			}
+			waitFor(t, time.Second, func() bool {
+				return atomic.LoadInt64(&platform.destroys) == int64(c.want) && recorder.count(EvictReasonMemoryPressure) == c.want
+			})
+		})
+	}
+}
+
+// 通过 WithMemoryPressureChannel 传入的信号同样触发回收
+func TestMemoryPressureChannel(t *testing.T) {
+	ch := make(chan MemoryPressureLevel, 1)
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform, WithMemoryPressureChannel(ch))
+	idleInOrder(t, s, assignAll(t, s, assignRequest("r0"), assignRequest("r1"))...)
+
+	ch <- MemoryPressureCritical
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if n := s.Stats().TotalIdleInstance; n != 0 {
+		t.Fatalf("got %d idle instances, want 0", n)
+	}
+}
+
+// 空闲内存超过 MaxIdleMemoryMb 时先回收内存最大的实例，与空闲时长无关
+func TestEvictIdleOverMemory(t *testing.T) {
+	recorder := &evictionRecorder{}
+	cfg := testConfig()
+	cfg.MaxIdleMemoryMb = 400
+	cfg.EvictionCallback = recorder.callback
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, memoryAssignRequest("small", 128), memoryAssignRequest("large", 512), memoryAssignRequest("medium", 256))
+	idleInOrder(t, s, replies...)
+	if total := s.TotalIdleMemoryMb(); total != 896 {
+		t.Fatalf("got %dMB idle memory, want 896MB", total)
+	}
+
+	if n := s.evictIdleOverMemory(); n != 1 {
+		t.Fatalf("evicted %d instances, want 1", n)
+	}
+	if total := s.TotalIdleMemoryMb(); total != 384 {
+		t.Fatalf("got %dMB idle memory, want 384MB", total)
+	}
+	for _, id := range idleIds(s) {
+		if id == replies[1].Assigment.InstanceId {
+			t.Fatalf("largest instance %s should be evicted first", id)
+		}
+	}
+	waitFor(t, time.Second, func() bool {
+		return atomic.LoadInt64(&platform.destroys) == 1 && recorder.count(EvictReasonMemoryPressure) == 1
+	})
+	if n := s.evictIdleOverMemory(); n != 0 {
+		t.Fatalf("evicted %d instances under the limit, want 0", n)
+	}
+}
+
+// 还有请求在处理的多槽位实例即使内存最大也不回收
+func TestEvictIdleOverMemorySkipsBusy(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxIdleMemoryMb = 100
+	cfg.InstanceCapacity = 2
+	s := newTestSimple(t, cfg, newFakePlatform())
+	large := assignAll(t, s, memoryAssignRequest("large", 512))[0]
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	small := memoryAssignRequest("small", 128)
+	small.PoolPreference = pb.PoolPreference_Cold
+	reply := assignAll(t, s, small)[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.TotalIdleMemoryMb() == 640 })
+
+	if n := s.evictIdleOverMemory(); n != 1 {
+		t.Fatalf("evicted %d instances, want 1", n)
+	}
+	if ids := idleIds(s); len(ids) != 1 || ids[0] != large.Assigment.InstanceId {
+		t.Fatalf("busy instance should stay idle, got %v", ids)
+	}
+}
+
+// gc 循环在按时间回收之后执行内存回收，MaxIdleMemoryMb 为 0 时不限制
+func TestGcEvictsIdleOverMemory(t *testing.T) {
+	for _, limit := range []int64{0, 200} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			cfg := testConfig()
+			s := newTestSimple(t, cfg, newFakePlatform())
+			idleInOrder(t, s, assignAll(t, s, memoryAssignRequest("r0", 128), memoryAssignRequest("r1", 128))...)
+			// 实例都放回空闲队列之后再缩短 gc 间隔
+			reloaded := *cfg
+			reloaded.GcInterval = 10 * time.Millisecond
+			reloaded.MaxIdleMemoryMb = limit
+			if err := s.ReloadConfig(&reloaded); err != nil {
+				t.Fatalf("reload config: %v", err)
+			}
+			if limit == 0 {
+				time.Sleep(50 * time.Millisecond)
+				if n := len(idleIds(s)); n != 2 {
+					t.Fatalf("got %d idle instances without a limit, want 2", n)
+				}
+				return
+			}
+			waitFor(t, time.Second, func() bool { return s.TotalIdleMemoryMb() == 128 })
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "scaler"
+
+var (
+	// 所有未关闭的 Simple，采集时遍历
+	liveScalers sync.Map
+
+	instanceInitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "instance_init_duration_ms",
+		Help:      "Init duration of created instances in milliseconds.",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 12),
+	}, []string{"meta_key"})
+
+	totalInstancesDesc = prometheus.NewDesc(metricsNamespace+"_instances",
+		"Number of initialized instances.", []string{"meta_key"}, nil)
+	idleInstancesDesc = prometheus.NewDesc(metricsNamespace+"_idle_instances",
+		"Number of idle instances.", []string{"meta_key"}, nil)
+	creatingInstancesDesc = prometheus.NewDesc(metricsNamespace+"_creating_instances",
+		"Number of instances being created.", []string{"meta_key"}, nil)
+	queueDepthDesc = prometheus.NewDesc(metricsNamespace+"_long_polling_queue_depth",
+		"Number of requests waiting for an instance.", []string{"meta_key"}, nil)
+	requestCostTimeDesc = prometheus.NewDesc(metricsNamespace+"_request_cost_time_ms",
+		"EWMA of request cost time in milliseconds.", []string{"meta_key"}, nil)
+	maxRequestNumDesc = prometheus.NewDesc(metricsNamespace+"_max_request_num",
+		"Maximum number of concurrent requests observed.", []string{"meta_key"}, nil)
+)
+
+// RegisterMetrics 把所有 scaler 的指标注册到 reg，指标带 meta_key 标签
+// 同一个 meta key 的多个分片合并上报，实例数相加，请求耗时和最大并发取最大值
+func RegisterMetrics(reg prometheus.Registerer) error {
+	if err := reg.Register(metricsCollector{}); err != nil {
+		return err
+	}
+	return reg.Register(instanceInitDuration)
+}
+
+type metricsCollector struct{}
+
+func (metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- totalInstancesDesc
+	ch <- idleInstancesDesc
+	ch <- creatingInstancesDesc
+	ch <- queueDepthDesc
+	ch <- requestCostTimeDesc
+	ch <- maxRequestNumDesc
+}
+
+type metaKeyMetrics struct {
+	total, idle, creating, queueDepth int64
+	requestCostMs, maxRequestNum      float64
+}
+
+func (metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	byMetaKey := make(map[string]*metaKeyMetrics)
+	liveScalers.Range(func(key, _ any) bool {
+		s := key.(*Simple)
+		m := byMetaKey[s.metaData.Key]
+		if m == nil {
+			m = &metaKeyMetrics{}
+			byMetaKey[s.metaData.Key] = m
+		}
+		stats := s.Stats()
+		m.total += int64(stats.TotalInstance)
+		m.idle += int64(stats.TotalIdleInstance)
+		m.creating += atomic.LoadInt64(&s.creatingNum)
+		s.longPollingMu.Lock()
+		m.queueDepth += int64(s.longPollingHeap.Len())
+		s.longPollingMu.Unlock()
+		if costMs := float64(s.runtimeStatus.GetRequestCostTime().Milliseconds()); costMs > m.requestCostMs {
+			m.requestCostMs = costMs
+		}
+		if maxRequestNum := float64(s.runtimeStatus.getMaxRequestBNum()); maxRequestNum > m.maxRequestNum {
+			m.maxRequestNum = maxRequestNum
+		}
+		return true
+	})
+	for metaKey, m := range byMetaKey {
+		ch <- prometheus.MustNewConstMetric(totalInstancesDesc, prometheus.GaugeValue, float64(m.total), metaKey)
+		ch <- prometheus.MustNewConstMetric(idleInstancesDesc, prometheus.GaugeValue, float64(m.idle), metaKey)
+		ch <- prometheus.MustNewConstMetric(creatingInstancesDesc, prometheus.GaugeValue, float64(m.creating), metaKey)
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(m.queueDepth), metaKey)
+		ch <- prometheus.MustNewConstMetric(requestCostTimeDesc, prometheus.GaugeValue, m.requestCostMs, metaKey)
+		ch <- prometheus.MustNewConstMetric(maxRequestNumDesc, prometheus.GaugeValue, m.maxRequestNum, metaKey)
+	}
+}
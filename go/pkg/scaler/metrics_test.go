@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+// 使用单独的 meta key，与其他测试的 scaler 区分
+func newMetricsSimple(t *testing.T, platform *fakePlatform) *Simple {
+	t.Helper()
+	cfg := testConfig()
+	cfg.MaxInstances = 2
+	meta := &model2.Meta{Meta: pb.Meta{Key: "metrics", Runtime: "go", TimeoutInSecs: 10, MemoryInMb: 128}}
+	s := newSimple(meta, cfg, withPlatformClient(platform), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	t.Cleanup(func() {
+		s.Close(context.Background())
+	})
+	return s
+}
+
+func metricsAssignRequest(requestId string) *pb.AssignRequest {
+	return &pb.AssignRequest{
+		RequestId: requestId,
+		Timestamp: uint64(time.Now().UnixMilli()),
+		MetaData:  &pb.Meta{Key: "metrics", Runtime: "go", TimeoutInSecs: 10, MemoryInMb: 128},
+	}
+}
+
+// 返回 meta key 为 metrics 的 Init 耗时直方图的样本数和总和，直方图是全局的，需要与测试开始时的值比较
+func initDurationSamples(t *testing.T) (uint64, float64) {
+	t.Helper()
+	var metric dto.Metric
+	if err := instanceInitDuration.WithLabelValues("metrics").(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatalf("write histogram: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	baseCount, baseSum := initDurationSamples(t)
+	platform := newFakePlatform()
+	platform.setInitDurations(30, 300)
+	s := newMetricsSimple(t, platform)
+	replies := assignAll(t, s, metricsAssignRequest("a"), metricsAssignRequest("b"))
+	idleInOrder(t, s, replies[0])
+	assignAll(t, s, metricsAssignRequest("c"))
+	// 两个实例都在忙，达到 MaxInstances 后的请求进入等待队列
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Assign(ctx, metricsAssignRequest("d"))
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := RegisterMetrics(reg); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	expected := `
+# HELP scaler_creating_instances Number of instances being created.
+# TYPE scaler_creating_instances gauge
+scaler_creating_instances{meta_key="metrics"} 0
+# HELP scaler_idle_instances Number of idle instances.
+# TYPE scaler_idle_instances gauge
+scaler_idle_instances{meta_key="metrics"} 0
+# HELP scaler_instances Number of initialized instances.
+# TYPE scaler_instances gauge
+scaler_instances{meta_key="metrics"} 2
+# HELP scaler_long_polling_queue_depth Number of requests waiting for an instance.
+# TYPE scaler_long_polling_queue_depth gauge
+scaler_long_polling_queue_depth{meta_key="metrics"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"scaler_creating_instances", "scaler_idle_instances", "scaler_instances", "scaler_long_polling_queue_depth"); err != nil {
+		t.Fatalf("unexpected metrics: %v", err)
+	}
+
+	// 每个创建成功的实例记录一次 Init 耗时
+	count, sum := initDurationSamples(t)
+	if count-baseCount != 2 || sum-baseSum != 330 {
+		t.Fatalf("init duration histogram got %d samples with sum %v, want 2 and 330", count-baseCount, sum-baseSum)
+	}
+}
+
+// 多次注册同一组指标返回错误
+func TestRegisterMetricsTwice(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := RegisterMetrics(reg); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := RegisterMetrics(reg); err == nil {
+		t.Fatalf("registering twice should fail")
+	}
+}
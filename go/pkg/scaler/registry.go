@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// SimpleImplementation 默认的 Scaler 实现，ShardCount 大于 1 时为 Sharded，否则为 Simple
+const SimpleImplementation = "simple"
+
+// Factory 创建一个 Scaler 实现
+type Factory func(metaData *model2.Meta, config *config.Config, opts ...Option) Scaler
+
+// Registry 按名字注册的 Scaler 实现，用于在运行时选择或对比不同的扩缩容策略
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// DefaultRegistry New 使用的注册表，init 时注册了 SimpleImplementation
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(SimpleImplementation, newScaler)
+}
+
+// Register 以 name 注册 factory，name 已注册或 factory 为 nil 时 panic
+func (r *Registry) Register(name string, factory Factory) {
+	if factory == nil {
+		panic("scaler: register nil factory for " + name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		panic("scaler: register twice for " + name)
+	}
+	r.factories[name] = factory
+}
+
+// NewScaler 用 name 对应的实现创建 scaler
+func (r *Registry) NewScaler(name string, metaData *model2.Meta, config *config.Config, opts ...Option) (Scaler, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("scaler implementation %s is not registered", name)
+	}
+	return factory(metaData, config, opts...), nil
+}
+
+// ListImplementations 按名字排序返回已注册的实现
+func (r *Registry) ListImplementations() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	created := 0
+	factory := func(metaData *model2.Meta, config *config.Config, opts ...Option) Scaler {
+		created++
+		return nil
+	}
+	r.Register("b", factory)
+	r.Register("a", factory)
+	if got := r.ListImplementations(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("got implementations %v, want [a b]", got)
+	}
+	if _, err := r.NewScaler("a", testMeta(), testConfig()); err != nil || created != 1 {
+		t.Fatalf("new scaler: %v, created %d", err, created)
+	}
+	if _, err := r.NewScaler("missing", testMeta(), testConfig()); err == nil {
+		t.Fatalf("unregistered implementation should return an error")
+	}
+	if got := NewRegistry().ListImplementations(); len(got) != 0 {
+		t.Fatalf("new registry lists %v", got)
+	}
+}
+
+// 重复注册或注册 nil 属于编程错误，直接 panic
+func TestRegistryRegisterPanics(t *testing.T) {
+	cases := map[string]func(r *Registry){
+		"twice": func(r *Registry) {
+			factory := func(*model2.Meta, *config.Config, ...Option) Scaler { return nil }
+			r.Register("a", factory)
+			r.Register("a", factory)
+		},
+		"nil factory": func(r *Registry) { r.Register("a", nil) },
+	}
+	for name, register := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("register should panic")
+				}
+			}()
+			register(NewRegistry())
+		})
+	}
+}
+
+// 默认注册的 simple 按 ShardCount 创建 Simple 或 Sharded，New 与之相同
+func TestDefaultRegistrySimple(t *testing.T) {
+	found := false
+	for _, name := range DefaultRegistry.ListImplementations() {
+		found = found || name == SimpleImplementation
+	}
+	if !found {
+		t.Fatalf("%s is not registered by default", SimpleImplementation)
+	}
+	for shards, want := range map[int]string{1: "*scaler.Simple", 2: "*scaler.Sharded"} {
+		cfg := testConfig()
+		cfg.ShardCount = shards
+		s, err := DefaultRegistry.NewScaler(SimpleImplementation, testMeta(), cfg,
+			withPlatformClient(newFakePlatform()), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		if err != nil {
+			t.Fatalf("new scaler: %v", err)
+		}
+		t.Cleanup(func() {
+			s.(interface{ Close(context.Context) error }).Close(context.Background())
+		})
+		if got := reflect.TypeOf(s).String(); got != want {
+			t.Fatalf("shard count %d: got %s, want %s", shards, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"sync"
+	"time"
+)
+
+// 默认统计请求速率的窗口
+const defaultRequestRateWindow = 60 * time.Second
+
+// RequestRate 按秒分桶的滑动窗口请求计数，桶在记录和读取时按当前时间惰性推进，不需要后台 goroutine
+type RequestRate struct {
+	mu      sync.Mutex
+	buckets []int64
+	// 最新的桶对应的 unix 秒
+	current int64
+}
+
+// NewRequestRate 创建窗口为 window 的计数器，window 按秒取整，小于 1 秒时使用默认窗口
+func NewRequestRate(window time.Duration) *RequestRate {
+	n := int(window / time.Second)
+	if n < 1 {
+		n = int(defaultRequestRateWindow / time.Second)
+	}
+	return &RequestRate{buckets: make([]int64, n)}
+}
+
+// 推进到 sec 所在的桶，跳过的桶清零，调用方需持有 mu
+func (r *RequestRate) advance(sec int64) {
+	if sec <= r.current {
+		return
+	}
+	n := int64(len(r.buckets))
+	if sec-r.current >= n {
+		for i := range r.buckets {
+			r.buckets[i] = 0
+		}
+	} else {
+		for t := r.current + 1; t <= sec; t++ {
+			r.buckets[t%n] = 0
+		}
+	}
+	r.current = sec
+}
+
+// Add 在 t 所在的桶上计数一次，早于窗口的时间直接丢弃
+func (r *RequestRate) Add(t time.Time) {
+	sec := t.Unix()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance(sec)
+	n := int64(len(r.buckets))
+	if r.current-sec >= n {
+		return
+	}
+	r.buckets[sec%n]++
+}
+
+// Current 窗口内的平均每秒请求数
+func (r *RequestRate) Current() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance(time.Now().Unix())
+	var total int64
+	for _, count := range r.buckets {
+		total += count
+	}
+	return float64(total) / float64(len(r.buckets))
+}
+
+// Peak 窗口内请求数最多的一秒的请求数
+func (r *RequestRate) Peak() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance(time.Now().Unix())
+	var peak int64
+	for _, count := range r.buckets {
+		if count > peak {
+			peak = count
+		}
+	}
+	return float64(peak)
+}
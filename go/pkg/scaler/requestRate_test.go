@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// 等到下一秒开始，测试中的读写落在同一秒内，不会因为窗口推进丢掉最旧的桶
+func waitNextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+// 窗口内每秒 rps 个请求时 CurrentRPS 收敛到 rps，PeakRPS 为请求最多的一秒
+func TestRequestRateConverges(t *testing.T) {
+	const window, rps = 10, 5
+	r := NewRuntimeStatus("app")
+	r.SetRequestRateWindow(window * time.Second)
+	waitNextSecond()
+	now := time.Now()
+	for sec := window - 1; sec >= 0; sec-- {
+		at := now.Add(-time.Duration(sec) * time.Second)
+		for i := 0; i < rps; i++ {
+			r.AssignStart(fmt.Sprintf("r%d-%d", sec, i), at)
+		}
+	}
+	if got := r.CurrentRPS(); got != rps {
+		t.Fatalf("got current rps %v, want %v", got, float64(rps))
+	}
+	// 当前这一秒的突发只抬高峰值，平均值按整个窗口计算
+	for i := 0; i < 3*rps; i++ {
+		r.AssignStart(fmt.Sprintf("burst-%d", i), now)
+	}
+	if peak := r.PeakRPS(); peak != 4*rps {
+		t.Fatalf("got peak rps %v, want %v", peak, float64(4*rps))
+	}
+	if got, want := r.CurrentRPS(), float64(window*rps+3*rps)/window; got != want {
+		t.Fatalf("got current rps %v after the burst, want %v", got, want)
+	}
+}
+
+// 超出窗口的请求不计入，窗口按秒取整，小于 1 秒时使用默认窗口
+func TestRequestRateWindow(t *testing.T) {
+	rate := NewRequestRate(3 * time.Second)
+	waitNextSecond()
+	now := time.Now()
+	rate.Add(now.Add(-10 * time.Second))
+	if got := rate.Current(); got != 0 {
+		t.Fatalf("request older than the window was counted: %v", got)
+	}
+	rate.Add(now)
+	rate.Add(now.Add(-2 * time.Second))
+	if got := rate.Current(); got != 2.0/3 {
+		t.Fatalf("got current rps %v, want %v", got, 2.0/3)
+	}
+	// 窗口推进后旧的桶被清零
+	rate.mu.Lock()
+	rate.advance(now.Unix() + 2)
+	rate.mu.Unlock()
+	var total int64
+	for _, count := range rate.buckets {
+		total += count
+	}
+	if total != 1 {
+		t.Fatalf("got %d requests after the window moved, want only the latest", total)
+	}
+
+	if n := len(NewRequestRate(500 * time.Millisecond).buckets); n != int(defaultRequestRateWindow/time.Second) {
+		t.Fatalf("sub-second window should use the default, got %d buckets", n)
+	}
+	if n := len(NewRequestRate(2500 * time.Millisecond).buckets); n != 2 {
+		t.Fatalf("window should round down to whole seconds, got %d buckets", n)
+	}
+}
+
+// Assign 的速率反映在 DetailedStats 中，并作为 ScaleDecisionInput.CurrentRPS 传给扩容策略
+func TestRequestRateFeedsStatsAndPolicy(t *testing.T) {
+	cfg := testConfig()
+	cfg.RequestRateWindow = 2 * time.Second
+	policy := &recordingPolicy{create: true}
+	s := newTestSimple(t, cfg, newFakePlatform(), WithScalingPolicy(policy))
+	waitNextSecond()
+	for i := 0; i < 4; i++ {
+		reply := assignAll(t, s, assignRequest(fmt.Sprintf("r%d", i)))[0]
+		idleInOrder(t, s, reply)
+	}
+	stats := s.DetailedStats()
+	if stats.CurrentRPS != 2 || stats.PeakRPS != 4 {
+		t.Fatalf("got current rps %v peak %v, want 2 and 4", stats.CurrentRPS, stats.PeakRPS)
+	}
+	// 只有第一个请求需要等待创建，此时已经计入了它自己
+	if input := policy.lastInput(); input.CurrentRPS != 0.5 {
+		t.Fatalf("policy got current rps %v, want 0.5", input.CurrentRPS)
+	}
+}
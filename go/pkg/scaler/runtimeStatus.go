@@ -2,69 +2,459 @@ package scaler
 
 import (
 	"container/list"
+	"fmt"
 	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// 自适应衰减系数的取值范围和参与计算的请求数
+const (
+	minAdaptiveRctRate = 0.5
+	maxAdaptiveRctRate = 0.95
+	rctSampleSize      = 20
+	// 默认保留的 Assign 耗时样本数
+	defaultLatencySampleSize = 1000
+)
+
 type RuntimeStatus struct {
 	requestDuration   map[string]time.Time
 	requestDurationMu sync.Mutex
 	requestCostTime   time.Duration
-	rctRate           float64
+	// 各项 EWMA 的衰减系数，math.Float64bits 编码后原子读写
+	rctRate           uint64
 	requestInstance   *list.List
 	requestInstanceMu sync.Mutex
 	maxRequestNum     int64
+	// 请求进入 Assign 的时间，用于统计 Assign 本身的耗时
+	assignStart   map[string]time.Time
+	assignStartMu sync.Mutex
+	assignLatency time.Duration
+	// Idle 上报的执行统计累计值
+	executionMu    sync.Mutex
+	executionStats executionTotals
+	// 平台 CreateSlot+Init 耗时的 EWMA
+	platformCallMu       sync.Mutex
+	platformCallDuration time.Duration
+	// 请求耗时 EWMA 使用的衰减系数，开启 AdaptiveRctRate 时随请求耗时的波动调整，由 requestDurationMu 保护
+	costRate     float64
+	adaptive     bool
+	costSamples  []time.Duration
+	costSampleAt int
+	// 最近成功 Assign 的耗时，环形缓冲区，用于计算分位数
+	latencyMu       sync.Mutex
+	latencySamples  []time.Duration
+	latencyCapacity int
+	latencyAt       int
+	// 最近一次 AssignReturn 的时间，由 requestDurationMu 保护
+	lastAssignReturn time.Time
+	// 距离 lastAssignReturn 超过该时间后 AssignStart 调用 Reset，0 表示不清空
+	staleness time.Duration
+	// 创建成功的实例的 Init 耗时分布
+	initDuration InitDurationHistogram
+	// Assign 的请求速率
+	requestRate atomic.Pointer[RequestRate]
+}
+
+// Init 耗时分桶的上界，最后一个桶没有上界
+var initDurationBuckets = []struct {
+	name  string
+	upper time.Duration
+}{
+	{"<100ms", 100 * time.Millisecond},
+	{"100ms-500ms", 500 * time.Millisecond},
+	{"500ms-1s", time.Second},
+	{"1s-5s", 5 * time.Second},
+	{">5s", 0},
+}
+
+// InitDurationHistogram 按固定分桶统计实例 Init 耗时的次数，计数原子读写
+type InitDurationHistogram struct {
+	counts [5]int64
+}
+
+// Observe 把一次 Init 耗时计入对应的桶
+func (h *InitDurationHistogram) Observe(d time.Duration) {
+	for i, bucket := range initDurationBuckets {
+		if bucket.upper == 0 || d < bucket.upper {
+			atomic.AddInt64(&h.counts[i], 1)
+			return
+		}
+	}
+}
+
+// Snapshot 返回各个桶的计数，key 是桶的名字
+func (h *InitDurationHistogram) Snapshot() map[string]int64 {
+	snapshot := make(map[string]int64, len(initDurationBuckets))
+	for i, bucket := range initDurationBuckets {
+		snapshot[bucket.name] = atomic.LoadInt64(&h.counts[i])
+	}
+	return snapshot
+}
+
+type executionTotals struct {
+	count           int64
+	cpuMs           int64
+	memPeakMb       int64
+	maxMemPeakMb    int64
+	networkBytesIn  int64
+	networkBytesOut int64
 }
 
-func NewRuntimeStatus() *RuntimeStatus {
+// MetricsSnapshot 运行时统计快照
+type MetricsSnapshot struct {
+	RequestCostTime      time.Duration
+	MeanAssignLatency    time.Duration
+	MaxRequestNum        int64
+	ExecutionCount       int64
+	TotalCpuMs           int64
+	TotalMemPeakMb       int64
+	MaxMemPeakMb         int64
+	TotalNetworkBytesIn  int64
+	TotalNetworkBytesOut int64
+}
+
+// NewRuntimeStatus 创建运行时统计，衰减系数优先使用 DefaultConfig 中 metaKey 的覆盖值
+func NewRuntimeStatus(metaKey string) *RuntimeStatus {
+	rctRate := config.DefaultConfig.RctRateFor(metaKey)
 	r := &RuntimeStatus{
 		requestDuration:   make(map[string]time.Time),
 		requestDurationMu: sync.Mutex{},
-		rctRate:           config.DefaultConfig.RctRate,
+		rctRate:           math.Float64bits(rctRate),
+		costRate:          rctRate,
+		adaptive:          config.DefaultConfig.AdaptiveRctRate,
 		requestInstanceMu: sync.Mutex{},
 		requestInstance:   list.New(),
+		assignStart:       make(map[string]time.Time),
+		assignStartMu:     sync.Mutex{},
+		latencyCapacity:   defaultLatencySampleSize,
+		staleness:         config.DefaultConfig.StalenessDuration,
 	}
+	r.requestRate.Store(NewRequestRate(config.DefaultConfig.RequestRateWindow))
 	return r
 }
 
-func (r *RuntimeStatus) AssignReturn(requestId string) {
+// SetLatencySampleSize 设置保留的 Assign 耗时样本数并清空已有样本，n 小于等于 0 时使用默认值
+func (r *RuntimeStatus) SetLatencySampleSize(n int) {
+	if n <= 0 {
+		n = defaultLatencySampleSize
+	}
+	r.latencyMu.Lock()
+	defer r.latencyMu.Unlock()
+	r.latencyCapacity = n
+	r.latencySamples = nil
+	r.latencyAt = 0
+}
+
+// SetStalenessDuration 设置流量中断多久后清空统计，在开始处理请求前调用
+func (r *RuntimeStatus) SetStalenessDuration(staleness time.Duration) {
+	r.requestDurationMu.Lock()
+	defer r.requestDurationMu.Unlock()
+	r.staleness = staleness
+}
+
+// Reset 清空请求耗时 EWMA 和并发请求统计
+// 保留已分配请求的开始时间，这些请求之后 Idle 时仍按真实耗时计入新的 EWMA
+func (r *RuntimeStatus) Reset() {
+	r.requestDurationMu.Lock()
+	defer r.requestDurationMu.Unlock()
+	r.requestInstanceMu.Lock()
+	defer r.requestInstanceMu.Unlock()
+	r.requestCostTime = 0
+	r.costSamples = nil
+	r.costSampleAt = 0
+	r.maxRequestNum = 0
+	r.requestInstance.Init()
+}
+
+// ObserveAssignLatency 记录一次成功 Assign 的耗时，样本数达到上限后覆盖最旧的样本
+func (r *RuntimeStatus) ObserveAssignLatency(latency time.Duration) {
+	r.latencyMu.Lock()
+	defer r.latencyMu.Unlock()
+	if len(r.latencySamples) < r.latencyCapacity {
+		r.latencySamples = append(r.latencySamples, latency)
+		return
+	}
+	r.latencySamples[r.latencyAt] = latency
+	r.latencyAt = (r.latencyAt + 1) % r.latencyCapacity
+}
+
+// SetRctRate 原子地替换衰减系数，rate 需要在 [0,1] 之间
+// 开启 AdaptiveRctRate 时请求耗时的衰减系数仍按请求耗时的波动调整，下一次调整前使用 rate
+func (r *RuntimeStatus) SetRctRate(rate float64) error {
+	if rate < 0 || rate > 1 || math.IsNaN(rate) {
+		return fmt.Errorf("rct rate %v is out of range [0,1]", rate)
+	}
+	atomic.StoreUint64(&r.rctRate, math.Float64bits(rate))
+	r.requestDurationMu.Lock()
+	r.costRate = rate
+	r.requestDurationMu.Unlock()
+	return nil
+}
+
+// SetAdaptiveRctRate 开启或关闭按请求耗时的波动调整衰减系数，关闭时恢复为 SetRctRate 设置的值
+func (r *RuntimeStatus) SetAdaptiveRctRate(enabled bool) {
 	r.requestDurationMu.Lock()
 	defer r.requestDurationMu.Unlock()
+	if r.adaptive == enabled {
+		return
+	}
+	r.adaptive = enabled
+	r.costSamples = nil
+	r.costSampleAt = 0
+	if !enabled {
+		r.costRate = r.getRctRate()
+	}
+}
+
+func (r *RuntimeStatus) getRctRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&r.rctRate))
+}
+
+// SetRequestRateWindow 设置统计请求速率的窗口并清空已有计数
+func (r *RuntimeStatus) SetRequestRateWindow(window time.Duration) {
+	r.requestRate.Store(NewRequestRate(window))
+}
+
+// CurrentRPS 请求速率窗口内平均每秒的 Assign 数
+func (r *RuntimeStatus) CurrentRPS() float64 {
+	return r.requestRate.Load().Current()
+}
+
+// PeakRPS 请求速率窗口内 Assign 最多的一秒的 Assign 数
+func (r *RuntimeStatus) PeakRPS() float64 {
+	return r.requestRate.Load().Peak()
+}
+
+// ObserveInitDuration 记录一次成功创建实例的 Init 耗时
+func (r *RuntimeStatus) ObserveInitDuration(d time.Duration) {
+	r.initDuration.Observe(d)
+}
+
+// GetInitDurationHistogram 返回 Init 耗时各个桶的计数
+func (r *RuntimeStatus) GetInitDurationHistogram() map[string]int64 {
+	return r.initDuration.Snapshot()
+}
+
+// Percentile 返回 Assign 耗时的第 p 百分位数（p 取 0~100），没有样本时返回 0
+func (r *RuntimeStatus) Percentile(p float64) time.Duration {
+	r.latencyMu.Lock()
+	samples := make([]time.Duration, len(r.latencySamples))
+	copy(samples, r.latencySamples)
+	r.latencyMu.Unlock()
+	if len(samples) == 0 {
+		return 0
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	// nearest-rank
+	rank := int(math.Ceil(p / 100 * float64(len(samples))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(samples) {
+		rank = len(samples)
+	}
+	return samples[rank-1]
+}
+
+func (r *RuntimeStatus) P50() time.Duration {
+	return r.Percentile(50)
+}
+
+func (r *RuntimeStatus) P95() time.Duration {
+	return r.Percentile(95)
+}
+
+func (r *RuntimeStatus) P99() time.Duration {
+	return r.Percentile(99)
+}
+
+// AssignReturn 记录 Assign 返回，assigned 为 false 时请求不会再 Idle，不记录处理开始时间
+func (r *RuntimeStatus) AssignReturn(requestId string, assigned bool) {
+	r.requestDurationMu.Lock()
 	// 记录处理开始时间
-	r.requestDuration[requestId] = time.Now()
+	if assigned {
+		r.requestDuration[requestId] = time.Now()
+	}
+	r.lastAssignReturn = time.Now()
+	r.requestDurationMu.Unlock()
+
+	r.assignStartMu.Lock()
+	defer r.assignStartMu.Unlock()
+	start, ok := r.assignStart[requestId]
+	if !ok {
+		return
+	}
+	delete(r.assignStart, requestId)
+	// Assign 从进入到返回的耗时，衡量 scaler 自身开销
+	latency := time.Since(start)
+	if r.assignLatency == 0 {
+		r.assignLatency = latency
+	} else {
+		rate := r.getRctRate()
+		r.assignLatency = time.Duration(rate*float64(r.assignLatency) + (1-rate)*float64(latency))
+	}
+}
+
+// GetMeanAssignLatency Assign 耗时的 EWMA
+func (r *RuntimeStatus) GetMeanAssignLatency() time.Duration {
+	r.assignStartMu.Lock()
+	defer r.assignStartMu.Unlock()
+	return r.assignLatency
+}
+
+// ObservePlatformCall 记录一次 CreateSlot+Init 的耗时
+func (r *RuntimeStatus) ObservePlatformCall(duration time.Duration) {
+	r.platformCallMu.Lock()
+	defer r.platformCallMu.Unlock()
+	if r.platformCallDuration == 0 {
+		r.platformCallDuration = duration
+	} else {
+		rate := r.getRctRate()
+		r.platformCallDuration = time.Duration(rate*float64(r.platformCallDuration) + (1-rate)*float64(duration))
+	}
+}
+
+// GetMeanPlatformCallDuration 平台调用耗时的 EWMA
+func (r *RuntimeStatus) GetMeanPlatformCallDuration() time.Duration {
+	r.platformCallMu.Lock()
+	defer r.platformCallMu.Unlock()
+	return r.platformCallDuration
 }
 
 func (r *RuntimeStatus) IdleStart(requestId string) {
 	r.requestDurationMu.Lock()
 	defer r.requestDurationMu.Unlock()
-	// Duration
-	duration := time.Since(r.requestDuration[requestId])
+	// 没有记录开始时间的请求不计入，否则 time.Since 零值会让 EWMA 变得极大
+	startTime, ok := r.requestDuration[requestId]
+	if !ok {
+		return
+	}
+	delete(r.requestDuration, requestId)
+	duration := time.Since(startTime)
+	if r.adaptive {
+		r.adaptRctRate(duration)
+	}
 	if r.requestCostTime == 0 {
 		r.requestCostTime = duration
 	} else {
 		// 旧duration * rate + 新duration * (1 - rate)
-		r.requestCostTime = time.Duration(r.rctRate*float64(r.requestCostTime) + (1-r.rctRate)*float64(duration))
+		r.requestCostTime = time.Duration(r.costRate*float64(r.requestCostTime) + (1-r.costRate)*float64(duration))
 	}
 }
 
+// 根据最近 rctSampleSize 个请求耗时的变异系数调整衰减系数，调用方需持有 requestDurationMu
+// 变异系数为 0 时使用 maxAdaptiveRctRate，大于等于 1 时使用 minAdaptiveRctRate，中间线性插值
+func (r *RuntimeStatus) adaptRctRate(duration time.Duration) {
+	if len(r.costSamples) < rctSampleSize {
+		r.costSamples = append(r.costSamples, duration)
+	} else {
+		r.costSamples[r.costSampleAt] = duration
+		r.costSampleAt = (r.costSampleAt + 1) % rctSampleSize
+	}
+	if len(r.costSamples) < 2 {
+		return
+	}
+	var sum float64
+	for _, sample := range r.costSamples {
+		sum += float64(sample)
+	}
+	mean := sum / float64(len(r.costSamples))
+	if mean <= 0 {
+		return
+	}
+	var variance float64
+	for _, sample := range r.costSamples {
+		variance += (float64(sample) - mean) * (float64(sample) - mean)
+	}
+	cv := math.Sqrt(variance/float64(len(r.costSamples))) / mean
+	r.costRate = maxAdaptiveRctRate - math.Min(cv, 1)*(maxAdaptiveRctRate-minAdaptiveRctRate)
+}
+
+// GetRctRate 请求耗时 EWMA 当前使用的衰减系数
+func (r *RuntimeStatus) GetRctRate() float64 {
+	r.requestDurationMu.Lock()
+	defer r.requestDurationMu.Unlock()
+	return r.costRate
+}
+
 func (r *RuntimeStatus) GetRequestCostTime() time.Duration {
 	r.requestDurationMu.Lock()
 	defer r.requestDurationMu.Unlock()
 	return r.requestCostTime
 }
 
-func (r *RuntimeStatus) AssignStart(timeStamp time.Time) {
+// ObserveExecution 累计一次请求的执行统计
+func (r *RuntimeStatus) ObserveExecution(stats *model2.ExecutionStats) {
+	r.executionMu.Lock()
+	defer r.executionMu.Unlock()
+	r.executionStats.count++
+	r.executionStats.cpuMs += stats.CpuMs
+	r.executionStats.memPeakMb += stats.MemPeakMb
+	if stats.MemPeakMb > r.executionStats.maxMemPeakMb {
+		r.executionStats.maxMemPeakMb = stats.MemPeakMb
+	}
+	r.executionStats.networkBytesIn += stats.NetworkBytesIn
+	r.executionStats.networkBytesOut += stats.NetworkBytesOut
+}
+
+// MetricsSnapshot 返回当前的统计快照
+func (r *RuntimeStatus) MetricsSnapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		RequestCostTime:   r.GetRequestCostTime(),
+		MeanAssignLatency: r.GetMeanAssignLatency(),
+	}
+	r.requestInstanceMu.Lock()
+	snapshot.MaxRequestNum = r.maxRequestNum
+	r.requestInstanceMu.Unlock()
+	r.executionMu.Lock()
+	snapshot.ExecutionCount = r.executionStats.count
+	snapshot.TotalCpuMs = r.executionStats.cpuMs
+	snapshot.TotalMemPeakMb = r.executionStats.memPeakMb
+	snapshot.MaxMemPeakMb = r.executionStats.maxMemPeakMb
+	snapshot.TotalNetworkBytesIn = r.executionStats.networkBytesIn
+	snapshot.TotalNetworkBytesOut = r.executionStats.networkBytesOut
+	r.executionMu.Unlock()
+	return snapshot
+}
+
+func (r *RuntimeStatus) AssignStart(requestId string, timeStamp time.Time) {
+	r.requestDurationMu.Lock()
+	stale := r.staleness > 0 && !r.lastAssignReturn.IsZero() && timeStamp.Sub(r.lastAssignReturn) > r.staleness
+	if stale {
+		// 同一波请求只清空一次
+		r.lastAssignReturn = timeStamp
+	}
+	r.requestDurationMu.Unlock()
+	if stale {
+		// 流量中断太久，之前的统计已经不能反映当前负载
+		r.Reset()
+	}
+	r.assignStartMu.Lock()
+	r.assignStart[requestId] = timeStamp
+	r.assignStartMu.Unlock()
+	r.requestRate.Load().Add(timeStamp)
+
 	requestCostTime := r.GetRequestCostTime()
 	r.requestInstanceMu.Lock()
 	defer r.requestInstanceMu.Unlock()
-	r.requestInstance.PushBack(timeStamp)
+	last := r.requestInstance.PushBack(timeStamp)
 	// 遍历request队列，timeStamp>requestCostTime则删除
-	for element := r.requestInstance.Front(); element.Value.(time.Time) != timeStamp; element = element.Next() {
+	// Remove 之后 element.Next() 返回 nil，需要先取出下一个元素
+	for element := r.requestInstance.Front(); element != last; {
+		next := element.Next()
 		elemTimeStamp := element.Value.(time.Time)
 		if time.Since(elemTimeStamp) > requestCostTime {
 			r.requestInstance.Remove(element)
 		}
+		element = next
 	}
 	//记录当前请求数量
 	requestNum := r.requestInstance.Len()
@@ -83,11 +473,13 @@ func (r *RuntimeStatus) getCurrentRequestBNum() int64 {
 	r.requestInstanceMu.Lock()
 	defer r.requestInstanceMu.Unlock()
 	// 遍历request队列，timeStamp>requestCostTime则删除
-	for element := r.requestInstance.Front(); element != nil; element = element.Next() {
+	for element := r.requestInstance.Front(); element != nil; {
+		next := element.Next()
 		elemTimeStamp := element.Value.(time.Time)
 		if time.Since(elemTimeStamp) > requestCostTime {
 			r.requestInstance.Remove(element)
 		}
+		element = next
 	}
 	//记录当前请求数量
 	requestNum := int64(r.requestInstance.Len())
@@ -97,3 +489,65 @@ func (r *RuntimeStatus) getCurrentRequestBNum() int64 {
 	}
 	return requestNum
 }
+
+// 最近一个请求耗时窗口内的请求到达速率
+func (r *RuntimeStatus) getRequestRate() float64 {
+	requestCostTime := r.GetRequestCostTime()
+	if requestCostTime <= 0 {
+		return 0
+	}
+	return float64(r.getCurrentRequestBNum()) / requestCostTime.Seconds()
+}
+
+// SnapshotToProto 导出各项 EWMA、最大并发数和执行统计
+func (r *RuntimeStatus) SnapshotToProto() *pb.RuntimeStatusProto {
+	snapshot := r.MetricsSnapshot()
+	return &pb.RuntimeStatusProto{
+		RequestCostTimeNs:      int64(snapshot.RequestCostTime),
+		AssignLatencyNs:        int64(snapshot.MeanAssignLatency),
+		PlatformCallDurationNs: int64(r.GetMeanPlatformCallDuration()),
+		MaxRequestNum:          snapshot.MaxRequestNum,
+		CostRate:               r.GetRctRate(),
+		ExecutionCount:         snapshot.ExecutionCount,
+		TotalCpuMs:             snapshot.TotalCpuMs,
+		TotalMemPeakMb:         snapshot.TotalMemPeakMb,
+		MaxMemPeakMb:           snapshot.MaxMemPeakMb,
+		TotalNetworkBytesIn:    snapshot.TotalNetworkBytesIn,
+		TotalNetworkBytesOut:   snapshot.TotalNetworkBytesOut,
+	}
+}
+
+// LoadFromProto 用快照覆盖各项 EWMA、最大并发数和执行统计，正在进行中的请求记录保持不变
+func (r *RuntimeStatus) LoadFromProto(snapshot *pb.RuntimeStatusProto) {
+	r.requestDurationMu.Lock()
+	r.requestCostTime = time.Duration(snapshot.RequestCostTimeNs)
+	if snapshot.CostRate > 0 {
+		r.costRate = snapshot.CostRate
+	}
+	r.costSamples = nil
+	r.costSampleAt = 0
+	r.requestDurationMu.Unlock()
+
+	r.assignStartMu.Lock()
+	r.assignLatency = time.Duration(snapshot.AssignLatencyNs)
+	r.assignStartMu.Unlock()
+
+	r.platformCallMu.Lock()
+	r.platformCallDuration = time.Duration(snapshot.PlatformCallDurationNs)
+	r.platformCallMu.Unlock()
+
+	r.requestInstanceMu.Lock()
+	r.maxRequestNum = snapshot.MaxRequestNum
+	r.requestInstanceMu.Unlock()
+
+	r.executionMu.Lock()
+	r.executionStats = executionTotals{
+		count:           snapshot.ExecutionCount,
+		cpuMs:           snapshot.TotalCpuMs,
+		memPeakMb:       snapshot.TotalMemPeakMb,
+		maxMemPeakMb:    snapshot.MaxMemPeakMb,
+		networkBytesIn:  snapshot.TotalNetworkBytesIn,
+		networkBytesOut: snapshot.TotalNetworkBytesOut,
+	}
+	r.executionMu.Unlock()
+}
@@ -0,0 +1,482 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// 记录一个耗时为 d 的请求
+func observeCost(r *RuntimeStatus, requestId string, d time.Duration) {
+	r.requestDurationMu.Lock()
+	r.requestDuration[requestId] = time.Now().Add(-d)
+	r.requestDurationMu.Unlock()
+	r.IdleStart(requestId)
+}
+
+// 开启 AdaptiveRctRate 后，耗时稳定的流量使用较大的衰减系数，波动大的流量使用较小的衰减系数
+// 通过 ReloadConfig 关闭后恢复为配置的 RctRate
+func TestAdaptiveRctRate(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdaptiveRctRate = true
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	for i := 0; i < rctSampleSize; i++ {
+		observeCost(s.runtimeStatus, fmt.Sprintf("stable-%d", i), 100*time.Millisecond)
+	}
+	stable := s.runtimeStatus.GetRctRate()
+	if stable < maxAdaptiveRctRate-0.01 {
+		t.Fatalf("stable traffic got rct rate %v, want about %v", stable, maxAdaptiveRctRate)
+	}
+
+	for i := 0; i < rctSampleSize; i++ {
+		d := 10 * time.Millisecond
+		if i%2 == 0 {
+			d = 2 * time.Second
+		}
+		observeCost(s.runtimeStatus, fmt.Sprintf("variable-%d", i), d)
+	}
+	if variable := s.runtimeStatus.GetRctRate(); variable >= stable || variable > (minAdaptiveRctRate+maxAdaptiveRctRate)/2 {
+		t.Fatalf("variable traffic got rct rate %v, want it to drop towards %v", variable, minAdaptiveRctRate)
+	}
+
+	reloaded := *cfg
+	reloaded.AdaptiveRctRate = false
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	observeCost(s.runtimeStatus, "fixed", 10*time.Millisecond)
+	if rate := s.runtimeStatus.GetRctRate(); rate != cfg.RctRate {
+		t.Fatalf("got rct rate %v after disabling, want %v", rate, cfg.RctRate)
+	}
+}
+
+// 没有开启 AdaptiveRctRate 时衰减系数固定
+func TestRctRateFixedByDefault(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdaptiveRctRate = false
+	s := newTestSimple(t, cfg, newFakePlatform())
+	for i := 0; i < rctSampleSize; i++ {
+		observeCost(s.runtimeStatus, fmt.Sprintf("r%d", i), time.Duration(i%2*900+10)*time.Millisecond)
+	}
+	if rate := s.runtimeStatus.GetRctRate(); rate != cfg.RctRate {
+		t.Fatalf("got rct rate %v, want %v", rate, cfg.RctRate)
+	}
+
+	enabled := *cfg
+	enabled.AdaptiveRctRate = true
+	if err := s.ReloadConfig(&enabled); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	for i := 0; i < rctSampleSize; i++ {
+		observeCost(s.runtimeStatus, fmt.Sprintf("stable-%d", i), 100*time.Millisecond)
+	}
+	if rate := s.runtimeStatus.GetRctRate(); rate == cfg.RctRate {
+		t.Fatalf("rct rate did not adapt after enabling")
+	}
+}
+
+// 快照后清空，再从快照恢复，所有指标与快照一致；恢复到新的 RuntimeStatus 同样一致
+func TestRuntimeStatusProtoRoundTrip(t *testing.T) {
+	r := NewRuntimeStatus("app")
+	if err := r.SetRctRate(0.3); err != nil {
+		t.Fatalf("set rct rate: %v", err)
+	}
+	// 先有请求耗时，并发请求才会在窗口内累计
+	observeCost(r, "warm", 200*time.Millisecond)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		r.AssignStart(fmt.Sprintf("r%d", i), now.Add(-30*time.Millisecond))
+	}
+	r.AssignReturn("r0", true)
+	r.ObservePlatformCall(40 * time.Millisecond)
+	r.ObserveExecution(&model2.ExecutionStats{CpuMs: 5, MemPeakMb: 64, NetworkBytesIn: 100, NetworkBytesOut: 200})
+	r.ObserveExecution(&model2.ExecutionStats{CpuMs: 7, MemPeakMb: 32, NetworkBytesIn: 10, NetworkBytesOut: 20})
+
+	snapshot := r.SnapshotToProto()
+	if snapshot.RequestCostTimeNs == 0 || snapshot.AssignLatencyNs == 0 || snapshot.PlatformCallDurationNs == 0 ||
+		snapshot.MaxRequestNum != 3 || snapshot.CostRate != 0.3 || snapshot.ExecutionCount != 2 || snapshot.MaxMemPeakMb != 64 {
+		t.Fatalf("snapshot does not reflect the recorded metrics: %v", snapshot)
+	}
+
+	r.LoadFromProto(&pb.RuntimeStatusProto{})
+	if cleared := r.SnapshotToProto(); cleared.RequestCostTimeNs != 0 || cleared.MaxRequestNum != 0 || cleared.ExecutionCount != 0 {
+		t.Fatalf("loading an empty snapshot did not clear the metrics: %v", cleared)
+	}
+	r.LoadFromProto(snapshot)
+	if restored := r.SnapshotToProto(); !proto.Equal(restored, snapshot) {
+		t.Fatalf("restored %v, want %v", restored, snapshot)
+	}
+
+	fresh := NewRuntimeStatus("app")
+	fresh.LoadFromProto(snapshot)
+	if restored := fresh.SnapshotToProto(); !proto.Equal(restored, snapshot) {
+		t.Fatalf("restored into a new status %v, want %v", restored, snapshot)
+	}
+}
+
+func TestAssignLatencyPercentile(t *testing.T) {
+	r := NewRuntimeStatus("app")
+	if p := r.P99(); p != 0 {
+		t.Fatalf("percentile without samples got %s, want 0", p)
+	}
+	// 乱序写入 1ms..100ms
+	for i := 100; i >= 1; i-- {
+		r.ObserveAssignLatency(time.Duration(i) * time.Millisecond)
+	}
+	cases := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := r.Percentile(c.p); got != c.want {
+			t.Errorf("p%v got %s, want %s", c.p, got, c.want)
+		}
+	}
+	if r.P50() != 50*time.Millisecond || r.P95() != 95*time.Millisecond || r.P99() != 99*time.Millisecond {
+		t.Fatalf("unexpected percentiles %s %s %s", r.P50(), r.P95(), r.P99())
+	}
+}
+
+// 样本数达到上限后覆盖最旧的样本
+func TestAssignLatencySampleSize(t *testing.T) {
+	r := NewRuntimeStatus("app")
+	r.SetLatencySampleSize(3)
+	for i := 1; i <= 5; i++ {
+		r.ObserveAssignLatency(time.Duration(i) * time.Millisecond)
+	}
+	if min, max := r.Percentile(0), r.Percentile(100); min != 3*time.Millisecond || max != 5*time.Millisecond {
+		t.Fatalf("kept samples [%s, %s], want [3ms, 5ms]", min, max)
+	}
+	r.SetLatencySampleSize(0)
+	if p := r.P50(); p != 0 {
+		t.Fatalf("resizing should drop samples, got %s", p)
+	}
+}
+
+// 冷启动的 Assign 包含创建耗时，只影响高百分位
+func TestDetailedStatsAssignLatency(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	for i := 0; i < 20; i++ {
+		reply := assignAll(t, s, assignRequest(fmt.Sprintf("r%d", i)))[0]
+		idleInOrder(t, s, reply)
+	}
+	stats := s.DetailedStats()
+	if stats.AssignP99 < 50*time.Millisecond {
+		t.Fatalf("p99 %s should include the cold start", stats.AssignP99)
+	}
+	if stats.AssignP50 >= 50*time.Millisecond || stats.AssignP95 >= 50*time.Millisecond {
+		t.Fatalf("warm assigns should be fast, p50 %s p95 %s", stats.AssignP50, stats.AssignP95)
+	}
+	if stats.TotalInstance != 1 {
+		t.Fatalf("unexpected stats %+v", stats.Stats)
+	}
+}
+
+// 流量中断超过 StalenessDuration 后，之前的 EWMA 不再影响新一波请求
+// 中断前已分配、中断后归还的请求按真实耗时计入新的 EWMA
+func TestResetAfterTrafficGap(t *testing.T) {
+	cfg := testConfig()
+	cfg.StalenessDuration = 100 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	r := s.runtimeStatus
+
+	replies := assignAll(t, s, assignRequest("old"), assignRequest("inflight"))
+	idleInOrder(t, s, replies[0])
+	observeCost(r, "slow", 5*time.Second)
+	if cost := r.GetRequestCostTime(); cost < 400*time.Millisecond {
+		t.Fatalf("got request cost %s before the gap, want it to include the slow request", cost)
+	}
+
+	time.Sleep(2 * cfg.StalenessDuration)
+	assignStart := time.Now()
+	fresh := assignAll(t, s, assignRequest("fresh"))[0]
+	if snapshot := r.MetricsSnapshot(); snapshot.RequestCostTime != 0 || snapshot.MaxRequestNum != 1 {
+		t.Fatalf("assign after the gap did not reset the status: %+v", snapshot)
+	}
+
+	inflightCost := time.Since(assignStart) + 2*cfg.StalenessDuration
+	if _, err := s.Idle(context.Background(), idleRequest(replies[1], false)); err != nil {
+		t.Fatalf("idle inflight: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return r.GetRequestCostTime() != 0 })
+	if cost := r.GetRequestCostTime(); cost < 2*cfg.StalenessDuration || cost > inflightCost+time.Second {
+		t.Fatalf("got request cost %s for the in-flight request, want about %s", cost, inflightCost)
+	}
+	// 同一波请求只清空一次，随后的请求在新的 EWMA 上累计
+	idleInOrder(t, s, fresh)
+	if snapshot := r.MetricsSnapshot(); snapshot.RequestCostTime == 0 || snapshot.MaxRequestNum == 0 {
+		t.Fatalf("status was reset again within the same burst: %+v", snapshot)
+	}
+}
+
+// StalenessDuration 为 0 时不清空统计
+func TestStalenessDisabled(t *testing.T) {
+	r := NewRuntimeStatus("app")
+	r.SetStalenessDuration(0)
+	observeCost(r, "warm", 200*time.Millisecond)
+	r.AssignStart("r0", time.Now())
+	r.AssignReturn("r0", true)
+	r.AssignStart("r1", time.Now().Add(time.Hour))
+	if cost := r.GetRequestCostTime(); cost < 200*time.Millisecond {
+		t.Fatalf("got request cost %s, want the status to be kept", cost)
+	}
+
+	r.SetStalenessDuration(time.Minute)
+	r.AssignReturn("r1", true)
+	r.AssignStart("r2", time.Now().Add(time.Hour))
+	if cost := r.GetRequestCostTime(); cost != 0 {
+		t.Fatalf("got request cost %s, want a reset after the gap", cost)
+	}
+}
+
+// 桶的上界不包含在桶内，最后一个桶没有上界
+func TestInitDurationHistogramBuckets(t *testing.T) {
+	cases := []struct {
+		d      time.Duration
+		bucket string
+	}{
+		{0, "<100ms"},
+		{99 * time.Millisecond, "<100ms"},
+		{100 * time.Millisecond, "100ms-500ms"},
+		{499 * time.Millisecond, "100ms-500ms"},
+		{500 * time.Millisecond, "500ms-1s"},
+		{time.Second, "1s-5s"},
+		{5 * time.Second, ">5s"},
+		{time.Hour, ">5s"},
+	}
+	for _, c := range cases {
+		var h InitDurationHistogram
+		h.Observe(c.d)
+		snapshot := h.Snapshot()
+		if len(snapshot) != len(initDurationBuckets) {
+			t.Fatalf("snapshot should report every bucket, got %v", snapshot)
+		}
+		for name, count := range snapshot {
+			want := int64(0)
+			if name == c.bucket {
+				want = 1
+			}
+			if count != want {
+				t.Fatalf("%s: bucket %s has %d, want %d", c.d, name, count, want)
+			}
+		}
+	}
+}
+
+// DetailedStats 按桶报告创建成功的实例的 Init 耗时，Init 失败的实例不计入
+func TestDetailedStatsInitDurationHistogram(t *testing.T) {
+	platform := newFakePlatform()
+	platform.setInitDurations(30, 300, 700, 2000, 6000, 80)
+	s := newTestSimple(t, testConfig(), platform)
+	var requests []*pb.AssignRequest
+	for i := 0; i < 6; i++ {
+		requests = append(requests, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	assignAll(t, s, requests...)
+	platform.setInitErr(fmt.Errorf("init failed"))
+	if _, err := s.Assign(context.Background(), assignRequest("failed")); err == nil {
+		t.Fatalf("assign should fail when init fails")
+	}
+
+	want := map[string]int64{"<100ms": 2, "100ms-500ms": 1, "500ms-1s": 1, "1s-5s": 1, ">5s": 1}
+	if got := s.DetailedStats().InitDurationHistogram; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got histogram %v, want %v", got, want)
+	}
+}
+
+// SetRctRate 只接受 [0,1] 内的值，非法值不改变当前系数
+func TestSetRctRate(t *testing.T) {
+	cases := []struct {
+		rate float64
+		ok   bool
+	}{
+		{0, true},
+		{0.5, true},
+		{1, true},
+		{-0.1, false},
+		{1.1, false},
+		{math.NaN(), false},
+	}
+	for _, c := range cases {
+		r := NewRuntimeStatus("app")
+		before := r.GetRctRate()
+		err := r.SetRctRate(c.rate)
+		if (err == nil) != c.ok {
+			t.Fatalf("rate %v: got error %v, want ok %v", c.rate, err, c.ok)
+		}
+		want := before
+		if c.ok {
+			want = c.rate
+		}
+		if got := r.GetRctRate(); got != want {
+			t.Fatalf("rate %v: got rct rate %v, want %v", c.rate, got, want)
+		}
+	}
+}
+
+// 系数小时请求耗时的估计更快跟上新的耗时
+func TestRctRateReactionSpeed(t *testing.T) {
+	estimate := func(rate float64) time.Duration {
+		r := NewRuntimeStatus("app")
+		if err := r.SetRctRate(rate); err != nil {
+			t.Fatalf("set rct rate: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			observeCost(r, fmt.Sprintf("slow%d", i), time.Second)
+		}
+		observeCost(r, "fast", 100*time.Millisecond)
+		return r.GetRequestCostTime()
+	}
+	if fast := estimate(0.1); fast > 300*time.Millisecond {
+		t.Fatalf("rct rate 0.1 estimates %v after one fast request, want close to 100ms", fast)
+	}
+	if stable := estimate(0.9); stable < 800*time.Millisecond {
+		t.Fatalf("rct rate 0.9 estimates %v after one fast request, want close to 1s", stable)
+	}
+}
+
+// RctRateOverrides 按 meta key 覆盖 RctRate，ReloadConfig 修改或删除覆盖后立即生效
+func TestRctRateOverrides(t *testing.T) {
+	cfg := testConfig()
+	cfg.RctRate = 0.5
+	cfg.RctRateOverrides = map[string]float64{testMeta().Key: 0.1, "other": 0.9}
+	s := newTestSimple(t, cfg, newFakePlatform())
+	if rate := s.runtimeStatus.GetRctRate(); rate != 0.1 {
+		t.Fatalf("got rct rate %v, want the override 0.1", rate)
+	}
+
+	reloaded := *cfg
+	reloaded.RctRateOverrides = map[string]float64{testMeta().Key: 0.2}
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if rate := s.runtimeStatus.GetRctRate(); rate != 0.2 {
+		t.Fatalf("got rct rate %v after reload, want 0.2", rate)
+	}
+
+	removed := *cfg
+	removed.RctRateOverrides = nil
+	if err := s.ReloadConfig(&removed); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if rate := s.runtimeStatus.GetRctRate(); rate != cfg.RctRate {
+		t.Fatalf("got rct rate %v without override, want %v", rate, cfg.RctRate)
+	}
+
+	invalid := *cfg
+	invalid.RctRateOverrides = map[string]float64{testMeta().Key: 2}
+	if err := s.ReloadConfig(&invalid); err == nil {
+		t.Fatalf("reload with an out of range override should fail")
+	}
+	if rate := s.runtimeStatus.GetRctRate(); rate != cfg.RctRate {
+		t.Fatalf("got rct rate %v after a rejected reload, want %v", rate, cfg.RctRate)
+	}
+}
+
+// 请求耗时设为 cost，按 ages 的顺序加入请求时间戳，age 相同的时间戳完全相等
+func fillRequestInstances(r *RuntimeStatus, cost time.Duration, ages []time.Duration) {
+	r.requestDurationMu.Lock()
+	r.requestCostTime = cost
+	r.requestDurationMu.Unlock()
+	now := time.Now()
+	r.requestInstanceMu.Lock()
+	defer r.requestInstanceMu.Unlock()
+	for _, age := range ages {
+		r.requestInstance.PushBack(now.Add(-age))
+	}
+}
+
+func requestInstanceLen(r *RuntimeStatus) int {
+	r.requestInstanceMu.Lock()
+	defer r.requestInstanceMu.Unlock()
+	return r.requestInstance.Len()
+}
+
+// getCurrentRequestBNum 和 Assign 中的 AssignStart 删除全部超过请求耗时的时间戳，不会在第一次删除后停止
+func TestRequestInstanceScan(t *testing.T) {
+	const cost = 500 * time.Millisecond
+	expired, live := 2*time.Second, 10*time.Millisecond
+	cases := []struct {
+		name string
+		ages []time.Duration
+		want int
+	}{
+		{name: "empty", want: 0},
+		{name: "all live", ages: []time.Duration{live, live, live}, want: 3},
+		{name: "all expired", ages: []time.Duration{expired, expired + time.Second, expired}, want: 0},
+		{name: "expired first", ages: []time.Duration{expired, expired, live}, want: 1},
+		{name: "expired last", ages: []time.Duration{live, expired, expired}, want: 1},
+		{name: "interleaved", ages: []time.Duration{expired, live, expired, live, expired}, want: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewRuntimeStatus("app")
+			fillRequestInstances(r, cost, c.ages)
+			if got := r.getCurrentRequestBNum(); got != int64(c.want) {
+				t.Fatalf("getCurrentRequestBNum got %d, want %d", got, c.want)
+			}
+
+			// 经过 Assign 调用 AssignStart，新加入的时间戳不参与本次扫描
+			s := newTestSimple(t, testConfig(), newFakePlatform())
+			fillRequestInstances(s.runtimeStatus, cost, c.ages)
+			assignAll(t, s, assignRequest("new"))
+			if got := requestInstanceLen(s.runtimeStatus); got != c.want+1 {
+				t.Fatalf("AssignStart left %d timestamps, want %d", got, c.want+1)
+			}
+		})
+	}
+}
+
+// 奇数字节表示已超过请求耗时的时间戳，偶数字节表示仍在耗时内的时间戳，两者与 cost 相差足够远
+func FuzzRequestInstanceScan(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 1, 0})
+	f.Add([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+	f.Add([]byte{255, 255, 255, 254})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		const cost = 500 * time.Millisecond
+		ages := make([]time.Duration, len(data))
+		want := 0
+		for i, b := range data {
+			if b&1 == 1 {
+				ages[i] = time.Second + time.Duration(b)*time.Millisecond
+			} else {
+				ages[i] = time.Duration(b>>3) * time.Millisecond
+				want++
+			}
+		}
+		r := NewRuntimeStatus("app")
+		fillRequestInstances(r, cost, ages)
+		if got := r.getCurrentRequestBNum(); got != int64(want) {
+			t.Fatalf("%v: got %d, want %d", data, got, want)
+		}
+	})
+}
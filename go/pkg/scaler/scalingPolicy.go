@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import "time"
+
+// ScaleDecisionInput 请求进入等待队列时用于判断是否创建新实例的状态
+type ScaleDecisionInput struct {
+	// 空闲队列中的实例数
+	IdleCount int
+	// 已初始化、正在处理请求的实例数
+	BusyCount int
+	// 创建中的实例数
+	CreatingCount int
+	// 等待实例的请求数，包含本次请求
+	QueueDepth int
+	// 请求耗时的 EWMA
+	RequestCostTime time.Duration
+	// 首个实例的创建耗时，还没有实例创建成功时为 0
+	CreateDuration time.Duration
+	// 最近 RequestRateWindow 内平均每秒的 Assign 数
+	CurrentRPS float64
+}
+
+// ScalingPolicy 决定请求进入等待队列时是否创建新实例，在 longPollingMu 内调用，不能阻塞
+type ScalingPolicy interface {
+	ShouldCreate(input ScaleDecisionInput) bool
+}
+
+// WithScalingPolicy 使用 policy 判断是否创建新实例，默认为 SimplePolicy
+func WithScalingPolicy(policy ScalingPolicy) Option {
+	return func(s *Simple) {
+		s.scalingPolicy = policy
+	}
+}
+
+// SimplePolicy 等待的请求数超过创建中的实例数时创建
+type SimplePolicy struct{}
+
+func (SimplePolicy) ShouldCreate(input ScaleDecisionInput) bool {
+	return input.QueueDepth > input.CreatingCount
+}
+
+// PredictivePolicy 估算新实例创建完成前忙碌实例能处理完多少等待的请求，只在剩余请求超过创建中的实例数时创建
+// 请求耗时或创建耗时未知时与 SimplePolicy 相同
+type PredictivePolicy struct{}
+
+func (PredictivePolicy) ShouldCreate(input ScaleDecisionInput) bool {
+	if input.RequestCostTime <= 0 || input.CreateDuration <= 0 {
+		return SimplePolicy{}.ShouldCreate(input)
+	}
+	// 每个忙碌实例在一次创建耗时内能处理的请求数
+	served := input.BusyCount * int(input.CreateDuration/input.RequestCostTime)
+	return input.QueueDepth-served > input.CreatingCount
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSimplePolicy(t *testing.T) {
+	cases := []struct {
+		queueDepth, creating int
+		want                 bool
+	}{
+		{1, 0, true},
+		{1, 1, false},
+		{3, 2, true},
+		{2, 2, false},
+	}
+	for _, c := range cases {
+		input := ScaleDecisionInput{QueueDepth: c.queueDepth, CreatingCount: c.creating}
+		if got := (SimplePolicy{}).ShouldCreate(input); got != c.want {
+			t.Errorf("queue %d creating %d: got %v, want %v", c.queueDepth, c.creating, got, c.want)
+		}
+	}
+}
+
+func TestPredictivePolicy(t *testing.T) {
+	cases := []struct {
+		name  string
+		input ScaleDecisionInput
+		want  bool
+	}{
+		// 耗时未知时与 SimplePolicy 相同
+		{"unknown cost", ScaleDecisionInput{QueueDepth: 3, BusyCount: 5, CreateDuration: time.Second}, true},
+		{"unknown create duration", ScaleDecisionInput{QueueDepth: 3, BusyCount: 5, RequestCostTime: 10 * time.Millisecond}, true},
+		// 2 个忙碌实例在一次创建耗时内各处理 5 个请求，10 个等待的请求不需要新实例
+		{"busy instances drain the queue", ScaleDecisionInput{QueueDepth: 10, BusyCount: 2, RequestCostTime: 100 * time.Millisecond, CreateDuration: 500 * time.Millisecond}, false},
+		{"queue exceeds what busy instances serve", ScaleDecisionInput{QueueDepth: 11, BusyCount: 2, RequestCostTime: 100 * time.Millisecond, CreateDuration: 500 * time.Millisecond}, true},
+		{"creating instances cover the rest", ScaleDecisionInput{QueueDepth: 12, BusyCount: 2, CreatingCount: 2, RequestCostTime: 100 * time.Millisecond, CreateDuration: 500 * time.Millisecond}, false},
+		// 请求耗时比创建耗时长，忙碌实例在新实例就绪前处理不完任何请求
+		{"slow requests", ScaleDecisionInput{QueueDepth: 1, BusyCount: 4, RequestCostTime: time.Second, CreateDuration: 500 * time.Millisecond}, true},
+	}
+	for _, c := range cases {
+		if got := (PredictivePolicy{}).ShouldCreate(c.input); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// 记录收到的输入，按 create 决定是否创建
+type recordingPolicy struct {
+	mu     sync.Mutex
+	inputs []ScaleDecisionInput
+	create bool
+}
+
+func (p *recordingPolicy) ShouldCreate(input ScaleDecisionInput) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inputs = append(p.inputs, input)
+	return p.create
+}
+
+func (p *recordingPolicy) lastInput() ScaleDecisionInput {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inputs[len(p.inputs)-1]
+}
+
+// scaler 在请求进入等待队列时调用 WithScalingPolicy 设置的策略，策略不创建时请求等待已有实例归还
+func TestWithScalingPolicy(t *testing.T) {
+	policy := &recordingPolicy{create: true}
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform, WithScalingPolicy(policy))
+	busy := assignAll(t, s, assignRequest("busy"))[0]
+	if input := policy.lastInput(); input.QueueDepth != 1 || input.IdleCount != 0 || input.BusyCount != 0 {
+		t.Fatalf("unexpected input for the first request %+v", input)
+	}
+
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingDuration) != 0 })
+	policy.mu.Lock()
+	policy.create = false
+	policy.mu.Unlock()
+	served := make(chan string, 1)
+	go func() {
+		reply, err := s.Assign(context.Background(), assignRequest("waiting"))
+		if err != nil {
+			t.Errorf("assign: %v", err)
+			served <- ""
+			return
+		}
+		served <- reply.Assigment.InstanceId
+	}()
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+	if input := policy.lastInput(); input.QueueDepth != 1 || input.BusyCount != 1 || input.CreateDuration == 0 {
+		t.Fatalf("unexpected input for the waiting request %+v", input)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("creates %d, the policy declined to create", n)
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(busy, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if id := <-served; id != busy.Assigment.InstanceId {
+		t.Fatalf("waiting request got %q, want the returned instance", id)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+// Sharded 把同一个应用的请求按 request id 分散到多个 Simple 上，减少单个 Simple 的锁竞争
+// 每个分片独立扩缩容，MaxInstances、ResourceBudgetMb 等限制对每个分片分别生效
+type Sharded struct {
+	shards []*Simple
+	// instance id 到所属分片的映射，实例被回收时删除
+	shardIndex sync.Map
+	// 所有分片下线完成后关闭
+	drainDone chan struct{}
+	drainOnce sync.Once
+}
+
+func newSharded(metaData *model2.Meta, cfg *config.Config, opts ...Option) *Sharded {
+	sharded := &Sharded{shards: make([]*Simple, cfg.ShardCount), drainDone: make(chan struct{})}
+	shardConfig := sharded.shardConfig(cfg)
+	for i := range sharded.shards {
+		sharded.shards[i] = newSimple(metaData, shardConfig, opts...)
+	}
+	sharded.shards[0].logger.Info("scaler is split into shards", "metaKey", metaData.Key, "shards", cfg.ShardCount)
+	return sharded
+}
+
+// 各分片使用的配置，回收实例时同时清理 shardIndex
+func (s *Sharded) shardConfig(cfg *config.Config) *config.Config {
+	shardConfig := *cfg
+	// 各分片会读写同一个检查点文件
+	shardConfig.CheckpointDir = ""
+	callback := cfg.EvictionCallback
+	shardConfig.EvictionCallback = func(instance *model2.Instance, reason string) {
+		s.shardIndex.Delete(instance.Id)
+		if callback != nil {
+			callback(instance, reason)
+		}
+	}
+	return &shardConfig
+}
+
+// ReloadConfig 替换所有分片的配置，分片数不能修改
+func (s *Sharded) ReloadConfig(cfg *config.Config) error {
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+	if cfg.ShardCount != len(s.shards) {
+		return fmt.Errorf("shard count can not be changed from %d to %d without restart", len(s.shards), cfg.ShardCount)
+	}
+	shardConfig := s.shardConfig(cfg)
+	for _, shard := range s.shards {
+		if err := shard.ReloadConfig(shardConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sharded) shardFor(requestId string) *Simple {
+	h := fnv.New32a()
+	h.Write([]byte(requestId))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+func (s *Sharded) Assign(ctx context.Context, request *pb.AssignRequest) (*pb.AssignReply, error) {
+	shard := s.shardFor(request.RequestId)
+	reply, err := shard.Assign(ctx, request)
+	if err == nil && !reply.IsDryRun && reply.Assigment != nil {
+		s.shardIndex.Store(reply.Assigment.InstanceId, shard)
+	}
+	return reply, err
+}
+
+// BatchAssign 由 request id 所在的分片分配全部实例
+func (s *Sharded) BatchAssign(ctx context.Context, request *pb.BatchAssignRequest) (*pb.BatchAssignReply, error) {
+	shard := s.shardFor(request.RequestId)
+	reply, err := shard.BatchAssign(ctx, request)
+	if err == nil {
+		for _, assignment := range reply.Assigments {
+			s.shardIndex.Store(assignment.InstanceId, shard)
+		}
+	}
+	return reply, err
+}
+
+func (s *Sharded) Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
+	if request.Assigment == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "assignment is nil")
+	}
+	shard, ok := s.shardIndex.Load(request.Assigment.InstanceId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("request id %s, instance %s not found", request.Assigment.RequestId, request.Assigment.InstanceId))
+	}
+	return shard.(*Simple).Idle(ctx, request)
+}
+
+func (s *Sharded) Stats() Stats {
+	stats := Stats{CircuitState: CircuitClosed.String()}
+	for _, shard := range s.shards {
+		shardStats := shard.Stats()
+		stats.TotalInstance += shardStats.TotalInstance
+		stats.TotalIdleInstance += shardStats.TotalIdleInstance
+		stats.AllocatedMemoryMb += shardStats.AllocatedMemoryMb
+		// 任一分片熔断时上报该分片的状态
+		if shardStats.CircuitState != CircuitClosed.String() {
+			stats.CircuitState = shardStats.CircuitState
+		}
+		// 上报最短的 gc 间隔
+		if stats.GcInterval == 0 || shardStats.GcInterval < stats.GcInterval {
+			stats.GcInterval = shardStats.GcInterval
+		}
+	}
+	return stats
+}
+
+func (s *Sharded) Clear(rate float64) {
+	for _, shard := range s.shards {
+		shard.Clear(rate)
+	}
+}
+
+func (s *Sharded) CheckLive() bool {
+	for _, shard := range s.shards {
+		if !shard.CheckLive() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Sharded) GetScalingMetrics() ScalingMetrics {
+	var metrics ScalingMetrics
+	for _, shard := range s.shards {
+		shardMetrics := shard.GetScalingMetrics()
+		metrics.DesiredInstances += shardMetrics.DesiredInstances
+		metrics.CurrentInstances += shardMetrics.CurrentInstances
+		metrics.ReadyInstances += shardMetrics.ReadyInstances
+		metrics.QueueDepth += shardMetrics.QueueDepth
+	}
+	return metrics
+}
+
+// Close 并发关闭所有分片，返回第一个错误
+func (s *Sharded) Close(ctx context.Context) error {
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	for i, shard := range s.shards {
+		wg.Add(1)
+		go func(i int, shard *Simple) {
+			defer wg.Done()
+			errs[i] = shard.Close(ctx)
+		}(i, shard)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Retire 并发让所有分片下线并销毁实例，返回第一个错误
+func (s *Sharded) Retire(ctx context.Context) error {
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	for i, shard := range s.shards {
+		wg.Add(1)
+		go func(i int, shard *Simple) {
+			defer wg.Done()
+			errs[i] = shard.Retire(ctx)
+		}(i, shard)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sharded) InstanceMeta() *model2.Meta {
+	return s.shards[0].InstanceMeta()
+}
+
+func (s *Sharded) Drain() {
+	for _, shard := range s.shards {
+		shard.Drain()
+	}
+	s.drainOnce.Do(func() {
+		go func() {
+			for _, shard := range s.shards {
+				<-shard.DrainComplete()
+			}
+			close(s.drainDone)
+		}()
+	})
+}
+
+func (s *Sharded) IsDraining() bool {
+	return s.shards[0].IsDraining()
+}
+
+func (s *Sharded) DrainComplete() <-chan struct{} {
+	return s.drainDone
+}
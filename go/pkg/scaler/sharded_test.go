@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	platform_client2 "github.com/AliyunContainerService/scaler/go/pkg/platform_client"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func shardedConfig(shards int) *config.Config {
+	cfg := testConfig()
+	cfg.ShardCount = shards
+	return cfg
+}
+
+func newTestSharded(tb testing.TB, cfg *config.Config, platform platform_client2.Client) *Sharded {
+	tb.Helper()
+	s := newSharded(testMeta(), cfg,
+		withPlatformClient(platform),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	tb.Cleanup(func() {
+		s.Close(context.Background())
+	})
+	return s
+}
+
+func ownsInstance(s *Simple, instanceId string) bool {
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	_, ok := s.instances[instanceId]
+	return ok
+}
+
+// 请求按 request id 分散到各分片，Idle 把实例归还到分配它的分片
+func TestShardedRoutesByRequestId(t *testing.T) {
+	s := newTestSharded(t, shardedConfig(4), newFakePlatform())
+	routed := make(map[*Simple]bool)
+	for i := 0; i < 32; i++ {
+		request := assignRequest(fmt.Sprintf("req-%d", i))
+		shard := s.shardFor(request.RequestId)
+		if s.shardFor(request.RequestId) != shard {
+			t.Fatalf("request %s is not routed to a stable shard", request.RequestId)
+		}
+		routed[shard] = true
+		reply, err := s.Assign(context.Background(), request)
+		if err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		if !ownsInstance(shard, reply.Assigment.InstanceId) {
+			t.Fatalf("instance %s is not owned by the routed shard", reply.Assigment.InstanceId)
+		}
+		want := shard.Stats().TotalIdleInstance + 1
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+		waitFor(t, time.Second, func() bool { return shard.Stats().TotalIdleInstance == want })
+	}
+	if len(routed) < 2 {
+		t.Fatalf("requests were routed to %d shards, want at least 2", len(routed))
+	}
+}
+
+func TestShardedIdleErrors(t *testing.T) {
+	s := newTestSharded(t, shardedConfig(2), newFakePlatform())
+	missing := &pb.IdleRequest{Assigment: &pb.Assignment{RequestId: "req", MetaKey: "app", InstanceId: "missing"}}
+	if _, err := s.Idle(context.Background(), missing); status.Code(err) != codes.NotFound {
+		t.Fatalf("unknown instance got %v, want NotFound", err)
+	}
+	if _, err := s.Idle(context.Background(), &pb.IdleRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("nil assignment got %v, want InvalidArgument", err)
+	}
+}
+
+// Stats 汇总所有分片，回收的实例从 shardIndex 中删除
+func TestShardedStatsAndEviction(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSharded(t, shardedConfig(4), platform)
+	var replies []*pb.AssignReply
+	for i := 0; i < 8; i++ {
+		reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("req-%d", i)))
+		if err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		replies = append(replies, reply)
+	}
+	if stats := s.Stats(); stats.TotalInstance != 8 || stats.TotalIdleInstance != 0 || stats.AllocatedMemoryMb != 8*int64(testMeta().MemoryInMb) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	for _, reply := range replies[:3] {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	for _, reply := range replies[3:] {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	waitFor(t, time.Second, func() bool {
+		stats := s.Stats()
+		return stats.TotalInstance == 3 && stats.TotalIdleInstance == 3
+	})
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 3 })
+	for _, reply := range replies[3:] {
+		if _, ok := s.shardIndex.Load(reply.Assigment.InstanceId); ok {
+			t.Fatalf("destroyed instance %s is still indexed", reply.Assigment.InstanceId)
+		}
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(replies[3], false)); status.Code(err) != codes.NotFound {
+		t.Fatalf("idle of a destroyed instance got %v, want NotFound", err)
+	}
+}
+
+func TestShardedReloadConfigKeepsShardCount(t *testing.T) {
+	s := newTestSharded(t, shardedConfig(2), newFakePlatform())
+	cfg := testConfig()
+	cfg.ShardCount = 3
+	if err := s.ReloadConfig(cfg); err == nil {
+		t.Fatalf("changing the shard count should fail")
+	}
+	cfg.ShardCount = 2
+	cfg.MaxInstances = 7
+	if err := s.ReloadConfig(cfg); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	for _, shard := range s.shards {
+		if shard.cfg().MaxInstances != 7 {
+			t.Fatalf("shard config was not reloaded")
+		}
+	}
+}
+
+// 所有分片下线完成后 DrainComplete 才关闭
+func TestShardedDrainComplete(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSharded(t, shardedConfig(2), platform)
+	// 只有一个分片有正在处理的请求，另一个分片没有实例，下线时立即完成
+	reply, err := s.Assign(context.Background(), assignRequest("req"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	owner := s.shardFor("req")
+	s.Drain()
+	if !s.IsDraining() {
+		t.Fatalf("scaler should be draining")
+	}
+	for _, shard := range s.shards {
+		if shard != owner {
+			select {
+			case <-shard.DrainComplete():
+			case <-time.After(time.Second):
+				t.Fatalf("empty shard did not finish draining")
+			}
+		}
+	}
+	select {
+	case <-s.DrainComplete():
+		t.Fatalf("drain completed while a shard still has a request in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	select {
+	case <-s.DrainComplete():
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not complete after the request finished")
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if owner.Stats().TotalInstance != 0 {
+		t.Fatalf("instance of the draining shard was not destroyed")
+	}
+}
+
+// 并发 Assign/Idle 的吞吐量，对比单个分片和 4 个分片
+func BenchmarkShardedAssignIdle(b *testing.B) {
+	for _, shards := range []int{1, 4} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			var s Scaler
+			if shards == 1 {
+				s = newTestSimple(b, testConfig(), newFakePlatform())
+			} else {
+				s = newTestSharded(b, shardedConfig(shards), newFakePlatform())
+			}
+			var seq int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					request := assignRequest(fmt.Sprintf("req-%d", atomic.AddInt64(&seq, 1)))
+					reply, err := s.Assign(context.Background(), request)
+					if err != nil {
+						b.Errorf("assign: %v", err)
+						return
+					}
+					if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+						b.Errorf("idle: %v", err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -16,10 +16,19 @@ package scaler
 import (
 	"container/list"
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"text/tabwriter"
 	"time"
 
 	"github.com/AliyunContainerService/scaler/go/pkg/config"
@@ -28,303 +37,2441 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
+// EvictionCallback 收到的回收原因
+const (
+	EvictReasonGC             = "gc"
+	EvictReasonForce          = "force_evict"
+	EvictReasonGraceful       = "graceful_evict"
+	EvictReasonBadInstance    = "bad_instance"
+	EvictReasonMemoryPressure = "memory_pressure"
+	EvictReasonClear          = "clear"
+	EvictReasonIdleOverflow   = "idle_overflow"
+	EvictReasonRetired        = "retired"
+	EvictReasonDrained        = "drained"
+)
+
+// 请求进入等待队列的原因
+const (
+	// 没有空闲实例，本次请求触发了新实例的创建
+	AssignQueuedNoneIdle = "assign_queued_none_idle"
+	// 已有创建中的实例可以满足本次请求
+	AssignQueuedAwaitCreate = "assign_queued_await_create"
+)
+
+// CreateInstanceError 中记录的失败阶段
+const (
+	CreatePhaseCreateSlot = "create_slot"
+	CreatePhaseInit       = "init"
+)
+
+// CreateInstanceError 创建实例失败时返回，记录失败的阶段以及是否超时
+type CreateInstanceError struct {
+	Phase   string
+	Timeout bool
+	Err     error
+}
+
+func (e *CreateInstanceError) Error() string {
+	if e.Timeout {
+		return fmt.Sprintf("%s timeout: %s", e.Phase, e.Err.Error())
+	}
+	return fmt.Sprintf("%s failed: %s", e.Phase, e.Err.Error())
+}
+
+func (e *CreateInstanceError) Unwrap() error {
+	return e.Err
+}
+
+// 创建实例失败时返回给等待请求的错误码，CreateSlot 或 Init 超时为 DeadlineExceeded，
+// 超过内存预算为 ResourceExhausted，其余为 Unavailable
+func createErrorCode(err error) codes.Code {
+	if status.Code(err) == codes.ResourceExhausted {
+		return codes.ResourceExhausted
+	}
+	var createErr *CreateInstanceError
+	if errors.As(err, &createErr) && createErr.Timeout {
+		return codes.DeadlineExceeded
+	}
+	return codes.Unavailable
+}
+
 type Simple struct {
-	config         *config.Config
+	// 当前生效的 *config.Config
+	config         atomic.Value
 	metaData       *model2.Meta
 	platformClient platform_client2.Client
-	mu             sync.Mutex
-	wg             sync.WaitGroup
+	// 加锁顺序：instancesMu -> idleMu，longPollingMu -> idleMu，longPollingMu 不与 instancesMu 嵌套持有
+	// 持有 idleMu 时不能获取 longPollingMu
+	// instancesMu 保护 instances，idleMu 保护 idleInstance
+	instancesMu sync.RWMutex
+	idleMu      sync.Mutex
+	wg          sync.WaitGroup
 	// instances内存映射表,key是实例id
 	instances map[string]*model2.Instance
 	// instances空闲队列
-	idleInstance    *list.List
-	longPollingMu   sync.Mutex
-	longPollingList *list.List
-	// 正在创建的实例数
-	creatingNum      int64
+	idleInstance *list.List
+	// 空闲队列的标签索引
+	labelIndex *labelIndex
+	// 空闲队列中的实例及其元素，由 idleMu 保护
+	idleMembers   map[*model2.Instance]*list.Element
+	longPollingMu sync.Mutex
+	// 等待实例的长轮询请求，按 deadline 排序
+	longPollingHeap *longPollingHeap
+	// 正在创建和已预留创建额度的实例数
+	creatingNum int64
+	// 保证检查创建额度和增加 creatingNum 是原子的
+	createBudgetMu   sync.Mutex
 	runtimeStatus    *RuntimeStatus
 	creatingDuration int64
+	// 已分配的内存总量
+	allocatedMemoryMb int64
+	// sticky key 到上次分配实例的映射
+	stickyMu  sync.Mutex
+	stickyMap map[string]stickyEntry
+	// 请求触发的平台就绪探测，startupProbe 是正在进行的探测，成功后 startupReady 置为 1，不再探测
+	startupMu    sync.Mutex
+	startupProbe *startupProbe
+	startupReady int32
+	// Close 时关闭，通知后台循环退出
+	done      chan struct{}
+	closeOnce sync.Once
+	// 关闭 done 时持有写锁，goBackground 持有读锁检查 done 并调用 wg.Add，保证 wg.Wait 开始后不会再有新的 goroutine
+	shutdownMu sync.RWMutex
+	// Drain 之后置 1，下线完成时关闭 drainDone
+	draining  int32
+	drainDone chan struct{}
+	drainOnce sync.Once
+	// 结构化日志，默认为 slog.Default()
+	logger *slog.Logger
+	tracer trace.Tracer
+	// ReloadConfig 修改 GcInterval 时通知 gc 重新计时
+	gcConfigCh chan struct{}
+	// HotStandby 的备用实例正在创建时为 1
+	hotStandbyCreating int32
+	// 内存压力信号，为 nil 时不监听
+	memoryPressureCh <-chan MemoryPressureLevel
+	// 平台调用的根 ctx，Close 超时后取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	// CreateSlot 和 Init 的超时次数
+	slotCreateTimeouts uint64
+	initTimeouts       uint64
+	// Init 调用的总次数和失败次数
+	initAttempts uint64
+	initErrors   uint64
+	// 观察到的最大 InitDurationInMs
+	maxInitMs int64
+	// ClientId 到 *rate.Limiter 的映射
+	clientLimiters sync.Map
+	// 找不到要求网络等级的空闲实例、退回其他等级的次数
+	networkTierMismatches uint64
+	// 重置后等待复用的 slot，内存预留保持不变
+	slotReusePool chan *model2.Slot
+	slotReused    uint64
+	// 平台对 CreateSlotGroup 返回过 Unimplemented
+	slotGroupUnsupported int32
+	// 预先创建的 slot，PreallocatedSlots 为 0 时为 nil
+	slotPool *SlotPool
+	// 新创建、等待通知请求的实例，notifying 表示已有 goroutine 在批量处理
+	readyMu        sync.Mutex
+	readyInstances []*model2.Instance
+	notifying      bool
+	// 进入分配流程的请求数和其中直接命中空闲实例的请求数
+	assignTotal  uint64
+	idlePoolHits uint64
+	// slot 创建和删除的审计日志，nil 表示不记录
+	auditLog *auditLog
+	// 多次 Close 只释放一次审计日志
+	auditReleaseOnce sync.Once
+	// Assign 和 Idle 的调用记录，nil 表示不记录
+	journal *Journal
+	// 最近一次成功 Assign 或 Idle 调用的时间，UnixNano，初始为创建时间
+	lastActivity int64
+	// 通知预热循环补齐，WarmPoolSize 为 0 时为 nil
+	warmPoolCh chan struct{}
+	// 已分配或正在分配、尚未 Idle 的请求数
+	inFlight int64
+	// 请求进入等待队列时判断是否创建新实例
+	scalingPolicy ScalingPolicy
+	// CreateSlot 和 Init 的熔断器，nil 表示不熔断
+	breaker *CircuitBreaker
+	// CreateSlot 的限流器，可能与其他 scaler 共享，nil 表示不限流
+	slotCreateLimiter *rate.Limiter
+	// 当前生效的 gc 间隔，开启 GcChurnThreshold 时会调整
+	gcInterval int64
+}
+
+type stickyEntry struct {
+	instanceId string
+	assignedAt time.Time
+}
+
+// New 用 DefaultRegistry 中的 SimpleImplementation 创建 scaler
+func New(metaData *model2.Meta, config *config.Config, opts ...Option) Scaler {
+	scaler, err := DefaultRegistry.NewScaler(SimpleImplementation, metaData, config, opts...)
+	if err != nil {
+		log.Fatalf("create scaler for app %s with error: %s", metaData.Key, err.Error())
+	}
+	return scaler
+}
+
+func newScaler(metaData *model2.Meta, config *config.Config, opts ...Option) Scaler {
+	if config.ShardCount > 1 {
+		return newSharded(metaData, config, opts...)
+	}
+	return newSimple(metaData, config, opts...)
+}
+
+// WithLogger 使用 logger 输出日志，logger 为 nil 时使用 slog.Default()
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Simple) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// 应用 metaKey 的配置覆盖，没有覆盖时返回 cfg 本身
+func withMetaOverride(cfg *config.Config, metaKey string) *config.Config {
+	if override, ok := cfg.MetaOverrides[metaKey]; ok {
+		merged := override.Merge(*cfg)
+		return &merged
+	}
+	return cfg
 }
 
-func New(metaData *model2.Meta, config *config.Config) Scaler {
+func newSimple(metaData *model2.Meta, config *config.Config, opts ...Option) *Simple {
 	client, err := platform_client2.New(config.ClientAddr)
 	if err != nil {
 		log.Fatalf("client init with error: %s", err.Error())
 	}
+	if config.MinIdleInstances < 0 || config.MaxIdleInstances < 0 ||
+		(config.MaxIdleInstances > 0 && config.MinIdleInstances > config.MaxIdleInstances) {
+		log.Fatalf("invalid idle bounds, min: %d, max: %d", config.MinIdleInstances, config.MaxIdleInstances)
+	}
+	config = withMetaOverride(config, metaData.Key)
+	ctx, cancel := context.WithCancel(context.Background())
 	scheduler := &Simple{
-		config:          config,
 		metaData:        metaData,
 		platformClient:  client,
-		mu:              sync.Mutex{},
+		instancesMu:     sync.RWMutex{},
+		idleMu:          sync.Mutex{},
 		wg:              sync.WaitGroup{},
 		instances:       make(map[string]*model2.Instance),
 		idleInstance:    list.New(),
+		labelIndex:      newLabelIndex(),
+		idleMembers:     make(map[*model2.Instance]*list.Element),
 		longPollingMu:   sync.Mutex{},
-		longPollingList: list.New(),
+		longPollingHeap: newLongPollingHeap(),
 		creatingNum:     0,
-		runtimeStatus:   NewRuntimeStatus(),
+		runtimeStatus:   NewRuntimeStatus(metaData.Key),
+		stickyMu:        sync.Mutex{},
+		stickyMap:       make(map[string]stickyEntry),
+		scalingPolicy:   SimplePolicy{},
+		done:            make(chan struct{}),
+		drainDone:       make(chan struct{}),
+		gcConfigCh:      make(chan struct{}, 1),
+		// gc 循环启动前 Stats 同样报告配置的间隔
+		gcInterval:   int64(config.GcInterval),
+		logger:       slog.Default(),
+		tracer:       noopTracer,
+		lastActivity: time.Now().UnixNano(),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	if config.SlotReusePoolSize > 0 {
+		scheduler.slotReusePool = make(chan *model2.Slot, config.SlotReusePoolSize)
+	}
+	scheduler.config.Store(config)
+	scheduler.runtimeStatus.SetLatencySampleSize(config.AssignLatencySampleSize)
+	scheduler.runtimeStatus.SetStalenessDuration(config.StalenessDuration)
+	scheduler.runtimeStatus.SetRequestRateWindow(config.RequestRateWindow)
+	if err := scheduler.runtimeStatus.SetRctRate(config.RctRateFor(metaData.Key)); err != nil {
+		log.Fatalf("invalid rct rate for app %s: %s", metaData.Key, err.Error())
+	}
+	scheduler.runtimeStatus.SetAdaptiveRctRate(config.AdaptiveRctRate)
+	scheduler.slotCreateLimiter = sharedSlotCreateLimiter(config.SlotCreateRPS, config.SlotCreateBurst)
+	if config.CircuitBreakerThreshold > 0 {
+		scheduler.breaker = NewCircuitBreaker(config.CircuitBreakerThreshold, config.CircuitBreakerRecovery)
+	}
+	if config.WarmPoolSize > 0 {
+		scheduler.warmPoolCh = make(chan struct{}, 1)
+	}
+	if config.PreallocatedSlots > 0 {
+		scheduler.slotPool = newSlotPool(config.PreallocatedSlots)
+	}
+	if config.AuditLogPath != "" {
+		scheduler.auditLog = openAuditLog(config.AuditLogPath, config.AuditLogMaxSizeMb)
 	}
-	log.Printf("New scaler for app: %s is created", metaData.Key)
+	for _, opt := range opts {
+		opt(scheduler)
+	}
+	if dir := config.CheckpointDir; dir != "" {
+		if _, err := os.Stat(checkpointFile(dir, metaData.Key)); err == nil {
+			if err := scheduler.RestoreFromCheckpoint(dir); err != nil {
+				scheduler.logger.Error("restore from checkpoint failed", "metaKey", metaData.Key, "error", err)
+			}
+		}
+	}
+	liveScalers.Store(scheduler, struct{}{})
+	scheduler.logger.Info("new scaler is created", "metaKey", metaData.Key)
 	// 回收pod
 	scheduler.wg.Add(1)
 	go func() {
 		defer scheduler.wg.Done()
 		scheduler.gcLoop()
-		log.Printf("gc loop for app: %s is stoped", metaData.Key)
+		scheduler.logger.Info("gc loop is stopped", "metaKey", metaData.Key)
 	}()
+	if scheduler.warmPoolCh != nil {
+		scheduler.wg.Add(1)
+		go func() {
+			defer scheduler.wg.Done()
+			scheduler.warmPoolLoop()
+		}()
+	}
+	if scheduler.slotPool != nil {
+		scheduler.wg.Add(1)
+		go func() {
+			defer scheduler.wg.Done()
+			scheduler.slotPoolLoop()
+		}()
+	}
+	if scheduler.memoryPressureCh != nil {
+		scheduler.wg.Add(1)
+		go func() {
+			defer scheduler.wg.Done()
+			scheduler.memoryPressureLoop()
+		}()
+	}
 
 	return scheduler
 }
 
 // 通知等待的请求,有空闲的instance
 func (s *Simple) notifyRequest(instance *model2.Instance) {
+	s.notifyRequests([]*model2.Instance{instance})
+}
+
+// 在一次 longPollingMu 临界区内把一批实例分配给等待的请求，剩余有空闲槽位的实例加入空闲资源池
+// 同时持有 idleMu，检查空闲队列成员、分配和插入空闲队列不会与其他通知交错，同一实例不会被重复插入
+// 调用方不能持有 instancesMu、idleMu，也不会获取 instancesMu
+func (s *Simple) notifyRequests(instances []*model2.Instance) {
 	s.longPollingMu.Lock()
-	// 如果有等待的长轮询请求
-	if element := s.longPollingList.Front(); element != nil {
-		// 有长轮询请求
-		log.Printf("notify long polling request, instance: %s", instance.Id)
-		// 获取请求的channel
-		longPollingChan := element.Value.(chan *model2.Instance)
-		// 从队列中删除
-		s.longPollingList.Remove(element)
-		// 发送实例通知
-		longPollingChan <- instance
-		s.longPollingMu.Unlock()
-	} else {
-		// 没有等待请求，将释放的instance加入到空闲资源池
-		log.Printf("add to idleInstance, instance: %s", instance.Id)
-		s.longPollingMu.Unlock()
-		instance.Busy = false
-		instance.LastIdleTime = time.Now()
-		s.mu.Lock()
-		s.idleInstance.PushFront(instance)
-		s.mu.Unlock()
+	s.idleMu.Lock()
+	overflow, drained := s.notifyRequestsLocked(instances)
+	s.idleMu.Unlock()
+	s.longPollingMu.Unlock()
+	s.evictNotIdled(overflow, EvictReasonIdleOverflow)
+	s.evictNotIdled(drained, EvictReasonDrained)
+}
+
+// notifyRequests 的临界区部分，返回因空闲队列已满和因下线中需要回收的实例，调用方需持有 longPollingMu 和 idleMu
+func (s *Simple) notifyRequestsLocked(instances []*model2.Instance) (overflow, drained []*model2.Instance) {
+	fair := s.cfg().FairQueueing
+	sorted := s.cfg().SortIdleByInitDuration
+	maxIdle := s.cfg().MaxIdleInstances
+	draining := s.IsDraining()
+	for _, instance := range instances {
+		// 已在空闲队列中的实例由 Assign 分配，例如 PromoteToIdle 与创建完成的通知同时处理同一实例
+		if _, ok := s.idleMembers[instance]; ok {
+			s.logger.Info("instance is already idle", "instanceId", instance.Id)
+			continue
+		}
+		// 实例不满足的请求留给其他实例，保留在等待队列中
+		match := func(waiter *longPollingRequest) bool {
+			if !matchesTags(instance, waiter.tags) {
+				return false
+			}
+			return !fair || waiterCompatible(instance, waiter)
+		}
+		// 实例还有空闲槽位且有等待的长轮询请求时，取出 deadline 最近的匹配请求
+		for instance.HasFreeSlot() {
+			waiter := s.longPollingHeap.popMatching(match)
+			if waiter == nil {
+				break
+			}
+			// 有长轮询请求
+			s.logger.Info("notify long polling request", "instanceId", instance.Id)
+			// 发送实例通知
+			instance.AcquireSlot()
+			if !s.deliver(waiter, instance) {
+				instance.ReleaseSlot()
+				s.logger.Warn("notify long polling request timeout", "instanceId", instance.Id, "duration", s.cfg().IdleNotifyTimeout)
+			}
+		}
+		if !instance.HasFreeSlot() {
+			continue
+		}
+		// 下线中没有请求在处理的实例不再放回空闲队列
+		if draining && !instance.IsBusy() {
+			drained = append(drained, instance)
+			continue
+		}
+		// 没有等待请求或通知超时，将有空闲槽位的instance加入到空闲资源池
+		s.logger.Info("add to idle instances", "instanceId", instance.Id)
+		if !instance.IsBusy() {
+			instance.Busy = false
+			instance.LastIdleTime = time.Now()
+		}
+		// 空闲队列已满，没有请求在处理的实例直接回收
+		if maxIdle > 0 && s.idleInstance.Len() >= maxIdle && !instance.IsBusy() {
+			overflow = append(overflow, instance)
+			continue
+		}
+		if sorted {
+			s.insertSortedIdle(instance)
+		} else if instance.IsBusy() {
+			// 仍有请求在处理的多槽位实例没有更新空闲时间，按原来的空闲时间插入
+			s.insertIdleByTime(instance)
+		} else {
+			s.pushIdleFront(instance)
+		}
+	}
+	return overflow, drained
+}
+
+// 回收因空闲队列已满或下线中没有放回空闲队列的实例，调用方不能持有 instancesMu
+func (s *Simple) evictNotIdled(instances []*model2.Instance, reason string) {
+	if len(instances) == 0 {
+		return
+	}
+	s.instancesMu.Lock()
+	for _, instance := range instances {
+		// 期间可能已被 ForceEvict 回收
+		if s.instances[instance.Id] != instance {
+			continue
+		}
+		s.logger.Info("instance is not put back to idle pool, evict it", "metaKey", s.metaData.Key, "instanceId", instance.Id, "reason", reason)
+		delete(s.instances, instance.Id)
+		s.goDestroy(instance, reason)
+	}
+	s.instancesMu.Unlock()
+}
+
+// 按 InitDurationInMs 从小到大插入空闲队列，耗时相同的插在已有实例之前，调用方需持有 idleMu
+// 链表无法二分查找，插入开销为 O(n)
+func (s *Simple) insertSortedIdle(instance *model2.Instance) {
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		if instance.InitDurationInMs <= element.Value.(*model2.Instance).InitDurationInMs {
+			s.trackIdle(s.idleInstance.InsertBefore(instance, element))
+			return
+		}
+	}
+	s.trackIdle(s.idleInstance.PushBack(instance))
+}
+
+// 按 LastIdleTime 从新到旧插入空闲队列，调用方需持有 idleMu
+func (s *Simple) insertIdleByTime(instance *model2.Instance) {
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		if !instance.LastIdleTime.Before(element.Value.(*model2.Instance).LastIdleTime) {
+			s.trackIdle(s.idleInstance.InsertBefore(instance, element))
+			return
+		}
+	}
+	s.trackIdle(s.idleInstance.PushBack(instance))
+}
+
+// 把新创建的实例加入待通知队列，同一时间只有一个 goroutine 批量处理
+func (s *Simple) enqueueReady(instance *model2.Instance) {
+	s.readyMu.Lock()
+	s.readyInstances = append(s.readyInstances, instance)
+	if s.notifying {
+		s.readyMu.Unlock()
+		return
+	}
+	s.notifying = true
+	s.readyMu.Unlock()
+	go s.notifyReady()
+}
+
+// 循环取出待通知队列中积累的实例批量通知，直到队列为空
+func (s *Simple) notifyReady() {
+	for {
+		s.readyMu.Lock()
+		batch := s.readyInstances
+		s.readyInstances = nil
+		if len(batch) == 0 {
+			s.notifying = false
+			s.readyMu.Unlock()
+			return
+		}
+		s.readyMu.Unlock()
+		s.logger.Info("notify requests of created instances", "count", len(batch))
+		s.notifyRequests(batch)
+	}
+}
+
+// 把实例发送给等待的请求，超过 IdleNotifyTimeout 仍未送达时关闭请求的 channel，请求收到 nil 后返回错误
+func (s *Simple) deliver(waiter *longPollingRequest, instance *model2.Instance) bool {
+	timeout := s.cfg().IdleNotifyTimeout
+	if timeout <= 0 {
+		waiter.ch <- instance
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case waiter.ch <- instance:
+		return true
+	case <-timer.C:
+		close(waiter.ch)
+		return false
 	}
 }
 
 // Assign 处理分配实例请求
 func (s *Simple) Assign(ctx context.Context, request *pb.AssignRequest) (*pb.AssignReply, error) {
-	go s.runtimeStatus.AssignStart(time.Now())
-	log.Printf("Assign, request id: %s", request.RequestId)
+	return s.AssignWithHint(ctx, request, AssignHint{})
+}
+
+// AssignWithHint 与 Assign 相同，但挑选空闲实例时先参考 hint
+func (s *Simple) AssignWithHint(ctx context.Context, request *pb.AssignRequest, hint AssignHint) (*pb.AssignReply, error) {
+	reply, err := s.assign(ctx, request, hint)
+	s.journalAssign(request, reply, err)
+	return reply, err
+}
+
+func (s *Simple) assign(ctx context.Context, request *pb.AssignRequest, hint AssignHint) (*pb.AssignReply, error) {
+	ctx, span := s.tracer.Start(ctx, "scaler.Assign", trace.WithAttributes(
+		attribute.String("requestId", request.RequestId),
+		attribute.String("metaKey", request.MetaData.GetKey()),
+	))
+	defer span.End()
+	// 冷启动时确认平台可以访问，避免把请求分配到不可达的平台
+	if err := s.waitStartup(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, status.Errorf(codes.Unavailable, "platform is not ready: %s", err.Error())
+	}
+	if s.IsDraining() {
+		return nil, status.Errorf(codes.FailedPrecondition, "request id %s, scaler for app %s is draining", request.RequestId, s.metaData.Key)
+	}
+	if request.DryRun {
+		return s.dryRunAssign(request, &hint)
+	}
+	if limiter := s.clientLimiter(request.ClientId); limiter != nil && !limiter.Allow() {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for client %s", request.ClientId)
+	}
+	if !s.acquireInFlight() {
+		return nil, status.Errorf(codes.ResourceExhausted, "request id %s, concurrent requests exceed %d", request.RequestId, s.cfg().MaxConcurrentRequests)
+	}
+	assigned := false
+	defer func() {
+		if !assigned {
+			s.releaseInFlight()
+		}
+	}()
+	atomic.AddUint64(&s.assignTotal, 1)
+	// 两者都只持有锁做简单的更新，同步调用保证 AssignReturn 总在 AssignStart 之后，assignStart 中的记录会被删除
+	s.runtimeStatus.AssignStart(request.RequestId, time.Now())
+	s.logger.InfoContext(ctx, "assign", "requestId", request.RequestId)
 	defer func() {
-		go s.runtimeStatus.AssignReturn(request.RequestId)
+		s.runtimeStatus.AssignReturn(request.RequestId, assigned)
 	}()
 	// 记录处理开始时间
 	start := time.Now()
-	// 有空闲资源
-	s.mu.Lock()
-	if element := s.idleInstance.Front(); element != nil {
+	defer s.checkSlowAssign(request.RequestId, start)
+	defer func() {
+		if assigned {
+			s.runtimeStatus.ObserveAssignLatency(time.Since(start))
+		}
+	}()
+	preference := request.PoolPreference
+	// 有空闲资源，Cold 请求不占用空闲实例
+	s.idleMu.Lock()
+	var element *list.Element
+	if preference != pb.PoolPreference_Cold {
+		element = s.selectIdleInstance(request, &hint)
+	}
+	if element != nil {
 		instance := element.Value.(*model2.Instance)
-		// 设置实例为忙碌
-		instance.Busy = true
-		// 从空闲队列中移除
-		s.idleInstance.Remove(element)
-		s.mu.Unlock()
-		log.Printf("Assign idleInstance, request id: %s, instance %s, cost time = %s", request.RequestId, instance.Id, time.Since(start))
-		return &pb.AssignReply{
-			Status: pb.Status_Ok,
-			Assigment: &pb.Assignment{
-				RequestId:  request.RequestId,
-				MetaKey:    instance.Meta.Key,
-				InstanceId: instance.Id,
-			},
-			ErrorMessage: nil,
-		}, nil
-	}
-	s.mu.Unlock()
+		// 占用实例的一个槽位
+		instance.AcquireSlot()
+		instance.LastMetaKey = request.MetaData.Key
+		bindAffinity(instance, request.AffinityKey)
+		// 槽位用满后从空闲队列中移除
+		if !instance.HasFreeSlot() {
+			s.removeIdleElement(element)
+		}
+		s.idleMu.Unlock()
+		atomic.AddUint64(&s.idlePoolHits, 1)
+		s.recordSticky(request.StickyKey, instance.Id)
+		s.logger.InfoContext(ctx, "assign idle instance", "requestId", request.RequestId, "instanceId", instance.Id, "duration", time.Since(start))
+		assigned = true
+		s.markActive()
+		s.signalWarmPool()
+		s.refillHotStandby()
+		return assignReply(request, instance, 0), nil
+	}
+	idleCount := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	if preference == pb.PoolPreference_Warm {
+		s.logger.InfoContext(ctx, "no idle instance for warm request", "requestId", request.RequestId)
+		return nil, status.Errorf(codes.Unavailable, "request id %s, no idle instance", request.RequestId)
+	}
 
 	// 无空闲资源
-	longPollingChan := make(chan *model2.Instance, 1)
+	chanSize := s.cfg().LongPollingChanSize
+	if chanSize <= 0 {
+		chanSize = 1
+	}
+	longPollingChan := make(chan *model2.Instance, chanSize)
+	// longPollingMu 不与 instancesMu 嵌套，先取实例数
+	s.instancesMu.RLock()
+	busyCount := len(s.instances) - idleCount
+	s.instancesMu.RUnlock()
+	if busyCount < 0 {
+		busyCount = 0
+	}
 	s.longPollingMu.Lock()
-	s.longPollingList.PushBack(longPollingChan)
+	// Shutdown 在关闭 done 之后获取 longPollingMu 清空等待队列，这里检查后入队的请求不会被遗漏
+	if s.isShutdown() {
+		s.longPollingMu.Unlock()
+		return nil, status.Errorf(codes.Unavailable, "request id %s, scaler for app %s is shut down", request.RequestId, s.metaData.Key)
+	}
+	deadline, _ := ctx.Deadline()
+	// 入队前已在等待的请求数
+	queuePos := s.longPollingHeap.Len()
+	if limit := s.cfg().MaxQueueDepth; limit > 0 && queuePos >= limit {
+		s.longPollingMu.Unlock()
+		s.logger.WarnContext(ctx, "shed assign", "metaKey", s.metaData.Key, "requestId", request.RequestId, "queueDepth", queuePos, "limit", limit)
+		return nil, status.Errorf(codes.ResourceExhausted, "request id %s, queue depth %d reaches limit %d", request.RequestId, queuePos, limit)
+	}
+	waiter := s.longPollingHeap.push(longPollingChan, deadline)
+	waiter.tags = request.RequiredTags
+	waiter.metaKey = request.MetaData.Key
+	waiter.memoryInMb = request.MetaData.MemoryInMb
 
 	// create instance limit
 	// 如果当前创建数没有达到限制,创建新实例
-	if s.longPollingList.Len() > int(atomic.LoadInt64(&s.creatingNum)) {
-		go func() {
-			s.createInstance(request.MetaData, request.RequestId)
-		}()
+	// Cold 请求总是创建新实例
+	needCreate := preference == pb.PoolPreference_Cold || s.scalingPolicy.ShouldCreate(ScaleDecisionInput{
+		IdleCount:       idleCount,
+		BusyCount:       busyCount,
+		CreatingCount:   int(atomic.LoadInt64(&s.creatingNum)),
+		QueueDepth:      s.longPollingHeap.Len(),
+		RequestCostTime: s.runtimeStatus.GetRequestCostTime(),
+		CreateDuration:  time.Duration(atomic.LoadInt64(&s.creatingDuration)),
+		CurrentRPS:      s.runtimeStatus.CurrentRPS(),
+	})
+	queuedReason := AssignQueuedAwaitCreate
+	if needCreate {
+		queuedReason = AssignQueuedNoneIdle
+	}
+	s.logger.InfoContext(ctx, "assign queued", "reason", queuedReason, "requestId", request.RequestId, "queuePosition", queuePos)
+	if needCreate && s.reserveCreations(1) > 0 {
+		started := s.goBackground(func() {
+			if err := s.createInstance(ctx, request.MetaData, request.RequestId, request.RequiredTags); err != nil {
+				s.failWaiter(waiter, fmt.Errorf("create instance failed: %w", err))
+			}
+		})
+		// 已经关闭时 Shutdown 会让等待的请求返回
+		if !started {
+			s.releaseCreations(1)
+		}
 	}
 	s.longPollingMu.Unlock()
 
+	// 请求自带的超时时间，与 gRPC deadline 相互独立
+	var timeout <-chan time.Time
+	if request.TimeoutMs > 0 {
+		timer := time.NewTimer(time.Until(start.Add(time.Duration(request.TimeoutMs) * time.Millisecond)))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	// 调用方的 deadline 可能很长甚至没有，等待队列自身的超时保证请求不会一直留在队列中
+	var pollingTimeout <-chan time.Time
+	if d := s.cfg().LongPollingTimeout; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		pollingTimeout = timer.C
+	}
+	_, waitSpan := s.tracer.Start(ctx, "scaler.waitIdleInstance")
+	defer waitSpan.End()
 	select {
 	case <-ctx.Done():
-		log.Printf("assign timeout request id: %s", request.RequestId)
+		s.logger.WarnContext(ctx, "assign timeout", "requestId", request.RequestId, "error", ctx.Err())
+		s.cancelWaiter(waiter)
 		return nil, ctx.Err()
+	case <-timeout:
+		s.logger.WarnContext(ctx, "assign exceed request timeout", "requestId", request.RequestId, "timeoutMs", request.TimeoutMs)
+		s.cancelWaiter(waiter)
+		return nil, status.Error(codes.DeadlineExceeded, "request timeout exceeded")
+	case <-pollingTimeout:
+		s.logger.WarnContext(ctx, "assign exceed long polling timeout", "requestId", request.RequestId, "duration", s.cfg().LongPollingTimeout)
+		// 从队列中移除，已投递到 channel 的实例会被取出并归还
+		s.cancelWaiter(waiter)
+		return nil, status.Errorf(codes.DeadlineExceeded, "request id %s, long polling timeout %s exceeded", request.RequestId, s.cfg().LongPollingTimeout)
 	case instance := <-longPollingChan:
-		instance.Busy = true
-		log.Printf("Assign longPolling, request id: %s, instance %s, cost time: %s", request.RequestId, instance.Id, time.Since(start))
-		return &pb.AssignReply{
-			Status: pb.Status_Ok,
-			Assigment: &pb.Assignment{
-				RequestId:  request.RequestId,
-				MetaKey:    instance.Meta.Key,
-				InstanceId: instance.Id,
-			},
-			ErrorMessage: nil,
-		}, nil
+		if instance == nil && waiter.err != nil {
+			return nil, status.Errorf(createErrorCode(waiter.err), "request id %s, %s", request.RequestId, waiter.err.Error())
+		}
+		if instance == nil {
+			s.logger.WarnContext(ctx, "assign notify timeout", "requestId", request.RequestId)
+			return nil, status.Errorf(codes.Unavailable, "request id %s, notify idle instance timeout", request.RequestId)
+		}
+		// 多槽位实例可能同时投递给多个等待的请求，与空闲队列分配一样在 idleMu 内更新
+		s.idleMu.Lock()
+		instance.LastMetaKey = request.MetaData.Key
+		bindAffinity(instance, request.AffinityKey)
+		s.idleMu.Unlock()
+		s.recordSticky(request.StickyKey, instance.Id)
+		s.logger.InfoContext(ctx, "assign long polling", "requestId", request.RequestId, "instanceId", instance.Id, "duration", time.Since(start))
+		assigned = true
+		s.markActive()
+		s.refillHotStandby()
+		return assignReply(request, instance, queuePos), nil
 	}
 }
 
-func (s *Simple) Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
-	go func() {
-		s.runtimeStatus.IdleStart(request.Assigment.RequestId)
-	}()
-	if request.Assigment == nil {
-		return nil, status.Errorf(codes.InvalidArgument, "assignment is nil")
-	}
-	reply := &pb.IdleReply{
-		Status:       pb.Status_Ok,
-		ErrorMessage: nil,
-	}
-	start := time.Now()
-	instanceId := request.Assigment.InstanceId
-	defer func() {
-		log.Printf("Idle, request id: %s, instance: %s, cost %dus", request.Assigment.RequestId, instanceId, time.Since(start).Microseconds())
-	}()
-	//log.Printf("Idle, request id: %s", request.Assigment.RequestId)
-	needDestroy := false
-	slotId := ""
-	if request.Result != nil && request.Result.NeedDestroy != nil && *request.Result.NeedDestroy {
-		needDestroy = true
+// 占用一个并发请求名额，达到 MaxConcurrentRequests 时返回 false
+func (s *Simple) acquireInFlight() bool {
+	n := atomic.AddInt64(&s.inFlight, 1)
+	if limit := s.cfg().MaxConcurrentRequests; limit > 0 && n > limit {
+		atomic.AddInt64(&s.inFlight, -1)
+		return false
 	}
-	defer func() {
-		if needDestroy {
-			s.deleteSlot(ctx, request.Assigment.RequestId, slotId, instanceId, request.Assigment.MetaKey, "bad instance")
-		}
-	}()
-	log.Printf("Idle, request id: %s", request.Assigment.RequestId)
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if instance := s.instances[instanceId]; instance != nil {
-		slotId = instance.Slot.Id
-		if needDestroy {
-			log.Printf("request id %s, instance %s need be destroy", request.Assigment.RequestId, instanceId)
-			return reply, nil
-		}
+	return true
+}
 
-		if !instance.Busy {
-			log.Printf("request id %s, instance %s already freed", request.Assigment.RequestId, instanceId)
-			return reply, nil
+// 释放一个并发请求名额，不会减到 0 以下
+func (s *Simple) releaseInFlight() {
+	for {
+		n := atomic.LoadInt64(&s.inFlight)
+		if n <= 0 || atomic.CompareAndSwapInt64(&s.inFlight, n, n-1) {
+			return
 		}
+	}
+}
 
-		go func() {
-			log.Printf("Idle notify request, instance: %s", instance.Id)
-			s.notifyRequest(instance)
-		}()
+func (s *Simple) markActive() {
+	atomic.StoreInt64(&s.lastActivity, time.Now().UnixNano())
+}
 
-	} else {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("request id %s, instance %s not found", request.Assigment.RequestId, instanceId))
+// 返回客户端的限流器，未配置限流时返回 nil
+func (s *Simple) clientLimiter(clientId string) *rate.Limiter {
+	if clientId == "" {
+		return nil
 	}
-	return &pb.IdleReply{
-		Status:       pb.Status_Ok,
-		ErrorMessage: nil,
-	}, nil
+	if limiter, ok := s.clientLimiters.Load(clientId); ok {
+		return limiter.(*rate.Limiter)
+	}
+	limit, ok := s.cfg().ClientRateLimits[clientId]
+	if !ok || limit <= 0 {
+		return nil
+	}
+	// 允许突发一秒内的请求量
+	burst := int(math.Ceil(limit))
+	limiter, _ := s.clientLimiters.LoadOrStore(clientId, rate.NewLimiter(rate.Limit(limit), burst))
+	return limiter.(*rate.Limiter)
 }
 
-func (s *Simple) deleteSlot(ctx context.Context, requestId, slotId, instanceId, metaKey, reason string) {
-	log.Printf("start delete Instance %s (Slot: %s) of app: %s", instanceId, slotId, metaKey)
-	if err := s.platformClient.DestroySLot(ctx, requestId, slotId, reason); err != nil {
-		log.Printf("delete Instance %s (Slot: %s) of app: %s failed with: %s", instanceId, slotId, metaKey, err.Error())
+// Assign 耗时超过 AssignSlowPathThreshold 时告警
+func (s *Simple) checkSlowAssign(requestId string, start time.Time) {
+	threshold := s.cfg().AssignSlowPathThreshold
+	if threshold <= 0 {
+		return
+	}
+	latency := time.Since(start)
+	if latency <= threshold {
+		return
+	}
+	s.logger.Warn("slow assign", "requestId", requestId, "duration", latency, "threshold", threshold)
+	if callback := s.cfg().OnSlowAssign; callback != nil {
+		callback(requestId, latency)
 	}
 }
 
-// 周期回收
-func (s *Simple) gcLoop() {
-	log.Printf("gc loop for app: %s is started", s.metaData.Key)
-	ticker := time.NewTicker(s.config.GcInterval)
-	for range ticker.C {
-		for {
-			s.mu.Lock()
-			if element := s.idleInstance.Back(); element != nil {
-				instance := element.Value.(*model2.Instance)
-				idleDuration := time.Since(instance.LastIdleTime)
-				if idleDuration > s.config.IdleDurationBeforeGC {
-					//need GC
-					s.idleInstance.Remove(element)
-					// 从map删除
-					delete(s.instances, instance.Id)
-					s.mu.Unlock()
-					// 回收实例
-					go func() {
-						reason := fmt.Sprintf("Idle duration: %fs, excceed configured duration: %fs", idleDuration.Seconds(), s.config.IdleDurationBeforeGC.Seconds())
-						ctx := context.Background()
-						ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-						defer cancel()
-						s.deleteSlot(ctx, uuid.NewString(), instance.Slot.Id, instance.Id, instance.Meta.Key, reason)
-					}()
-					continue
-				}
-			}
-			s.mu.Unlock()
-			break
+// 放弃等待时把请求移出等待队列
+// 请求已被 notifyRequest 取出时，实例可能已经投递到 channel 中，需要把槽位还回去
+func (s *Simple) cancelWaiter(waiter *longPollingRequest) {
+	s.longPollingMu.Lock()
+	removed := s.longPollingHeap.remove(waiter)
+	s.longPollingMu.Unlock()
+	if removed {
+		return
+	}
+	// 投递在 longPollingMu 内完成，此时 channel 中要么有实例，要么已因通知超时关闭
+	select {
+	case instance := <-waiter.ch:
+		if instance == nil {
+			return
 		}
+		s.logger.Info("return instance delivered to canceled request", "instanceId", instance.Id)
+		s.returnSlot(instance)
+	default:
 	}
 }
 
-func (s *Simple) Stats() Stats {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return Stats{
-		TotalInstance:     len(s.instances),
-		TotalIdleInstance: s.idleInstance.Len(),
+// 归还已占用的槽位，实例原本已经用满时重新通知等待的请求或放回空闲队列
+func (s *Simple) returnSlot(instance *model2.Instance) {
+	// 释放槽位会修改 Busy 和 LastIdleTime，持有 idleMu 避免与空闲队列的读取竞争
+	s.idleMu.Lock()
+	wasFull := !instance.HasFreeSlot()
+	instance.ReleaseSlot()
+	s.idleMu.Unlock()
+	if wasFull {
+		go s.notifyRequest(instance)
 	}
 }
 
-func (s *Simple) createInstance(requestMeta *pb.Meta, requestId string) {
-	creatingTime := time.Now()
-	// 将creating数量+1
-	atomic.AddInt64(&s.creatingNum, 1)
-	defer atomic.AddInt64(&s.creatingNum, -1)
-	//Create new Instance
-	instanceId := uuid.New().String()
-	resourceConfig := model2.SlotResourceConfig{
-		ResourceConfig: pb.ResourceConfig{
-			MemoryInMegabytes: requestMeta.MemoryInMb,
-		},
+// 把仍在等待的请求移出队列，并通过关闭 channel 通知失败原因
+func (s *Simple) failWaiter(waiter *longPollingRequest, err error) {
+	s.longPollingMu.Lock()
+	defer s.longPollingMu.Unlock()
+	if !s.longPollingHeap.remove(waiter) {
+		return
+	}
+	waiter.err = err
+	close(waiter.ch)
+}
+
+// 让所有等待的请求返回 err
+func (s *Simple) failAllWaiters(err error) {
+	s.longPollingMu.Lock()
+	defer s.longPollingMu.Unlock()
+	for waiter := s.longPollingHeap.pop(); waiter != nil; waiter = s.longPollingHeap.pop() {
+		waiter.err = err
+		close(waiter.ch)
 	}
+}
 
-	slot, err := s.platformClient.CreateSlot(context.Background(), requestId, &resourceConfig)
-	if err != nil {
-		log.Printf("create slot failed with: %s", err.Error())
-		return
+// 在后台运行 fn，Shutdown 会等待其完成；已经关闭时不运行，返回 false
+func (s *Simple) goBackground(fn func()) bool {
+	s.shutdownMu.RLock()
+	defer s.shutdownMu.RUnlock()
+	if s.isShutdown() {
+		return false
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+func (s *Simple) isShutdown() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// 执行与 Assign 相同的选择逻辑，但不占用空闲实例、不进入等待队列、不创建实例
+func (s *Simple) dryRunAssign(request *pb.AssignRequest, hint *AssignHint) (*pb.AssignReply, error) {
+	s.idleMu.Lock()
+	var element *list.Element
+	if request.PoolPreference != pb.PoolPreference_Cold {
+		element = s.selectIdleInstance(request, hint)
 	}
+	if element != nil {
+		reply := assignReply(request, element.Value.(*model2.Instance), 0)
+		s.idleMu.Unlock()
+		reply.IsDryRun = true
+		return reply, nil
+	}
+	s.idleMu.Unlock()
+	if request.PoolPreference == pb.PoolPreference_Warm {
+		return nil, status.Errorf(codes.Unavailable, "request id %s, no idle instance", request.RequestId)
+	}
+	// 用首个实例的创建耗时估算冷启动延迟
+	warmupLatency := time.Duration(atomic.LoadInt64(&s.creatingDuration))
+	return &pb.AssignReply{
+		Status: pb.Status_Ok,
+		Assigment: &pb.Assignment{
+			RequestId: request.RequestId,
+			MetaKey:   request.MetaData.Key,
+		},
+		ErrorMessage:    nil,
+		IsDryRun:        true,
+		WarmupLatencyMs: uint64(warmupLatency.Milliseconds()),
+	}, nil
+}
 
-	meta := &model2.Meta{
-		Meta: pb.Meta{
-			Key:           requestMeta.Key,
-			Runtime:       requestMeta.Runtime,
-			TimeoutInSecs: requestMeta.TimeoutInSecs,
+func assignReply(request *pb.AssignRequest, instance *model2.Instance, queuePos int) *pb.AssignReply {
+	return &pb.AssignReply{
+		Status: pb.Status_Ok,
+		Assigment: &pb.Assignment{
+			RequestId:  request.RequestId,
+			MetaKey:    instance.Meta.Key,
+			InstanceId: instance.Id,
+			SlotId:     instance.Slot.Id,
 		},
+		ErrorMessage:          nil,
+		QueuePositionAtAssign: int32(queuePos),
 	}
-	instance, err := s.platformClient.Init(context.Background(), requestId, instanceId, slot, meta)
-	if err != nil {
-		log.Printf("create instance failed with: %s", err.Error())
-		return
+}
+
+// 一次平台就绪探测，done 关闭后 err 为探测结果
+type startupProbe struct {
+	done chan struct{}
+	err  error
+}
+
+// 等待平台就绪，只有成功的结果会被记住，失败后下一个请求重新探测
+// 同一时间只有一个探测，在 s.ctx 上以 StartupTimeout 为超时运行，请求的 ctx 只决定自己等待多久
+func (s *Simple) waitStartup(ctx context.Context) error {
+	if s.cfg().StartupTimeout <= 0 || atomic.LoadInt32(&s.startupReady) == 1 {
+		return nil
+	}
+	s.startupMu.Lock()
+	if atomic.LoadInt32(&s.startupReady) == 1 {
+		s.startupMu.Unlock()
+		return nil
+	}
+	probe := s.startupProbe
+	if probe == nil {
+		probe = &startupProbe{done: make(chan struct{})}
+		s.startupProbe = probe
+		go s.runStartupProbe(probe)
+	}
+	s.startupMu.Unlock()
+	select {
+	case <-probe.done:
+		return probe.err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	s.mu.Lock()
-	s.instances[instance.Id] = instance
-	s.mu.Unlock()
+func (s *Simple) runStartupProbe(probe *startupProbe) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.cfg().StartupTimeout)
+	defer cancel()
+	err := s.StartupProbe(ctx)
+	s.startupMu.Lock()
+	if err == nil {
+		atomic.StoreInt32(&s.startupReady, 1)
+	}
+	s.startupProbe = nil
+	s.startupMu.Unlock()
+	probe.err = err
+	close(probe.done)
+}
 
-	//notify
-	go func() {
-		log.Printf("createInstance notify request, instance: %s", instance.Id)
-		s.notifyRequest(instance)
-	}()
-	go atomic.CompareAndSwapInt64(&s.creatingDuration, 0, int64(time.Since(creatingTime)))
-	log.Printf("request id: %s, instance %s for app %s is created, init latency: %dms", requestId, instance.Id, instance.Meta.Key, instance.InitDurationInMs)
+// StartupProbe 反复 Ping 平台直到成功或 ctx 结束
+func (s *Simple) StartupProbe(ctx context.Context) error {
+	for {
+		err := s.platformClient.Ping(ctx)
+		if err == nil {
+			s.logger.InfoContext(ctx, "platform is ready", "metaKey", s.metaData.Key)
+			return nil
+		}
+		s.logger.WarnContext(ctx, "platform is not ready", "metaKey", s.metaData.Key, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
 }
 
-func (s *Simple) CheckLive() bool {
-	// 超过45秒没有消息的时候，返回false
-	return true
+// 从空闲队列中挑选实例，调用方需持有 idleMu
+// 优先选择 sticky key 上次分配的实例，其次是 affinity key 相同的实例、routing key 哈希到的实例，再次是上次服务过相同 meta key 的实例，缓存更热；都找不到时退回队首
+// 请求要求网络等级时，选中的实例等级不匹配则改选第一个等级匹配的实例
+// hint 不为空且能满足时，直接使用按 hint 选出的实例
+// 请求带有 RequiredTags 时只选择标签都满足的实例，不退回其他实例
+func (s *Simple) selectIdleInstance(request *pb.AssignRequest, hint *AssignHint) *list.Element {
+	if len(request.RequiredTags) > 0 {
+		return s.selectIdleByTags(request.RequiredTags, hint)
+	}
+	if !hint.isZero() {
+		if element := s.selectIdleByHint(hint); element != nil {
+			return element
+		}
+	}
+	element := s.selectIdleByPreference(request)
+	tier := request.RequiredNetworkTier
+	if element == nil || tier == "" || element.Value.(*model2.Instance).NetworkTier == tier {
+		return element
+	}
+	// 优先选择网络等级匹配的实例，没有时退回任意等级
+	for e := s.idleInstance.Front(); e != nil; e = e.Next() {
+		if e.Value.(*model2.Instance).NetworkTier == tier {
+			return e
+		}
+	}
+	atomic.AddUint64(&s.networkTierMismatches, 1)
+	return element
 }
 
-func (s *Simple) Clear(rate float64) {
-	// 按照比例释放idleInstance
+// NetworkTierMismatches 返回没有匹配网络等级的空闲实例、分配了其他等级实例的次数
+func (s *Simple) NetworkTierMismatches() uint64 {
+	return atomic.LoadUint64(&s.networkTierMismatches)
+}
+
+// 按 sticky key、affinity key、routing key、meta key 的顺序挑选实例，调用方需持有 idleMu
+func (s *Simple) selectIdleByPreference(request *pb.AssignRequest) *list.Element {
+	if instanceId := s.stickyInstance(request.StickyKey); instanceId != "" {
+		for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+			if element.Value.(*model2.Instance).Id == instanceId {
+				return element
+			}
+		}
+	}
+	if element := s.affinityIdleInstance(request.AffinityKey); element != nil {
+		return element
+	}
+	if element := s.routedIdleInstance(request.RoutingKey); element != nil {
+		return element
+	}
+	if s.cfg().IdleQueueOrder == config.IdleQueueWeightedLeastConn {
+		return s.selectIdleByWeightedConn()
+	}
+	// 空闲队列按空闲时间从新到旧排列，FIFO 从队尾开始
+	first, next := s.idleInstance.Front, (*list.Element).Next
+	if s.cfg().IdleQueueOrder == config.IdleQueueFIFO {
+		first, next = s.idleInstance.Back, (*list.Element).Prev
+	}
+	for element := first(); element != nil; element = next(element) {
+		if element.Value.(*model2.Instance).LastMetaKey == request.MetaData.Key {
+			return element
+		}
+	}
+	return first()
+}
+
+// 按 routing key 的哈希值选择空闲队列中第 hash % len 个实例，调用方需持有 idleMu
+// 空闲队列变化后同一个 routing key 可能落到不同实例上，只是尽力而为的亲和性
+func (s *Simple) routedIdleInstance(routingKey string) *list.Element {
+	if routingKey == "" || s.idleInstance.Len() == 0 {
+		return nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(routingKey))
+	n := int(h.Sum32() % uint32(s.idleInstance.Len()))
+	element := s.idleInstance.Front()
+	for i := 0; i < n; i++ {
+		element = element.Next()
+	}
+	return element
+}
+
+// 查询 sticky key 上次分配的实例 id，过期的记录会被删除
+func (s *Simple) stickyInstance(stickyKey string) string {
+	if stickyKey == "" {
+		return ""
+	}
+	s.stickyMu.Lock()
+	defer s.stickyMu.Unlock()
+	entry, ok := s.stickyMap[stickyKey]
+	if !ok {
+		return ""
+	}
+	if ttl := s.cfg().StickyKeyTTL; ttl > 0 && time.Since(entry.assignedAt) > ttl {
+		delete(s.stickyMap, stickyKey)
+		return ""
+	}
+	return entry.instanceId
+}
+
+// 记录 sticky key 本次分配的实例
+func (s *Simple) recordSticky(stickyKey, instanceId string) {
+	if stickyKey == "" {
+		return
+	}
+	s.stickyMu.Lock()
+	s.stickyMap[stickyKey] = stickyEntry{instanceId: instanceId, assignedAt: time.Now()}
+	s.stickyMu.Unlock()
+}
+
+// 清理过期的 sticky key
+func (s *Simple) expireStickyKeys() {
+	ttl := s.cfg().StickyKeyTTL
+	if ttl <= 0 {
+		return
+	}
+	s.stickyMu.Lock()
+	defer s.stickyMu.Unlock()
+	for key, entry := range s.stickyMap {
+		if time.Since(entry.assignedAt) > ttl {
+			delete(s.stickyMap, key)
+		}
+	}
+}
+
+func (s *Simple) Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
+	reply, err := s.idle(ctx, request)
+	s.journalIdle(request, err)
+	return reply, err
+}
+
+func (s *Simple) idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
+	if request.Assigment == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "assignment is nil")
+	}
+	go s.runtimeStatus.IdleStart(request.Assigment.RequestId)
+	ctx, span := s.tracer.Start(ctx, "scaler.Idle", trace.WithAttributes(
+		attribute.String("requestId", request.Assigment.RequestId),
+		attribute.String("instanceId", request.Assigment.InstanceId),
+	))
+	defer span.End()
+	s.markActive()
+	reply := &pb.IdleReply{
+		Status:       pb.Status_Ok,
+		ErrorMessage: nil,
+	}
+	start := time.Now()
+	instanceId := request.Assigment.InstanceId
+	defer func() {
+		s.logger.InfoContext(ctx, "idle", "requestId", request.Assigment.RequestId, "instanceId", instanceId, "duration", time.Since(start))
+	}()
+	//log.Printf("Idle, request id: %s", request.Assigment.RequestId)
+	needDestroy := false
+	destroyReason := EvictReasonBadInstance
+	var evicted, recycling *model2.Instance
+	if request.Result != nil && request.Result.NeedDestroy != nil && *request.Result.NeedDestroy {
+		needDestroy = true
+	}
+	defer func() {
+		// instancesMu 已经释放
+		if recycling != nil {
+			s.resetOrDestroy(ctx, request.Assigment.RequestId, recycling)
+		}
+		if needDestroy && evicted != nil {
+			s.deleteSlot(ctx, request.Assigment.RequestId, evicted, destroyReason)
+			go s.notifyEviction(evicted, destroyReason)
+		}
+		s.checkDrainComplete()
+	}()
+	s.logger.InfoContext(ctx, "idle", "requestId", request.Assigment.RequestId)
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	if instance := s.instances[instanceId]; instance != nil {
+		// 客户端带上的 slot id 必须与实例记录的一致
+		if slotId := request.Assigment.SlotId; slotId != "" && slotId != instance.Slot.Id {
+			needDestroy = false
+			return nil, status.Errorf(codes.InvalidArgument, "request id %s, instance %s slot id mismatch: %s", request.Assigment.RequestId, instanceId, slotId)
+		}
+		if executionStats := request.Result.GetExecutionStats(); executionStats != nil {
+			stats := &model2.ExecutionStats{
+				CpuMs:           executionStats.CpuMs,
+				MemPeakMb:       executionStats.MemPeakMb,
+				NetworkBytesIn:  executionStats.NetworkBytesIn,
+				NetworkBytesOut: executionStats.NetworkBytesOut,
+			}
+			instance.LastExecutionStats = stats
+			go s.runtimeStatus.ObserveExecution(stats)
+		}
+		if instance.PendingEviction && !needDestroy {
+			needDestroy = true
+			destroyReason = EvictReasonGraceful
+		}
+		if needDestroy && destroyReason == EvictReasonBadInstance && s.canReset(instance) {
+			s.releaseInFlight()
+			// 重置期间槽位保持占用，从空闲队列移除，避免被分配或回收
+			recycling = instance
+			s.idleMu.Lock()
+			s.removeIdle(instanceId)
+			s.idleMu.Unlock()
+			s.logger.InfoContext(ctx, "instance needs to be destroyed, reset it instead", "requestId", request.Assigment.RequestId, "instanceId", instanceId)
+			return reply, nil
+		}
+		if needDestroy {
+			s.releaseInFlight()
+			evicted = instance
+			s.logger.InfoContext(ctx, "instance needs to be destroyed", "requestId", request.Assigment.RequestId, "instanceId", instanceId)
+			delete(s.instances, instanceId)
+			s.idleMu.Lock()
+			s.removeIdle(instanceId)
+			s.idleMu.Unlock()
+			return reply, nil
+		}
+
+		if !instance.IsBusy() {
+			s.logger.InfoContext(ctx, "instance already freed", "requestId", request.Assigment.RequestId, "instanceId", instanceId)
+			return reply, nil
+		}
+
+		// 实例原本槽位已满，不在空闲队列中，需要重新通知等待的请求
+		// 创建完成的实例可能正在放入空闲队列，释放槽位需持有 idleMu
+		s.idleMu.Lock()
+		wasFull := !instance.HasFreeSlot()
+		instance.ReleaseSlot()
+		s.idleMu.Unlock()
+		s.releaseInFlight()
+		// 这里持有 instancesMu 直到 Idle 返回，放到新的 goroutine 中通知，避免与 longPollingMu、idleMu 嵌套
+		// notifyRequests 不获取 instancesMu，即使先于 Idle 返回执行也不会死锁
+		if !wasFull && s.IsDraining() && !instance.IsBusy() {
+			// 下线中仍在空闲队列中的多槽位实例在最后一个请求结束时回收，槽位已满的实例先通知等待的请求
+			needDestroy, destroyReason, evicted = true, EvictReasonDrained, instance
+			delete(s.instances, instanceId)
+			s.idleMu.Lock()
+			s.removeIdle(instanceId)
+			s.idleMu.Unlock()
+			s.logger.InfoContext(ctx, "scaler is draining, destroy instance", "requestId", request.Assigment.RequestId, "instanceId", instanceId)
+		} else if wasFull {
+			go func() {
+				s.logger.InfoContext(ctx, "idle notify request", "instanceId", instance.Id)
+				s.notifyRequest(instance)
+			}()
+		} else if !instance.IsBusy() && !s.cfg().SortIdleByInitDuration {
+			// 仍在空闲队列中的多槽位实例变为完全空闲，移到队首保持空闲时间顺序
+			s.idleMu.Lock()
+			for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+				if element.Value.(*model2.Instance) == instance {
+					s.idleInstance.MoveToFront(element)
+					break
+				}
+			}
+			s.idleMu.Unlock()
+		}
+
+	} else {
+		// 实例在请求处理期间被强制回收时，回收时已经释放了并发名额
+		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("request id %s, instance %s not found", request.Assigment.RequestId, instanceId))
+	}
+	return &pb.IdleReply{
+		Status:       pb.Status_Ok,
+		ErrorMessage: nil,
+	}, nil
+}
+
+// 实例是否可以原地重置，只有本次请求在处理时才能重置，调用方需持有 instancesMu
+func (s *Simple) canReset(instance *model2.Instance) bool {
+	if _, ok := s.platformClient.(platform_client2.InstanceResetter); !ok {
+		return false
+	}
+	return instance.AllowRecycle && instance.RecycleCount < instance.MaxRecycleCount && atomic.LoadInt32(&instance.UsedSlots) == 1
+}
+
+// 原地重置实例，成功后放回空闲队列，失败时销毁
+func (s *Simple) resetOrDestroy(ctx context.Context, requestId string, instance *model2.Instance) {
+	resetter := s.platformClient.(platform_client2.InstanceResetter)
+	err := resetter.ResetInstance(ctx, requestId, instance.Slot.Id)
+	s.instancesMu.Lock()
+	// 重置期间可能已被 ForceEvict 回收
+	if s.instances[instance.Id] != instance {
+		s.instancesMu.Unlock()
+		return
+	}
+	if err == nil {
+		instance.RecycleCount++
+		instance.ReleaseSlot()
+		s.instancesMu.Unlock()
+		s.logger.Info("instance is reset", "requestId", requestId, "instanceId", instance.Id, "recycleCount", instance.RecycleCount)
+		s.notifyRequest(instance)
+		return
+	}
+	delete(s.instances, instance.Id)
+	s.instancesMu.Unlock()
+	s.logger.Error("reset instance failed", "requestId", requestId, "instanceId", instance.Id, "error", err)
+	s.deleteSlot(ctx, requestId, instance, EvictReasonBadInstance)
+	go s.notifyEviction(instance, EvictReasonBadInstance)
+}
+
+// ForceEvict 立即回收实例，实例上正在处理的请求会受影响
+func (s *Simple) ForceEvict(instanceId string) error {
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	instance := s.instances[instanceId]
+	if instance == nil {
+		return status.Errorf(codes.NotFound, "instance %s not found", instanceId)
+	}
+	s.evictLocked(instance, EvictReasonForce)
+	return nil
+}
+
+// GracefulEvict 回收实例，waitForIdle 为 true 且实例忙碌时，等请求结束调用 Idle 时再回收
+// waitForIdle 为 false 时等同于 ForceEvict
+func (s *Simple) GracefulEvict(instanceId string, waitForIdle bool) error {
+	if !waitForIdle {
+		return s.ForceEvict(instanceId)
+	}
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	instance := s.instances[instanceId]
+	if instance == nil {
+		return status.Errorf(codes.NotFound, "instance %s not found", instanceId)
+	}
+	if instance.IsBusy() {
+		s.logger.Info("instance is busy, evict after idle", "instanceId", instanceId)
+		instance.PendingEviction = true
+		return nil
+	}
+	s.evictLocked(instance, EvictReasonGraceful)
+	return nil
+}
+
+// KeepAliveInstance 重置空闲实例的空闲时间，使其在 duration 内不会被 gc 回收
+// duration 不超过 IdleDurationBeforeGC 时等同于刚刚变为空闲
+func (s *Simple) KeepAliveInstance(instanceId string, duration time.Duration) error {
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	if s.instances[instanceId] == nil {
+		return status.Errorf(codes.NotFound, "instance %s of app %s not found", instanceId, s.metaData.Key)
+	}
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		if instance.Id != instanceId {
+			continue
+		}
+		if instance.IsBusy() {
+			break
+		}
+		lastIdleTime := time.Now()
+		if extra := duration - s.cfg().IdleDurationBeforeGC; extra > 0 {
+			lastIdleTime = lastIdleTime.Add(extra)
+		}
+		instance.LastIdleTime = lastIdleTime
+		// 空闲队列按空闲时间从新到旧排列，gc 从队尾开始回收
+		if !s.cfg().SortIdleByInitDuration {
+			s.idleInstance.MoveToFront(element)
+		}
+		return nil
+	}
+	return status.Errorf(codes.FailedPrecondition, "instance %s of app %s is not idle", instanceId, s.metaData.Key)
+}
+
+// PromoteToIdle 把已注册但不在空闲队列中的实例交给等待的请求或放回空闲队列，用于预热或人工重置后的外部触发
+// 检查和放回在同一个 longPollingMu、idleMu 临界区内完成，与创建完成的通知并发时实例也只会进入空闲队列一次
+func (s *Simple) PromoteToIdle(instanceId string) error {
+	// longPollingMu 不能与 instancesMu 嵌套，先取出实例
+	s.instancesMu.RLock()
+	instance := s.instances[instanceId]
+	s.instancesMu.RUnlock()
+	if instance == nil {
+		return status.Errorf(codes.NotFound, "instance %s of app %s not found", instanceId, s.metaData.Key)
+	}
+	s.longPollingMu.Lock()
+	s.idleMu.Lock()
+	if _, ok := s.idleMembers[instance]; ok {
+		s.idleMu.Unlock()
+		s.longPollingMu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "instance %s of app %s is already idle", instanceId, s.metaData.Key)
+	}
+	if instance.IsBusy() || instance.PendingEviction {
+		s.idleMu.Unlock()
+		s.longPollingMu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "instance %s of app %s is busy or pending eviction", instanceId, s.metaData.Key)
+	}
+	s.logger.Info("promote instance to idle", "metaKey", s.metaData.Key, "instanceId", instanceId)
+	overflow, drained := s.notifyRequestsLocked([]*model2.Instance{instance})
+	s.idleMu.Unlock()
+	s.longPollingMu.Unlock()
+	s.evictNotIdled(overflow, EvictReasonIdleOverflow)
+	s.evictNotIdled(drained, EvictReasonDrained)
+	return nil
+}
+
+// 从 instances 和空闲队列中删除实例并异步销毁 slot，调用方需持有 instancesMu
+// 实例上正在处理的请求之后 Idle 会返回 NotFound，这里释放它们占用的并发名额
+func (s *Simple) evictLocked(instance *model2.Instance, reason string) {
+	for n := atomic.LoadInt32(&instance.UsedSlots); n > 0; n-- {
+		s.releaseInFlight()
+	}
+	delete(s.instances, instance.Id)
+	s.idleMu.Lock()
+	s.removeIdle(instance.Id)
+	s.idleMu.Unlock()
+	s.goDestroy(instance, reason)
+}
+
+// 在后台销毁实例，Shutdown 会等待其完成；已经关闭时与留在 instances 中的实例一样不再销毁
+func (s *Simple) goDestroy(instance *model2.Instance, reason string) {
+	if !s.goBackground(func() { s.destroyInstance(instance, reason) }) {
+		s.logger.Warn("scaler is shut down, slot is not destroyed", "metaKey", s.metaData.Key, "instanceId", instance.Id, "reason", reason)
+	}
+}
+
+// 销毁已经从 instances 和空闲队列中删除的实例
+func (s *Simple) destroyInstance(instance *model2.Instance, reason string) {
+	ctx, cancel := s.phaseContext(s.cfg().SlotDeleteTimeout)
+	defer cancel()
+	s.deleteSlot(ctx, uuid.NewString(), instance, reason)
+	s.notifyEviction(instance, reason)
+}
+
+// 调用配置的 EvictionCallback
+func (s *Simple) notifyEviction(instance *model2.Instance, reason string) {
+	if callback := s.cfg().EvictionCallback; callback != nil {
+		callback(instance, reason)
+	}
+}
+
+// 从空闲队列中删除实例，调用方需持有 idleMu
+func (s *Simple) removeIdle(instanceId string) bool {
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		if element.Value.(*model2.Instance).Id == instanceId {
+			s.removeIdleElement(element)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simple) deleteSlot(ctx context.Context, requestId string, instance *model2.Instance, reason string) {
+	slotId, instanceId, metaKey := instance.Slot.Id, instance.Id, instance.Meta.Key
+	s.logger.InfoContext(ctx, "start delete instance", "metaKey", metaKey, "instanceId", instanceId, "slotId", slotId)
+	// 异常实例的 slot 不复用；复用池只接收规格与应用 meta 相同的 slot，取出时按 meta 的内存匹配
+	if reason != EvictReasonBadInstance && instance.Meta.MemoryInMb == s.metaData.MemoryInMb && s.reuseSlot(ctx, requestId, instance.Slot) {
+		s.logger.InfoContext(ctx, "slot is reset for reuse", "instanceId", instanceId, "slotId", slotId)
+		s.audit(AuditActionReset, slotId, instanceId, reason)
+		return
+	}
+	if err := s.platformClient.DestroySLot(ctx, requestId, slotId, reason); err != nil {
+		s.logger.ErrorContext(ctx, "delete instance failed", "metaKey", metaKey, "instanceId", instanceId, "slotId", slotId, "error", err)
+	}
+	s.audit(AuditActionDelete, slotId, instanceId, reason)
+	s.releaseMemory(instance.Meta.MemoryInMb)
+}
+
+// 重置 slot 并放入复用池，复用池已满或平台不支持重置时返回 false
+func (s *Simple) reuseSlot(ctx context.Context, requestId string, slot *model2.Slot) bool {
+	resetter, ok := s.platformClient.(platform_client2.SlotResetter)
+	if !ok || s.slotReusePool == nil || len(s.slotReusePool) >= cap(s.slotReusePool) {
+		return false
+	}
+	if err := resetter.ResetSlot(ctx, requestId, slot.Id); err != nil {
+		s.logger.ErrorContext(ctx, "reset slot failed", "slotId", slot.Id, "error", err)
+		return false
+	}
+	select {
+	case s.slotReusePool <- slot:
+		return true
+	default:
+		return false
+	}
+}
+
+// SlotReusedTotal 返回从复用池中取出 slot 创建实例的次数
+func (s *Simple) SlotReusedTotal() uint64 {
+	return atomic.LoadUint64(&s.slotReused)
+}
+
+// 预留实例内存，超过 ResourceBudgetMb 时返回 ResourceExhausted
+func (s *Simple) reserveMemory(memoryInMb uint64) error {
+	budget := s.cfg().ResourceBudgetMb
+	memory := int64(memoryInMb)
+	if allocated := atomic.AddInt64(&s.allocatedMemoryMb, memory); budget > 0 && allocated > budget {
+		atomic.AddInt64(&s.allocatedMemoryMb, -memory)
+		return status.Errorf(codes.ResourceExhausted, "memory budget exceeded: allocated %dMB, request %dMB, budget %dMB", allocated-memory, memory, budget)
+	}
+	return nil
+}
+
+func (s *Simple) releaseMemory(memoryInMb uint64) {
+	atomic.AddInt64(&s.allocatedMemoryMb, -int64(memoryInMb))
+}
+
+// 周期回收
+func (s *Simple) gcLoop() {
+	s.logger.Info("gc loop is started", "metaKey", s.metaData.Key)
+	interval := s.cfg().GcInterval
+	atomic.StoreInt64(&s.gcInterval, int64(interval))
+	ticker := time.NewTicker(interval)
+	defer func() {
+		ticker.Stop()
+	}()
+	idleTicks := 0
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.gcConfigCh:
+			// 配置重新加载后从新的 GcInterval 开始
+			interval = s.cfg().GcInterval
+			idleTicks = 0
+			atomic.StoreInt64(&s.gcInterval, int64(interval))
+			ticker.Reset(interval)
+			s.logger.Info("gc interval is reset", "metaKey", s.metaData.Key, "interval", interval)
+			continue
+		case <-ticker.C:
+		}
+		s.expireStickyKeys()
+		s.expireAffinity()
+		s.compactIdleList()
+		var expired []*model2.Instance
+		if s.cfg().EvictByScore {
+			expired = s.removeExpiredIdle()
+		} else {
+			floor := s.idleFloor()
+			expired = s.drainIdle(config.EvictPolicyIdleTime, func(instance *model2.Instance) bool {
+				// fn 在 idleMu 保护下调用，可以直接读取空闲队列长度
+				return s.idleInstance.Len() > floor && time.Since(instance.LastIdleTime) > s.cfg().IdleDurationBeforeGC
+			})
+			// 按 CPU 加权的空闲分数排序，分数高的先回收
+			sortByEvictionScore(expired)
+		}
+		for _, instance := range expired {
+			instance := instance
+			idleDuration := time.Since(instance.LastIdleTime)
+			// 回收实例
+			started := s.goBackground(func() {
+				reason := fmt.Sprintf("Idle duration: %fs, excceed configured duration: %fs", idleDuration.Seconds(), s.cfg().IdleDurationBeforeGC.Seconds())
+				ctx, cancel := s.phaseContext(s.cfg().SlotDeleteTimeout)
+				defer cancel()
+				s.deleteSlot(ctx, uuid.NewString(), instance, reason)
+				s.notifyEviction(instance, EvictReasonGC)
+			})
+			if !started {
+				s.logger.Warn("scaler is shut down, slot is not destroyed", "metaKey", s.metaData.Key, "instanceId", instance.Id, "reason", EvictReasonGC)
+			}
+		}
+		collected := len(expired) + s.evictIdleOverMemory()
+		s.checkDrainComplete()
+		if next := s.nextGcInterval(interval, collected, &idleTicks); next != interval {
+			s.logger.Info("gc interval changes", "metaKey", s.metaData.Key, "from", interval, "to", next, "collected", collected)
+			interval = next
+			atomic.StoreInt64(&s.gcInterval, int64(interval))
+			ticker.Stop()
+			ticker = time.NewTicker(interval)
+		}
+	}
+}
+
+// 连续没有回收实例多少次后 gc 间隔加倍
+const gcIdleTicksBeforeBackoff = 3
+
+// 根据本次回收的实例数计算下一次 gc 间隔，idleTicks 记录连续没有回收的次数
+func (s *Simple) nextGcInterval(interval time.Duration, collected int, idleTicks *int) time.Duration {
+	threshold := s.cfg().GcChurnThreshold
+	if threshold <= 0 {
+		return interval
+	}
+	if collected == 0 {
+		*idleTicks++
+	} else {
+		*idleTicks = 0
+	}
+	maxInterval := s.cfg().GcInterval
+	minInterval := time.Second
+	if minInterval > maxInterval {
+		minInterval = maxInterval
+	}
+	switch {
+	case collected > threshold:
+		interval /= 2
+		if interval < minInterval {
+			interval = minInterval
+		}
+	case *idleTicks >= gcIdleTicksBeforeBackoff:
+		*idleTicks = 0
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+	return interval
+}
+
+// DrainIdle 在 instancesMu 和 idleMu 保护下按配置的 EvictPolicy 依次对空闲实例调用 fn
+// 默认从空闲队列队尾（空闲最久）开始，EvictPolicyPreserveFastInit 时从 InitDurationInMs 最大的实例开始
+// fn 返回 true 时把实例从空闲队列和 instances 中删除并继续，返回 false 时停止，返回被删除的实例
+// 仍有请求在处理的实例直接跳过，不会传给 fn
+// 调用方负责销毁返回的实例，fn 中不能再获取 instancesMu 和 idleMu
+func (s *Simple) DrainIdle(fn func(instance *model2.Instance) bool) []*model2.Instance {
+	return s.drainIdle(s.cfg().EvictPolicy, fn)
+}
+
+func (s *Simple) drainIdle(policy config.EvictPolicy, fn func(instance *model2.Instance) bool) []*model2.Instance {
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	// 默认顺序从队尾向前遍历，不需要复制整个队列
+	first := s.idleInstance.Back()
+	next := (*list.Element).Prev
+	if policy == config.EvictPolicyPreserveFastInit {
+		var candidates []*list.Element
+		for element := s.idleInstance.Back(); element != nil; element = element.Prev() {
+			candidates = append(candidates, element)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Value.(*model2.Instance).InitDurationInMs > candidates[j].Value.(*model2.Instance).InitDurationInMs
+		})
+		first = nil
+		if len(candidates) > 0 {
+			first = candidates[0]
+		}
+		next = func(*list.Element) *list.Element {
+			candidates = candidates[1:]
+			if len(candidates) == 0 {
+				return nil
+			}
+			return candidates[0]
+		}
+	}
+	var drained []*model2.Instance
+	for element := first; element != nil; {
+		// 删除元素后无法再取得前一个元素，先取出
+		following := next(element)
+		instance := element.Value.(*model2.Instance)
+		// 仍有请求在处理的多槽位实例跳过，不阻塞之后的实例
+		if instance.IsBusy() {
+			element = following
+			continue
+		}
+		if !fn(instance) {
+			break
+		}
+		s.removeIdleElement(element)
+		delete(s.instances, instance.Id)
+		drained = append(drained, instance)
+		element = following
+	}
+	return drained
+}
+
+// 扫描整个空闲队列，按 Instance.Score 从高到低删除过期且没有请求在处理的实例，空闲实例数降到 idleFloor 时停止
+// 返回的实例按 Score 从高到低排列
+func (s *Simple) removeExpiredIdle() []*model2.Instance {
+	s.instancesMu.Lock()
+	defer s.instancesMu.Unlock()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	n := s.idleInstance.Len() - s.idleFloor()
+	if n <= 0 {
+		return nil
+	}
+	var expired []*model2.Instance
+	elements := make(map[*model2.Instance]*list.Element)
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		if !instance.IsBusy() && time.Since(instance.LastIdleTime) > s.cfg().IdleDurationBeforeGC {
+			expired = append(expired, instance)
+			elements[instance] = element
+		}
+	}
+	s.sortByInstanceScore(expired)
+	if len(expired) > n {
+		expired = expired[:n]
+	}
+	for _, instance := range expired {
+		s.removeIdleElement(elements[instance])
+		delete(s.instances, instance.Id)
+	}
+	return expired
+}
+
+// 按 Instance.Score 从高到低排序
+func (s *Simple) sortByInstanceScore(instances []*model2.Instance) {
+	idleDuration := s.cfg().IdleDurationBeforeGC
+	maxInitMs := atomic.LoadInt64(&s.maxInitMs)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].Score(idleDuration, maxInitMs) > instances[j].Score(idleDuration, maxInitMs)
+	})
+}
+
+// 删除空闲队列中已经不在 instances 里的实例，避免把已回收的实例分配出去
+func (s *Simple) compactIdleList() {
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	for element := s.idleInstance.Front(); element != nil; {
+		next := element.Next()
+		instance := element.Value.(*model2.Instance)
+		if s.instances[instance.Id] != instance {
+			s.logger.Info("remove stale idle instance", "instanceId", instance.Id)
+			s.removeIdleElement(element)
+		}
+		element = next
+	}
+}
+
+// 空闲实例的回收分数，空闲越久、上次请求消耗的 CPU 越少，分数越高越应该先回收
+// 消耗 CPU 多的实例通常缓存和 JIT 更热，重建代价更大
+func evictionScore(instance *model2.Instance) float64 {
+	score := time.Since(instance.LastIdleTime).Seconds()
+	if stats := instance.LastExecutionStats; stats != nil {
+		score /= 1 + float64(stats.CpuMs)/1000
+	}
+	return score
+}
+
+// 按回收分数从高到低排序
+func sortByEvictionScore(instances []*model2.Instance) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		return evictionScore(instances[i]) > evictionScore(instances[j])
+	})
+}
+
+func (s *Simple) Stats() Stats {
+	s.instancesMu.RLock()
+	total := len(s.instances)
+	s.instancesMu.RUnlock()
+	s.idleMu.Lock()
+	idle := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	return Stats{
+		TotalInstance:     total,
+		TotalIdleInstance: idle,
+		AllocatedMemoryMb: atomic.LoadInt64(&s.allocatedMemoryMb),
+		CircuitState:      s.breaker.State().String(),
+		GcInterval:        time.Duration(atomic.LoadInt64(&s.gcInterval)),
+	}
+}
+
+// DetailedStats 返回 Stats、Assign 耗时的 P50、P95、P99、Init 耗时分布以及请求速率
+func (s *Simple) DetailedStats() DetailedStats {
+	return DetailedStats{
+		Stats:                 s.Stats(),
+		AssignP50:             s.runtimeStatus.P50(),
+		AssignP95:             s.runtimeStatus.P95(),
+		AssignP99:             s.runtimeStatus.P99(),
+		InitDurationHistogram: s.runtimeStatus.GetInitDurationHistogram(),
+		CurrentRPS:            s.runtimeStatus.CurrentRPS(),
+		PeakRPS:               s.runtimeStatus.PeakRPS(),
+	}
+}
+
+// GetIdlePage 按空闲队列顺序返回从 offset 开始的至多 limit 个空闲实例
+func (s *Simple) GetIdlePage(offset, limit int) []*InstanceDetail {
+	if offset < 0 || limit <= 0 {
+		return nil
+	}
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	element := s.idleInstance.Front()
+	for i := 0; i < offset && element != nil; i++ {
+		element = element.Next()
+	}
+	var page []*InstanceDetail
+	for ; element != nil && len(page) < limit; element = element.Next() {
+		page = append(page, newInstanceDetail(element.Value.(*model2.Instance)))
+	}
+	return page
+}
+
+func newInstanceDetail(instance *model2.Instance) *InstanceDetail {
+	return &InstanceDetail{
+		InstanceId:       instance.Id,
+		SlotId:           instance.Slot.Id,
+		MetaKey:          instance.Meta.Key,
+		NetworkTier:      instance.NetworkTier,
+		Tags:             instance.Tags,
+		UsedSlots:        atomic.LoadInt32(&instance.UsedSlots),
+		InstanceCapacity: instance.InstanceCapacity,
+		PendingEviction:  instance.PendingEviction,
+		LastIdleTime:     instance.LastIdleTime,
+		CustomData:       instance.CustomDataSnapshot(),
+	}
+}
+
+// DumpStats 以 "key: value" 的形式逐行输出可读的统计信息
+func (s *Simple) DumpStats(w io.Writer) error {
+	stats := s.Stats()
+	s.longPollingMu.Lock()
+	pending := s.longPollingHeap.Len()
+	s.longPollingMu.Unlock()
+	var hitRatio float64
+	if total := atomic.LoadUint64(&s.assignTotal); total > 0 {
+		hitRatio = float64(atomic.LoadUint64(&s.idlePoolHits)) / float64(total)
+	}
+	snapshot := s.runtimeStatus.MetricsSnapshot()
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(tw, "app:\t%s\n", s.metaData.Key)
+	fmt.Fprintf(tw, "idle_instances:\t%d\n", stats.TotalIdleInstance)
+	fmt.Fprintf(tw, "total_instances:\t%d\n", stats.TotalInstance)
+	fmt.Fprintf(tw, "creating:\t%d\n", atomic.LoadInt64(&s.creatingNum))
+	fmt.Fprintf(tw, "pending_requests:\t%d\n", pending)
+	fmt.Fprintf(tw, "rps:\t%.1f\n", s.runtimeStatus.getRequestRate())
+	fmt.Fprintf(tw, "request_cost_ms:\t%.1f\n", float64(snapshot.RequestCostTime)/float64(time.Millisecond))
+	fmt.Fprintf(tw, "mean_assign_latency_ms:\t%.1f\n", float64(snapshot.MeanAssignLatency)/float64(time.Millisecond))
+	fmt.Fprintf(tw, "p95_latency_ms:\t%.1f\n", float64(s.runtimeStatus.P95())/float64(time.Millisecond))
+	fmt.Fprintf(tw, "pool_hit_ratio:\t%.2f\n", hitRatio)
+	fmt.Fprintf(tw, "allocated_memory_mb:\t%d\n", stats.AllocatedMemoryMb)
+	return tw.Flush()
+}
+
+// InstanceCountByStatus 按状态统计实例数量
+// warmup 为已注册、没有请求在处理但不在空闲队列中的实例，例如初始化完成尚未通知或重置后等待 PromoteToIdle 的实例
+// 当前没有隔离状态，quarantined 固定为 0，保留键以便监控面板保持稳定
+func (s *Simple) InstanceCountByStatus() map[string]int {
+	counts := map[string]int{
+		"idle":           0,
+		"busy":           0,
+		"creating":       int(atomic.LoadInt64(&s.creatingNum)),
+		"quarantined":    0,
+		"expire_pending": 0,
+		"warmup":         0,
+	}
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	for _, instance := range s.instances {
+		_, idle := s.idleMembers[instance]
+		switch {
+		case instance.PendingEviction:
+			counts["expire_pending"]++
+		case instance.IsBusy():
+			counts["busy"]++
+		case idle:
+			counts["idle"]++
+		default:
+			counts["warmup"]++
+		}
+	}
+	return counts
+}
+
+// BackfillIdle 并发创建实例，把空闲实例补齐到 MinIdleInstances，等待全部完成后返回第一个错误
+// 与调用方指定数量的预热不同，补齐数量由配置决定，并受 MaxInstances 和 MaxConcurrentCreations 限制
+func (s *Simple) BackfillIdle(ctx context.Context) error {
+	s.idleMu.Lock()
+	n := s.cfg().MinIdleInstances - s.idleInstance.Len()
+	s.idleMu.Unlock()
+	n = s.reserveCreations(n)
+	if n <= 0 {
+		return nil
+	}
+	s.logger.InfoContext(ctx, "backfill idle instances", "metaKey", s.metaData.Key, "count", n)
+	return s.createInstances(ctx, n)
+}
+
+// Warmup 并发创建 n 个实例，受 MaxInstances 和 MaxConcurrentCreations 限制，等待全部完成后返回第一个错误
+func (s *Simple) Warmup(ctx context.Context, n int) error {
+	n = s.reserveCreations(n)
+	if n <= 0 {
+		return nil
+	}
+	s.logger.InfoContext(ctx, "warm up instances", "metaKey", s.metaData.Key, "count", n)
+	return s.createInstances(ctx, n)
+}
+
+// 创建 n 个应用 meta 规格的实例，n 大于 1 且平台支持时一次调用创建全部 slot，否则逐个并发创建
+// 调用方需先预留 n 个创建额度
+// 平台返回 Unimplemented 后不再尝试批量创建
+func (s *Simple) createInstances(ctx context.Context, n int) error {
+	if creator, ok := s.platformClient.(platform_client2.SlotGroupCreator); ok && n > 1 && atomic.LoadInt32(&s.slotGroupUnsupported) == 0 {
+		err := s.createInstanceGroup(ctx, creator, n)
+		if status.Code(err) != codes.Unimplemented {
+			return err
+		}
+		atomic.StoreInt32(&s.slotGroupUnsupported, 1)
+		s.logger.WarnContext(ctx, "platform does not support slot groups, create slots one by one", "metaKey", s.metaData.Key)
+	}
+	return waitAll(ctx, n, func(int) error {
+		return s.createInstance(ctx, &s.metaData.Meta, uuid.NewString(), nil)
+	})
+}
+
+// 并发执行 n 个任务，等待全部完成后返回第一个错误，ctx 结束时提前返回
+func waitAll(ctx context.Context, n int, fn func(i int) error) error {
+	errCh := make(chan error, n)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(i); err != nil {
+				errCh <- err
+			}
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+	}
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+// 在 MaxInstances 和 MaxConcurrentCreations 限制下预留最多 n 个实例的创建额度，返回预留的数量
+// 预留的数量计入 creatingNum，由 createInstance 和 createInstanceGroup 在结束时释放，不创建时调用 releaseCreations
+func (s *Simple) reserveCreations(n int) int {
+	s.createBudgetMu.Lock()
+	defer s.createBudgetMu.Unlock()
+	creating := int(atomic.LoadInt64(&s.creatingNum))
+	if limit := s.cfg().MaxConcurrentCreations; limit > 0 && n > limit-creating {
+		n = limit - creating
+	}
+	if limit := s.cfg().MaxInstances; limit > 0 {
+		s.instancesMu.RLock()
+		total := len(s.instances)
+		s.instancesMu.RUnlock()
+		if n > limit-total-creating {
+			n = limit - total - creating
+		}
+	}
+	if n <= 0 {
+		return 0
+	}
+	atomic.AddInt64(&s.creatingNum, int64(n))
+	return n
+}
+
+// 释放 reserveCreations 预留但没有使用的创建额度，下线中释放后检查是否下线完成
+func (s *Simple) releaseCreations(n int) {
+	atomic.AddInt64(&s.creatingNum, -int64(n))
+	s.checkDrainComplete()
+}
+
+// 创建实例，失败时按 MaxCreateRetries 指数退避重试，重试期间仍计入 creatingNum
+// 调用方需先通过 reserveCreations 预留一个额度，返回时释放
+// tags 非空时平台需要实现 TagInitializer
+// ctx 只用于关联 trace，创建过程不随 ctx 取消
+func (s *Simple) createInstance(ctx context.Context, requestMeta *pb.Meta, requestId string, tags map[string]string) error {
+	defer s.releaseCreations(1)
+	ctx, span := s.tracer.Start(ctx, "scaler.createInstance", trace.WithAttributes(
+		attribute.String("requestId", requestId),
+		attribute.String("metaKey", requestMeta.Key),
+	))
+	defer span.End()
+	if _, ok := s.platformClient.(platform_client2.TagInitializer); len(tags) > 0 && !ok {
+		return status.Errorf(codes.Unimplemented, "platform does not support initializing instances with tags")
+	}
+	creatingTime := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := s.tryCreateInstance(ctx, requestMeta, requestId, tags, creatingTime)
+		if err == nil {
+			return nil
+		}
+		// 超出内存预算或熔断时重试没有意义，超时直接返回，让等待的请求尽快收到 DeadlineExceeded
+		if attempt > s.cfg().MaxCreateRetries || status.Code(err) == codes.ResourceExhausted || errors.Is(err, ErrCircuitOpen) || createErrorCode(err) == codes.DeadlineExceeded {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return err
+		}
+		delay := createBackoff(attempt)
+		s.logger.Warn("create instance failed, retry later", "metaKey", requestMeta.Key, "requestId", requestId, "attempt", attempt, "duration", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.done:
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// 第 attempt 次失败后的重试间隔：100ms * 2^(attempt-1)，最长 5s，上下浮动 20%
+func createBackoff(attempt int) time.Duration {
+	delay := 5 * time.Second
+	if attempt <= 6 {
+		delay = 100 * time.Millisecond << (attempt - 1)
+	}
+	if delay > 5*time.Second {
+		delay = 5 * time.Second
+	}
+	return time.Duration(float64(delay) * (0.8 + 0.4*rand.Float64()))
+}
+
+func (s *Simple) tryCreateInstance(ctx context.Context, requestMeta *pb.Meta, requestId string, tags map[string]string, creatingTime time.Time) error {
+	if !s.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+	callStart := time.Now()
+	// 优先复用已重置的 slot，其次使用预先创建的 slot，两者的规格都与应用 meta 相同，内存已经预留
+	// 只有内存与应用 meta 相同的请求可以使用
+	var slot *model2.Slot
+	if requestMeta.MemoryInMb == s.metaData.MemoryInMb {
+		select {
+		case slot = <-s.slotReusePool:
+			atomic.AddUint64(&s.slotReused, 1)
+		default:
+			slot = s.slotPool.get()
+		}
+	}
+	if slot == nil {
+		if err := s.reserveMemory(requestMeta.MemoryInMb); err != nil {
+			s.logger.Warn("create slot rejected", "metaKey", requestMeta.Key, "requestId", requestId, "error", err)
+			s.breaker.Release()
+			return err
+		}
+		//Create new Instance
+		resourceConfig := newResourceConfig(requestMeta)
+		var err error
+		slot, err = s.createSlot(ctx, requestId, &resourceConfig)
+		if err != nil {
+			s.releaseMemory(requestMeta.MemoryInMb)
+			s.logger.Error("create slot failed", "metaKey", requestMeta.Key, "requestId", requestId, "error", err)
+			s.breaker.Record(err)
+			return err
+		}
+	}
+	instanceId := uuid.NewString()
+	if err := s.initInstance(ctx, requestMeta, requestId, instanceId, slot, tags, creatingTime); err != nil {
+		// Init 失败的 slot 不再使用，销毁后再重试
+		s.destroyInitFailedSlot(ctx, requestMeta.Key, requestId, instanceId, slot)
+		s.breaker.Record(err)
+		return err
+	}
+	s.breaker.Record(nil)
+	go s.runtimeStatus.ObservePlatformCall(time.Since(callStart))
+	return nil
+}
+
+// 销毁 Init 失败的 slot，内存已在 initInstance 中释放
+func (s *Simple) destroyInitFailedSlot(ctx context.Context, metaKey, requestId, instanceId string, slot *model2.Slot) {
+	ctx, cancel := s.tracedPhaseContext(ctx, s.cfg().SlotDeleteTimeout)
+	defer cancel()
+	if err := s.platformClient.DestroySLot(ctx, requestId, slot.Id, "init failed"); err != nil {
+		s.logger.Error("delete slot failed", "metaKey", metaKey, "slotId", slot.Id, "error", err)
+	}
+	s.audit(AuditActionDelete, slot.Id, instanceId, "init failed")
+}
+
+// 按 slotCreateLimiter 限流后在 SlotCreateTimeout 内调用 CreateSlot
+func (s *Simple) createSlot(parent context.Context, requestId string, resourceConfig *model2.SlotResourceConfig) (*model2.Slot, error) {
+	// 限流等待不计入 SlotCreateTimeout，scaler 关闭时放弃等待
+	if s.slotCreateLimiter != nil {
+		if err := s.slotCreateLimiter.Wait(s.ctx); err != nil {
+			return nil, &CreateInstanceError{Phase: CreatePhaseCreateSlot, Err: err}
+		}
+	}
+	parent, span := s.tracer.Start(parent, "platform.CreateSlot")
+	defer span.End()
+	ctx, cancel := s.tracedPhaseContext(parent, s.cfg().SlotCreateTimeout)
+	defer cancel()
+	slot, err := s.platformClient.CreateSlot(ctx, requestId, resourceConfig)
+	if err != nil {
+		timeout := ctx.Err() == context.DeadlineExceeded
+		if timeout {
+			atomic.AddUint64(&s.slotCreateTimeouts, 1)
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, &CreateInstanceError{Phase: CreatePhaseCreateSlot, Timeout: timeout, Err: err}
+	}
+	return slot, nil
+}
+
+// 基于 s.ctx 派生平台调用的 ctx，timeout 小于等于 0 时不设超时
+func (s *Simple) phaseContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(s.ctx)
+	}
+	return context.WithTimeout(s.ctx, timeout)
+}
+
+// CreateTimeouts 返回 CreateSlot 和 Init 的累计超时次数
+func (s *Simple) CreateTimeouts() (slotCreate, init uint64) {
+	return atomic.LoadUint64(&s.slotCreateTimeouts), atomic.LoadUint64(&s.initTimeouts)
+}
+
+// 通过一次平台调用创建 n 个 slot，再并发初始化实例
+// 调用方需先预留 n 个创建额度；平台返回 Unimplemented 时保留额度，由调用方逐个创建，其余情况下返回时全部释放
+func (s *Simple) createInstanceGroup(ctx context.Context, creator platform_client2.SlotGroupCreator, n int) error {
+	creatingTime := time.Now()
+	memoryInMb := s.metaData.MemoryInMb
+	if err := s.reserveMemory(uint64(n) * memoryInMb); err != nil {
+		s.releaseCreations(n)
+		s.logger.WarnContext(ctx, "create slot group rejected", "metaKey", s.metaData.Key, "count", n, "error", err)
+		return err
+	}
+	requestId := uuid.NewString()
+	resourceConfig := newResourceConfig(&s.metaData.Meta)
+	slots, err := s.createSlotGroup(ctx, creator, requestId, n, &resourceConfig)
+	if err != nil {
+		if status.Code(err) != codes.Unimplemented {
+			s.releaseCreations(n)
+		}
+		s.releaseMemory(uint64(n) * memoryInMb)
+		s.logger.ErrorContext(ctx, "create slot group failed", "metaKey", s.metaData.Key, "count", n, "error", err)
+		return err
+	}
+	if len(slots) > n {
+		slots = slots[:n]
+	}
+	// 平台返回的 slot 可能少于请求的数量
+	s.releaseCreations(n - len(slots))
+	s.releaseMemory(uint64(n-len(slots)) * memoryInMb)
+	return waitAll(ctx, len(slots), func(i int) error {
+		defer s.releaseCreations(1)
+		instanceId := uuid.NewString()
+		if err := s.initInstance(ctx, &s.metaData.Meta, requestId, instanceId, slots[i], nil, creatingTime); err != nil {
+			s.destroyInitFailedSlot(ctx, s.metaData.Key, requestId, instanceId, slots[i])
+			return err
+		}
+		return nil
+	})
+}
+
+// 与逐个创建相同，经过熔断、slotCreateLimiter 限流和 SlotCreateTimeout，失败时按 MaxCreateRetries 重试
+func (s *Simple) createSlotGroup(ctx context.Context, creator platform_client2.SlotGroupCreator, requestId string, n int, resourceConfig *model2.SlotResourceConfig) ([]*model2.Slot, error) {
+	for attempt := 1; ; attempt++ {
+		slots, err := s.tryCreateSlotGroup(ctx, creator, requestId, n, resourceConfig)
+		if err == nil {
+			return slots, nil
+		}
+		if attempt > s.cfg().MaxCreateRetries || status.Code(err) == codes.Unimplemented || errors.Is(err, ErrCircuitOpen) || createErrorCode(err) == codes.DeadlineExceeded {
+			return nil, err
+		}
+		delay := createBackoff(attempt)
+		s.logger.Warn("create slot group failed, retry later", "metaKey", s.metaData.Key, "requestId", requestId, "attempt", attempt, "duration", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.done:
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *Simple) tryCreateSlotGroup(parent context.Context, creator platform_client2.SlotGroupCreator, requestId string, n int, resourceConfig *model2.SlotResourceConfig) ([]*model2.Slot, error) {
+	if !s.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+	// 每个 slot 占用一个令牌，限流等待不计入 SlotCreateTimeout
+	if s.slotCreateLimiter != nil {
+		for i := 0; i < n; i++ {
+			if err := s.slotCreateLimiter.Wait(s.ctx); err != nil {
+				s.breaker.Release()
+				return nil, &CreateInstanceError{Phase: CreatePhaseCreateSlot, Err: err}
+			}
+		}
+	}
+	parent, span := s.tracer.Start(parent, "platform.CreateSlotGroup", trace.WithAttributes(attribute.Int("count", n)))
+	defer span.End()
+	ctx, cancel := s.tracedPhaseContext(parent, s.cfg().SlotCreateTimeout)
+	defer cancel()
+	slots, err := creator.CreateSlotGroup(ctx, requestId, n, resourceConfig)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		// 平台不支持批量创建，不计入熔断
+		if status.Code(err) == codes.Unimplemented {
+			s.breaker.Release()
+			return nil, err
+		}
+		s.breaker.Record(err)
+		timeout := ctx.Err() == context.DeadlineExceeded
+		if timeout {
+			atomic.AddUint64(&s.slotCreateTimeouts, 1)
+		}
+		return nil, &CreateInstanceError{Phase: CreatePhaseCreateSlot, Timeout: timeout, Err: err}
+	}
+	s.breaker.Record(nil)
+	return slots, nil
+}
+
+func newResourceConfig(requestMeta *pb.Meta) model2.SlotResourceConfig {
+	return model2.SlotResourceConfig{
+		ResourceConfig: pb.ResourceConfig{
+			MemoryInMegabytes: requestMeta.MemoryInMb,
+		},
+	}
+}
+
+// 在 slot 上初始化实例，成功后通知等待的请求
+func (s *Simple) initInstance(parent context.Context, requestMeta *pb.Meta, requestId, instanceId string, slot *model2.Slot, tags map[string]string, creatingTime time.Time) error {
+	// 新建、复用和从快照恢复的 slot 上创建实例都会记录，实例 id 与之后的删除记录对应
+	s.audit(AuditActionCreate, slot.Id, instanceId, "")
+	meta := model2.NewMeta(requestMeta)
+	parent, span := s.tracer.Start(parent, "platform.Init", trace.WithAttributes(attribute.String("slotId", slot.Id)))
+	defer span.End()
+	ctx, cancel := s.tracedPhaseContext(parent, s.cfg().SlotInitTimeout)
+	defer cancel()
+	var instance *model2.Instance
+	var err error
+	if initializer, ok := s.platformClient.(platform_client2.TagInitializer); ok && len(tags) > 0 {
+		instance, err = initializer.InitWithTags(ctx, requestId, instanceId, slot, meta, tags)
+	} else {
+		instance, err = s.platformClient.Init(ctx, requestId, instanceId, slot, meta)
+	}
+	atomic.AddUint64(&s.initAttempts, 1)
+	if err != nil {
+		atomic.AddUint64(&s.initErrors, 1)
+		s.checkInitErrorRate()
+		s.releaseMemory(requestMeta.MemoryInMb)
+		timeout := ctx.Err() == context.DeadlineExceeded
+		if timeout {
+			atomic.AddUint64(&s.initTimeouts, 1)
+		}
+		err = &CreateInstanceError{Phase: CreatePhaseInit, Timeout: timeout, Err: err}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error("create instance failed", "metaKey", requestMeta.Key, "requestId", requestId, "error", err)
+		return err
+	}
+	if s.cfg().InstanceCapacity > 0 {
+		instance.InstanceCapacity = s.cfg().InstanceCapacity
+	}
+	instance.Weight = instanceWeight(requestMeta.MemoryInMb)
+	if s.cfg().InstanceMaxRecycleCount > 0 {
+		instance.AllowRecycle = true
+		instance.MaxRecycleCount = s.cfg().InstanceMaxRecycleCount
+	}
+	for {
+		maxInitMs := atomic.LoadInt64(&s.maxInitMs)
+		if instance.InitDurationInMs <= maxInitMs || atomic.CompareAndSwapInt64(&s.maxInitMs, maxInitMs, instance.InitDurationInMs) {
+			break
+		}
+	}
+
+	s.instancesMu.Lock()
+	s.instances[instance.Id] = instance
+	s.instancesMu.Unlock()
+	instanceInitDuration.WithLabelValues(s.metaData.Key).Observe(float64(instance.InitDurationInMs))
+	s.runtimeStatus.ObserveInitDuration(time.Duration(instance.InitDurationInMs) * time.Millisecond)
+
+	//notify
+	s.enqueueReady(instance)
+	go atomic.CompareAndSwapInt64(&s.creatingDuration, 0, int64(time.Since(creatingTime)))
+	s.logger.Info("instance is created", "requestId", requestId, "instanceId", instance.Id, "metaKey", instance.Meta.Key, "duration", time.Duration(instance.InitDurationInMs)*time.Millisecond)
+	return nil
+}
+
+// InitErrorRate 返回 Init 调用的失败率
+func (s *Simple) InitErrorRate() float64 {
+	attempts := atomic.LoadUint64(&s.initAttempts)
+	if attempts == 0 {
+		return 0
+	}
+	return float64(atomic.LoadUint64(&s.initErrors)) / float64(attempts)
+}
+
+// 至少有 minInitSamples 次 Init 调用后才判断失败率，避免启动初期单次失败就告警
+const minInitSamples = 10
+
+func (s *Simple) checkInitErrorRate() {
+	threshold := s.cfg().InitErrorRateThreshold
+	if threshold <= 0 || atomic.LoadUint64(&s.initAttempts) < minInitSamples {
+		return
+	}
+	if rate := s.InitErrorRate(); rate > threshold {
+		s.logger.Warn("init error rate exceeds threshold", "metaKey", s.metaData.Key, "rate", rate, "threshold", threshold)
+	}
+}
+
+// Config 返回当前生效配置的副本
+func (s *Simple) Config() config.Config {
+	return *s.cfg()
+}
+
+// ReloadConfig 校验并替换当前配置，之后的调用和 gc 都读取新的配置，GcInterval 变化时 gc 立即按新的间隔重新计时
+// 只在创建时读取、修改后需要重新创建 scaler 才生效的字段：ClientAddr、ShardCount、SlotReusePoolSize、AssignLatencySampleSize、
+// StalenessDuration、RequestRateWindow、SlotCreateRPS、SlotCreateBurst、CircuitBreakerThreshold、CircuitBreakerRecovery、
+// WarmPoolSize 从 0 变为非 0、PreallocatedSlots、AuditLogPath、AuditLogMaxSizeMb，其余字段立即生效
+func (s *Simple) ReloadConfig(cfg *config.Config) error {
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+	// 与创建时一样应用当前 meta key 的配置覆盖
+	cfg = withMetaOverride(cfg, s.metaData.Key)
+	old := s.cfg()
+	if rate := cfg.RctRateFor(s.metaData.Key); rate != old.RctRateFor(s.metaData.Key) {
+		if err := s.runtimeStatus.SetRctRate(rate); err != nil {
+			return err
+		}
+	}
+	s.runtimeStatus.SetAdaptiveRctRate(cfg.AdaptiveRctRate)
+	s.config.Store(cfg)
+	s.logger.Info("config is reloaded", "metaKey", s.metaData.Key)
+	if cfg.GcInterval != old.GcInterval {
+		select {
+		case s.gcConfigCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func validateConfig(cfg *config.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+	if cfg.GcInterval <= 0 {
+		return fmt.Errorf("gc interval must be positive, got %s", cfg.GcInterval)
+	}
+	if cfg.IdleDurationBeforeGC < 0 {
+		return fmt.Errorf("idle duration before gc must not be negative, got %s", cfg.IdleDurationBeforeGC)
+	}
+	if cfg.MinIdleInstances < 0 || cfg.MaxIdleInstances < 0 || cfg.MaxInstances < 0 {
+		return fmt.Errorf("instance limits must not be negative")
+	}
+	if cfg.MaxIdleInstances > 0 && cfg.MinIdleInstances > cfg.MaxIdleInstances {
+		return fmt.Errorf("min idle instances %d exceeds max idle instances %d", cfg.MinIdleInstances, cfg.MaxIdleInstances)
+	}
+	return nil
+}
+
+func (s *Simple) cfg() *config.Config {
+	return s.config.Load().(*config.Config)
+}
+
+// InstanceMeta 返回 scaler 所属应用 meta 的副本，修改副本不影响 scaler
+func (s *Simple) InstanceMeta() *model2.Meta {
+	return model2.NewMeta(&s.metaData.Meta)
+}
+
+// EstimateTimeToServe 估算排在第 queuePos 位（从 1 开始）的请求被分配实例前需要等待的时间
+// 空闲实例能满足的位置返回 0；其余位置按每轮 creatingNum 个实例、每轮耗时 creatingDuration 计算
+// 没有正在创建的实例时每个请求都要等一次新的创建
+func (s *Simple) EstimateTimeToServe(queuePos int) time.Duration {
+	s.idleMu.Lock()
+	idle := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	if queuePos <= idle {
+		return 0
+	}
+	queuePos -= idle
+	createDuration := time.Duration(atomic.LoadInt64(&s.creatingDuration))
+	if createDuration == 0 {
+		// 还没有创建成功的实例，用平台调用的平均耗时估算
+		createDuration = s.runtimeStatus.GetMeanPlatformCallDuration()
+	}
+	creating := int(atomic.LoadInt64(&s.creatingNum))
+	perRound := creating
+	if perRound < 1 {
+		perRound = 1
+	}
+	rounds := (queuePos - creating) / perRound
+	if rounds < 1 {
+		rounds = 1
+	}
+	return createDuration * time.Duration(rounds)
+}
+
+// GetScalingMetrics 根据请求耗时和到达速率计算伸缩指标
+func (s *Simple) GetScalingMetrics() ScalingMetrics {
+	requestCostTime := s.runtimeStatus.GetRequestCostTime()
+	rps := s.runtimeStatus.getRequestRate()
+	desired := int64(math.Ceil(requestCostTime.Seconds() * rps * s.cfg().ScalingSafetyFactor))
+	stats := s.Stats()
+	s.longPollingMu.Lock()
+	queueDepth := int64(s.longPollingHeap.Len())
+	s.longPollingMu.Unlock()
+	return ScalingMetrics{
+		DesiredInstances: desired,
+		CurrentInstances: int64(stats.TotalInstance) + atomic.LoadInt64(&s.creatingNum),
+		ReadyInstances:   int64(stats.TotalIdleInstance),
+		QueueDepth:       queueDepth,
+	}
+}
+
+func (s *Simple) CheckLive() bool {
+	// 平台调用明显变慢时，返回false
+	if expected := s.cfg().ExpectedPlatformCallDuration; expected > 0 && s.runtimeStatus.GetMeanPlatformCallDuration() > 3*expected {
+		return false
+	}
+	// 有请求等待超过 LivenessTimeout，且期间没有成功的 Assign 或 Idle 时，返回false
+	// 没有流量时不会有等待的请求，不视为异常
+	if timeout := s.cfg().LivenessTimeout; timeout > 0 && time.Since(time.Unix(0, atomic.LoadInt64(&s.lastActivity))) > timeout {
+		s.longPollingMu.Lock()
+		oldest := s.longPollingHeap.oldestEnqueuedAt()
+		s.longPollingMu.Unlock()
+		if !oldest.IsZero() && time.Since(oldest) > timeout {
+			return false
+		}
+	}
+	return true
+}
+
+// Shutdown 停止后台循环和新的请求，让等待实例的请求返回 Unavailable，再等待正在进行的实例创建和销毁完成
+// ctx 结束前没有完成时返回 ctx 的错误；不关闭平台客户端，之后仍需调用 Close 释放资源
+func (s *Simple) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.shutdownMu.Lock()
+		close(s.done)
+		s.shutdownMu.Unlock()
+		liveScalers.Delete(s)
+	})
+	s.failAllWaiters(fmt.Errorf("scaler for app %s is shut down", s.metaData.Key))
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 调用 Shutdown 后取消剩余的平台调用并关闭平台客户端
+// ctx 没有 deadline 时使用 ShutdownTimeout 作为超时时间，超时后不再等待，直接关闭客户端并返回 ctx 的错误
+func (s *Simple) Close(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok && s.cfg().ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg().ShutdownTimeout)
+		defer cancel()
+	}
+	err := s.Shutdown(ctx)
+	if err != nil {
+		s.logger.WarnContext(ctx, "close scaler timeout", "metaKey", s.metaData.Key, "error", err)
+	}
+	if dir := s.cfg().CheckpointDir; dir != "" {
+		if checkpointErr := s.Checkpoint(dir); checkpointErr != nil {
+			s.logger.ErrorContext(ctx, "checkpoint failed", "metaKey", s.metaData.Key, "error", checkpointErr)
+		}
+	}
+	s.destroyReusableSlots(ctx)
+	s.destroyPreallocatedSlots(ctx)
+	if s.journal != nil {
+		if journalErr := s.journal.Close(); journalErr != nil {
+			s.logger.ErrorContext(ctx, "close journal failed", "metaKey", s.metaData.Key, "error", journalErr)
+		}
+	}
+	if s.auditLog != nil {
+		s.auditReleaseOnce.Do(func() {
+			if auditErr := s.auditLog.release(); auditErr != nil {
+				s.logger.ErrorContext(ctx, "close audit log failed", "metaKey", s.metaData.Key, "path", s.auditLog.path, "error", auditErr)
+			}
+		})
+	}
+	// 取消仍在进行的平台调用
+	s.cancel()
+	if closeErr := s.platformClient.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	s.logger.InfoContext(ctx, "scaler is closed", "metaKey", s.metaData.Key)
+	return err
+}
+
+// 销毁复用池中剩余的 slot
+func (s *Simple) destroyReusableSlots(ctx context.Context) {
+	for {
+		select {
+		case slot := <-s.slotReusePool:
+			if err := s.platformClient.DestroySLot(ctx, uuid.NewString(), slot.Id, "scaler closed"); err != nil {
+				s.logger.Error("delete reusable slot failed", "metaKey", s.metaData.Key, "slotId", slot.Id, "error", err)
+			}
+			s.releaseMemory(s.metaData.MemoryInMb)
+		default:
+			return
+		}
+	}
+}
+
+// Clear 按比例释放空闲实例，rate 会被限制在 [0, 1]，从空闲最久的实例开始回收 floor(空闲数 * rate) 个
+// 与 gc 使用相同的锁，同一实例不会被重复回收；slot 的删除在后台进行，Close 等待超时后取消
+func (s *Simple) Clear(rate float64) {
+	if rate <= 0 || math.IsNaN(rate) {
+		return
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	n := int(math.Floor(float64(s.Stats().TotalIdleInstance) * rate))
+	if n == 0 {
+		return
+	}
+	evicted := s.DrainIdle(func(instance *model2.Instance) bool {
+		n--
+		return n >= 0
+	})
+	s.logger.Info("clear idle instances", "metaKey", s.metaData.Key, "count", len(evicted))
+	for _, instance := range evicted {
+		s.goDestroy(instance, EvictReasonClear)
+	}
 }
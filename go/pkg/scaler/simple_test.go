@@ -0,0 +1,3712 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	platform_client2 "github.com/AliyunContainerService/scaler/go/pkg/platform_client"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAssignIdleReusesInstance(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+
+	reply, err := s.Assign(ctx, assignRequest("r1"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	// 用满的实例在后台放回空闲队列
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 1 })
+	second, err := s.Assign(ctx, assignRequest("r2"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if second.Assigment.InstanceId != reply.Assigment.InstanceId {
+		t.Fatalf("expected idle instance %s to be reused, got %s", reply.Assigment.InstanceId, second.Assigment.InstanceId)
+	}
+	if creates := atomic.LoadInt64(&platform.creates); creates != 1 {
+		t.Fatalf("expected 1 CreateSlot call, got %d", creates)
+	}
+}
+
+func BenchmarkAssignIdle(b *testing.B) {
+	s := newTestSimple(b, testConfig(), newFakePlatform())
+	ctx := context.Background()
+	var seq int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			reply, err := s.Assign(ctx, assignRequest(fmt.Sprintf("r%d", atomic.AddInt64(&seq, 1))))
+			if err != nil {
+				b.Errorf("assign: %v", err)
+				return
+			}
+			if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+				b.Errorf("idle: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// 只有一个实例，8 个 goroutine 争抢，暴露 Assign 和 Idle 路径上的锁竞争
+func BenchmarkAssignContended(b *testing.B) {
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	s := newTestSimple(b, cfg, newFakePlatform())
+	ctx := context.Background()
+	// 先创建唯一的实例
+	reply, err := s.Assign(ctx, assignRequest("warmup"))
+	if err != nil {
+		b.Fatalf("assign: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+		b.Fatalf("idle: %v", err)
+	}
+	const workers = 8
+	var seq int64
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.AddInt64(&seq, 1) <= int64(b.N) {
+				reply, err := s.Assign(ctx, assignRequest(fmt.Sprintf("r%d", atomic.LoadInt64(&seq))))
+				if err != nil {
+					b.Errorf("assign: %v", err)
+					return
+				}
+				if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+					b.Errorf("idle: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// 两个 meta key 共用一个 scaler，空闲实例中优先选择上次服务过相同 key 的实例
+func TestAssignPrefersInstanceOfSameMetaKey(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	ctx := context.Background()
+	requestA := assignRequest("a1")
+	requestA.MetaData.Key = "key-a"
+	requestB := assignRequest("b1")
+	requestB.MetaData.Key = "key-b"
+
+	// 两个请求同时占用，各自创建一个实例
+	replyA, err := s.Assign(ctx, requestA)
+	if err != nil {
+		t.Fatalf("assign a: %v", err)
+	}
+	replyB, err := s.Assign(ctx, requestB)
+	if err != nil {
+		t.Fatalf("assign b: %v", err)
+	}
+	if replyA.Assigment.InstanceId == replyB.Assigment.InstanceId {
+		t.Fatalf("expected two instances, both requests got %s", replyA.Assigment.InstanceId)
+	}
+	// b 的实例最后空闲，位于 LIFO 空闲队列的队首
+	for _, reply := range []*pb.AssignReply{replyA, replyB} {
+		if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+		waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance >= 1 })
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 2 })
+
+	next := assignRequest("a2")
+	next.MetaData.Key = "key-a"
+	reply, err := s.Assign(ctx, next)
+	if err != nil {
+		t.Fatalf("assign a2: %v", err)
+	}
+	if reply.Assigment.InstanceId != replyA.Assigment.InstanceId {
+		t.Fatalf("expected instance %s last used by key-a, got %s", replyA.Assigment.InstanceId, reply.Assigment.InstanceId)
+	}
+}
+
+// 忙碌实例的 GracefulEvict 推迟到 Idle 时回收
+func TestGracefulEvictWaitsForIdle(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+
+	reply, err := s.Assign(ctx, assignRequest("r1"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	instanceId := reply.Assigment.InstanceId
+	if err := s.GracefulEvict(instanceId, true); err != nil {
+		t.Fatalf("graceful evict: %v", err)
+	}
+	if counts := s.InstanceCountByStatus(); counts["expire_pending"] != 1 {
+		t.Fatalf("busy instance should be pending eviction, got %v", counts)
+	}
+	if n := atomic.LoadInt64(&platform.destroys); n != 0 {
+		t.Fatalf("busy instance destroyed before idle, destroys=%d", n)
+	}
+
+	if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	if total := s.Stats().TotalInstance; total != 0 {
+		t.Fatalf("evicted instance still tracked, total=%d", total)
+	}
+	if err := s.GracefulEvict(instanceId, true); status.Code(err) != codes.NotFound {
+		t.Fatalf("evict removed instance: got %v, want NotFound", err)
+	}
+}
+
+// 请求自带的 TimeoutMs 到期后立即返回，不等待调用方的 deadline
+func TestAssignRequestTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	if _, err := s.Assign(context.Background(), assignRequest("busy")); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	request := assignRequest("r1")
+	request.TimeoutMs = 50
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := s.Assign(ctx, request)
+	elapsed := time.Since(start)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("got %v, want DeadlineExceeded", err)
+	}
+	if elapsed < 50*time.Millisecond || elapsed > 250*time.Millisecond {
+		t.Fatalf("assign returned after %s, want about 50ms", elapsed)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("timed out request still queued, queue=%d", n)
+	}
+}
+
+// Clear 清空空闲实例后，BackfillIdle 把空闲实例补齐到 MinIdleInstances
+func TestBackfillIdleAfterClear(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinIdleInstances = 3
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	if err := s.BackfillIdle(ctx); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 3 })
+	s.Clear(1)
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 0 && platform.liveSlots() == 0 })
+
+	if err := s.BackfillIdle(ctx); err != nil {
+		t.Fatalf("backfill after clear: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 3 })
+	if n := atomic.LoadInt64(&platform.creates); n != 6 {
+		t.Fatalf("creates %d, want 6", n)
+	}
+	// 已经补齐时不再创建
+	if err := s.BackfillIdle(ctx); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 6 {
+		t.Fatalf("creates %d after a full backfill, want 6", n)
+	}
+}
+
+func TestBackfillIdleRespectsMaxInstances(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinIdleInstances = 3
+	cfg.MaxInstances = 2
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+
+	if err := s.BackfillIdle(context.Background()); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 2 })
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("creates %d, want 2", n)
+	}
+}
+
+// 并发的 Assign 同时检查创建额度时，创建的实例数也不会超过 MaxInstances
+func TestMaxInstancesConcurrentAssign(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 2
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("req-%d-%d", w, i)))
+				if err != nil {
+					t.Errorf("assign: %v", err)
+					return
+				}
+				if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+					t.Errorf("idle: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt64(&platform.creates); n > 2 {
+		t.Fatalf("creates %d, want at most 2", n)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingNum) == 0 })
+}
+
+func TestBackfillIdleReturnsCreateError(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinIdleInstances = 2
+	platform := newFakePlatform()
+	platform.setCreateErr(status.Error(codes.Internal, "platform down"))
+	s := newTestSimple(t, cfg, platform)
+
+	if err := s.BackfillIdle(context.Background()); err == nil {
+		t.Fatalf("backfill should return the create error")
+	}
+}
+
+func stickyRequest(requestId, stickyKey string) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.StickyKey = stickyKey
+	return request
+}
+
+// 重试的请求带相同 sticky key 时回到上次分配的空闲实例
+func TestAssignPrefersStickyInstance(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	replies := assignAll(t, s, stickyRequest("r1", "session"), assignRequest("r2"))
+	sticky, other := replies[0].Assigment.InstanceId, replies[1].Assigment.InstanceId
+	idleInOrder(t, s, replies...)
+
+	reply := assignAll(t, s, stickyRequest("retry", "session"))[0]
+	if reply.Assigment.InstanceId != sticky {
+		t.Fatalf("got instance %s, want sticky instance %s", reply.Assigment.InstanceId, sticky)
+	}
+	// 上次的实例忙碌时分配其他实例，并记录新的实例
+	moved := assignAll(t, s, stickyRequest("retry2", "session"))[0]
+	if moved.Assigment.InstanceId != other {
+		t.Fatalf("got instance %s, want idle instance %s", moved.Assigment.InstanceId, other)
+	}
+	if got := s.stickyInstance("session"); got != other {
+		t.Fatalf("sticky key maps to %s, want %s", got, other)
+	}
+}
+
+func TestStickyKeyExpires(t *testing.T) {
+	cfg := testConfig()
+	cfg.StickyKeyTTL = 20 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	replies := assignAll(t, s, stickyRequest("r1", "session"), assignRequest("r2"))
+	idleInOrder(t, s, replies...)
+	time.Sleep(40 * time.Millisecond)
+
+	reply := assignAll(t, s, stickyRequest("retry", "session"))[0]
+	if reply.Assigment.InstanceId != replies[1].Assigment.InstanceId {
+		t.Fatalf("expired sticky key still routes to %s", reply.Assigment.InstanceId)
+	}
+	s.expireStickyKeys()
+	s.stickyMu.Lock()
+	n := len(s.stickyMap)
+	s.stickyMu.Unlock()
+	if n != 1 {
+		t.Fatalf("sticky map has %d entries, want only the new assignment", n)
+	}
+}
+
+// 等待的请求迟迟不取实例时，超过 IdleNotifyTimeout 关闭请求的 channel，实例放回空闲队列
+func TestIdleNotifyTimeoutReturnsInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.IdleNotifyTimeout = 20 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+
+	// 没有人读取的 channel，模拟卡住的等待请求
+	s.longPollingMu.Lock()
+	stuck := s.longPollingHeap.push(make(chan *model2.Instance), time.Time{})
+	s.longPollingMu.Unlock()
+
+	start := time.Now()
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 1 })
+	if elapsed := time.Since(start); elapsed < cfg.IdleNotifyTimeout {
+		t.Fatalf("instance returned after %s, before the notify timeout", elapsed)
+	}
+	select {
+	case instance, ok := <-stuck.ch:
+		if ok || instance != nil {
+			t.Fatalf("stuck waiter should see a closed channel")
+		}
+	default:
+		t.Fatalf("stuck waiter channel is not closed")
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("stuck waiter still queued, queue=%d", n)
+	}
+	if reply := assignAll(t, s, assignRequest("r2"))[0]; reply.Assigment.InstanceId == "" {
+		t.Fatalf("returned instance should be assignable")
+	}
+}
+
+// 平台支持批量创建时，Warmup 通过一次 CreateSlotGroup 创建全部 slot
+func TestWarmupCreatesSlotGroup(t *testing.T) {
+	platform := newFakeGroupPlatform()
+	s := newTestSimple(t, testConfig(), platform)
+
+	if err := s.Warmup(context.Background(), 5); err != nil {
+		t.Fatalf("warmup: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 5 })
+	if n := atomic.LoadInt64(&platform.groupCalls); n != 1 {
+		t.Fatalf("CreateSlotGroup called %d times, want 1", n)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 0 {
+		t.Fatalf("CreateSlot called %d times, want 0", n)
+	}
+	// 只创建一个实例时不走批量创建
+	if err := s.Warmup(context.Background(), 1); err != nil {
+		t.Fatalf("warmup: %v", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("CreateSlot called %d times, want 1", n)
+	}
+}
+
+// 平台返回 Unimplemented 时退回逐个创建，之后不再尝试批量创建
+func TestWarmupFallsBackWithoutSlotGroup(t *testing.T) {
+	platform := newFakeGroupPlatform()
+	platform.groupErr = status.Error(codes.Unimplemented, "unknown method CreateSlotGroup")
+	s := newTestSimple(t, testConfig(), platform)
+
+	if err := s.Warmup(context.Background(), 3); err != nil {
+		t.Fatalf("warmup: %v", err)
+	}
+	if err := s.Warmup(context.Background(), 2); err != nil {
+		t.Fatalf("warmup: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 5 })
+	if n := atomic.LoadInt64(&platform.groupCalls); n != 1 {
+		t.Fatalf("CreateSlotGroup called %d times, want 1", n)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 5 {
+		t.Fatalf("CreateSlot called %d times, want 5", n)
+	}
+	if state := s.Stats().CircuitState; state != "closed" {
+		t.Fatalf("Unimplemented should not count as a platform failure, circuit %s", state)
+	}
+}
+
+// 批量创建与逐个创建一样经过熔断
+func TestSlotGroupRespectsCircuitBreaker(t *testing.T) {
+	cfg := testConfig()
+	cfg.CircuitBreakerThreshold = 1
+	cfg.CircuitBreakerRecovery = time.Hour
+	platform := newFakeGroupPlatform()
+	platform.groupErr = status.Error(codes.Internal, "platform down")
+	s := newTestSimple(t, cfg, platform)
+
+	if err := s.Warmup(context.Background(), 3); err == nil {
+		t.Fatalf("warmup should fail while platform is down")
+	}
+	if err := s.Warmup(context.Background(), 3); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+	if n := atomic.LoadInt64(&platform.groupCalls); n != 1 {
+		t.Fatalf("CreateSlotGroup called %d times, want 1", n)
+	}
+	if n := s.Stats().AllocatedMemoryMb; n != 0 {
+		t.Fatalf("failed group keeps %dMB reserved", n)
+	}
+}
+
+// 批量创建受 SlotCreateTimeout 限制，不依赖调用方的 ctx
+func TestSlotGroupTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.SlotCreateTimeout = 20 * time.Millisecond
+	platform := newFakeGroupPlatform()
+	platform.createDelay = time.Second
+	s := newTestSimple(t, cfg, platform)
+
+	start := time.Now()
+	err := s.Warmup(context.Background(), 2)
+	if createErrorCode(err) != codes.DeadlineExceeded {
+		t.Fatalf("got %v, want a create timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("warmup returned after %s, want about SlotCreateTimeout", elapsed)
+	}
+	if slotCreate, _ := s.CreateTimeouts(); slotCreate != 1 {
+		t.Fatalf("slot create timeouts %d, want 1", slotCreate)
+	}
+}
+
+// 预热池补齐时一次创建全部缺少的实例
+func TestWarmPoolRefillUsesSlotGroup(t *testing.T) {
+	cfg := testConfig()
+	cfg.WarmPoolSize = 3
+	platform := newFakeGroupPlatform()
+	s := newTestSimple(t, cfg, platform)
+
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 3 })
+	if n := atomic.LoadInt64(&platform.groupCalls); n != 1 {
+		t.Fatalf("CreateSlotGroup called %d times, want 1", n)
+	}
+}
+
+// 创建后立即预热 WarmPoolSize 个实例，第一个请求直接使用预热的实例，分配后补齐
+func TestWarmPoolServesFirstAssign(t *testing.T) {
+	cfg := testConfig()
+	cfg.WarmPoolSize = 3
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance >= 3 })
+	warm := make(map[string]bool)
+	for _, id := range idleIds(s) {
+		warm[id] = true
+	}
+
+	// 取最快的一次，避免调度抖动
+	fastest := time.Hour
+	var reply *pb.AssignReply
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		r, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("first-%d", i)))
+		if elapsed := time.Since(start); elapsed < fastest {
+			fastest = elapsed
+		}
+		if err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		if i == 0 {
+			reply = r
+		}
+		if _, err := s.Idle(context.Background(), idleRequest(r, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	if !warm[reply.Assigment.InstanceId] {
+		t.Fatalf("first assign got %s, want a warm instance", reply.Assigment.InstanceId)
+	}
+	if fastest > time.Millisecond {
+		t.Fatalf("assign from the warm pool took %s", fastest)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n > 4 {
+		t.Fatalf("creates %d, want the warm pool plus at most one refill", n)
+	}
+}
+
+// 空闲实例被分配后补齐预热池，gc 不会把空闲实例回收到 WarmPoolSize 以下
+func TestWarmPoolRefill(t *testing.T) {
+	cfg := testConfig()
+	cfg.WarmPoolSize = 2
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 2 })
+
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"))
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 2 })
+	if stats := s.Stats(); stats.TotalInstance != 4 {
+		t.Fatalf("unexpected stats after refill %+v", stats)
+	}
+	for _, reply := range replies {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	// 超过预热池的空闲实例过期后被回收
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalInstance == 2 })
+	time.Sleep(50 * time.Millisecond)
+	if stats := s.Stats(); stats.TotalIdleInstance != 2 {
+		t.Fatalf("gc went below the warm pool size %+v", stats)
+	}
+}
+
+func TestWarmPoolDisabled(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt64(&platform.creates); n != 0 || s.Stats().TotalInstance != 0 {
+		t.Fatalf("scaler without a warm pool created %d instances", n)
+	}
+	if s.warmPoolCh != nil {
+		t.Fatalf("warm pool loop should not run")
+	}
+}
+
+func TestValidateIdleBounds(t *testing.T) {
+	cases := []struct {
+		min, max int
+		valid    bool
+	}{
+		{0, 0, true},
+		{2, 0, true},
+		{0, 3, true},
+		{3, 3, true},
+		{4, 3, false},
+		{-1, 0, false},
+		{0, -1, false},
+	}
+	for _, c := range cases {
+		cfg := testConfig()
+		cfg.MinIdleInstances = c.min
+		cfg.MaxIdleInstances = c.max
+		if err := validateConfig(cfg); (err == nil) != c.valid {
+			t.Errorf("min %d max %d: got %v, want valid %v", c.min, c.max, err, c.valid)
+		}
+	}
+}
+
+// gc 在空闲实例数降到 MinIdleInstances 时停止回收，即使实例已经超过空闲时间
+func TestGCKeepsMinIdleInstances(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinIdleInstances = 2
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = time.Millisecond
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"), assignRequest("c"), assignRequest("d"))
+	for _, reply := range replies {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 2 })
+	time.Sleep(50 * time.Millisecond)
+	if stats := s.Stats(); stats.TotalIdleInstance != 2 || platform.liveSlots() != 2 {
+		t.Fatalf("gc went below MinIdleInstances %+v", stats)
+	}
+}
+
+// 空闲队列达到 MaxIdleInstances 后，归还的实例直接回收
+func TestMaxIdleInstancesDestroysOverflow(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxIdleInstances = 2
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"), assignRequest("c"))
+	idleInOrder(t, s, replies[0], replies[1])
+	if _, err := s.Idle(context.Background(), idleRequest(replies[2], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 2 })
+	if stats := s.Stats(); stats.TotalIdleInstance != 2 || stats.TotalInstance != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	for _, id := range idleIds(s) {
+		if id == replies[2].Assigment.InstanceId {
+			t.Fatalf("overflow instance %s stayed idle", id)
+		}
+	}
+}
+
+// LIFO 先分配最近归还的实例，FIFO 先分配空闲最久的实例，默认为 LIFO
+func TestIdleQueueOrder(t *testing.T) {
+	cases := []struct {
+		order config.IdleQueueOrder
+		want  []int
+	}{
+		{"", []int{2, 1, 0}},
+		{config.IdleQueueLIFO, []int{2, 1, 0}},
+		{config.IdleQueueFIFO, []int{0, 1, 2}},
+	}
+	for _, c := range cases {
+		cfg := testConfig()
+		cfg.IdleQueueOrder = c.order
+		s := newTestSimple(t, cfg, newFakePlatform())
+		replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"))
+		idleInOrder(t, s, replies...)
+		for i, want := range c.want {
+			reply := assignAll(t, s, assignRequest(fmt.Sprintf("next-%d", i)))[0]
+			if reply.Assigment.InstanceId != replies[want].Assigment.InstanceId {
+				t.Fatalf("order %q: assign %d got %s, want instance of r%d", c.order, i, reply.Assigment.InstanceId, want)
+			}
+		}
+	}
+}
+
+// 已分配未归还的请求达到 MaxConcurrentRequests 时 Assign 立即返回 ResourceExhausted，Idle 之后恢复
+func TestMaxConcurrentRequests(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConcurrentRequests = 2
+	s := newTestSimple(t, cfg, newFakePlatform())
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"))
+	if _, err := s.Assign(context.Background(), assignRequest("c")); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(replies[0], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if _, err := s.Assign(context.Background(), assignRequest("d")); err != nil {
+		t.Fatalf("assign after idle: %v", err)
+	}
+}
+
+// 找不到实例的 Idle 不释放并发名额，强制回收忙碌的实例时释放
+func TestMaxConcurrentRequestsIdleNotFound(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConcurrentRequests = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+	reply := assignAll(t, s, assignRequest("a"))[0]
+	missing := &pb.IdleRequest{Assigment: &pb.Assignment{RequestId: "other", MetaKey: "app", InstanceId: "missing"}}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Idle(context.Background(), missing); status.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want NotFound", err)
+		}
+	}
+	if _, err := s.Assign(context.Background(), assignRequest("b")); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("unknown idle released the slot of a running request, got %v", err)
+	}
+
+	if err := s.ForceEvict(reply.Assigment.InstanceId); err != nil {
+		t.Fatalf("force evict: %v", err)
+	}
+	second := assignAll(t, s, assignRequest("c"))[0]
+	// 被回收实例上的请求结束时不会释放 c 的名额
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v, want NotFound", err)
+	}
+	if _, err := s.Assign(context.Background(), assignRequest("d")); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("idle of an evicted instance released another request's slot, got %v", err)
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(second, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if n := atomic.LoadInt64(&s.inFlight); n != 0 {
+		t.Fatalf("in flight %d after all requests returned", n)
+	}
+}
+
+// 记录 EvictionCallback 收到的回收原因
+type evictionRecorder struct {
+	mu      sync.Mutex
+	reasons map[string]int
+}
+
+func (r *evictionRecorder) callback(instance *model2.Instance, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reasons == nil {
+		r.reasons = make(map[string]int)
+	}
+	r.reasons[reason]++
+}
+
+func (r *evictionRecorder) count(reason string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reasons[reason]
+}
+
+// 各个回收入口都调用 EvictionCallback，并带上各自的原因
+func TestEvictionCallbackReasons(t *testing.T) {
+	recorder := &evictionRecorder{}
+	cfg := testConfig()
+	cfg.EvictionCallback = recorder.callback
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	replies := assignAll(t, s, assignRequest("force"), assignRequest("graceful"), assignRequest("busy"), assignRequest("bad"), assignRequest("clear"))
+	idleInOrder(t, s, replies[0], replies[1], replies[4])
+	if err := s.ForceEvict(replies[0].Assigment.InstanceId); err != nil {
+		t.Fatalf("force evict: %v", err)
+	}
+	if err := s.GracefulEvict(replies[1].Assigment.InstanceId, true); err != nil {
+		t.Fatalf("graceful evict: %v", err)
+	}
+	// 忙碌实例在 Idle 时回收
+	if err := s.GracefulEvict(replies[2].Assigment.InstanceId, true); err != nil {
+		t.Fatalf("graceful evict: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleRequest(replies[2], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleRequest(replies[3], true)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	s.Clear(1)
+
+	want := map[string]int{
+		EvictReasonForce:       1,
+		EvictReasonGraceful:    2,
+		EvictReasonBadInstance: 1,
+		EvictReasonClear:       1,
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	waitFor(t, time.Second, func() bool {
+		for reason, n := range want {
+			if recorder.count(reason) != n {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestEvictionCallbackOnGC(t *testing.T) {
+	recorder := &evictionRecorder{}
+	cfg := testConfig()
+	cfg.EvictionCallback = recorder.callback
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = 10 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	// gc 可能在检查空闲数量之前就回收了实例，不等待实例进入空闲队列
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return recorder.count(EvictReasonGC) == 1 })
+}
+
+// 没有设置 EvictionCallback 时回收实例不会出错
+func TestEvictWithoutCallback(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	if err := s.ForceEvict(reply.Assigment.InstanceId); err != nil {
+		t.Fatalf("force evict: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+}
+
+// 超过内存预算的请求返回 ResourceExhausted，而不是普通的创建失败
+func TestMemoryBudgetMixedRequests(t *testing.T) {
+	cfg := testConfig()
+	cfg.ResourceBudgetMb = 512
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	large := assignRequest("large")
+	large.MetaData.MemoryInMb = 384
+	assignAll(t, s, large, assignRequest("small"))
+
+	_, err := s.Assign(ctx, assignRequest("over"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	huge := assignRequest("huge")
+	huge.MetaData.MemoryInMb = 1024
+	if _, err := s.Assign(ctx, huge); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("rejected requests reached the platform: %d creates", n)
+	}
+}
+
+func idleWithStats(reply *pb.AssignReply, cpuMs, memPeakMb int64) *pb.IdleRequest {
+	request := idleRequest(reply, false)
+	request.Result.ExecutionStats = &pb.ExecutionStats{CpuMs: cpuMs, MemPeakMb: memPeakMb, NetworkBytesIn: 10, NetworkBytesOut: 20}
+	return request
+}
+
+// Idle 带上的执行统计记录到实例上，并累计到 RuntimeStatus
+func TestIdleAggregatesExecutionStats(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	ctx := context.Background()
+	replies := assignAll(t, s, assignRequest("r1"), assignRequest("r2"))
+	if _, err := s.Idle(ctx, idleWithStats(replies[0], 100, 64)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleWithStats(replies[1], 300, 96)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.runtimeStatus.MetricsSnapshot().ExecutionCount == 2 })
+
+	snapshot := s.runtimeStatus.MetricsSnapshot()
+	if snapshot.TotalCpuMs != 400 || snapshot.TotalMemPeakMb != 160 || snapshot.MaxMemPeakMb != 96 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.TotalNetworkBytesIn != 20 || snapshot.TotalNetworkBytesOut != 40 {
+		t.Fatalf("unexpected network totals %+v", snapshot)
+	}
+	s.instancesMu.RLock()
+	stats := s.instances[replies[1].Assigment.InstanceId].LastExecutionStats
+	s.instancesMu.RUnlock()
+	if stats == nil || stats.CpuMs != 300 {
+		t.Fatalf("unexpected last execution stats %+v", stats)
+	}
+}
+
+// 空闲时间相同时，上次消耗 CPU 少的实例先回收
+func TestEvictionScorePrefersLowCpu(t *testing.T) {
+	idleTime := time.Now().Add(-time.Minute)
+	hot := &model2.Instance{Id: "hot", LastIdleTime: idleTime, LastExecutionStats: &model2.ExecutionStats{CpuMs: 5000}}
+	cold := &model2.Instance{Id: "cold", LastIdleTime: idleTime, LastExecutionStats: &model2.ExecutionStats{CpuMs: 10}}
+	unknown := &model2.Instance{Id: "unknown", LastIdleTime: idleTime}
+	instances := []*model2.Instance{hot, cold, unknown}
+	sortByEvictionScore(instances)
+	if instances[0] != unknown || instances[1] != cold || instances[2] != hot {
+		t.Fatalf("unexpected order %s %s %s", instances[0].Id, instances[1].Id, instances[2].Id)
+	}
+}
+
+// 按顺序分配，每次等新实例进入空闲队列后再发下一个请求，使多槽位实例的剩余槽位可以被复用
+func assignShared(tb testing.TB, s *Simple, requests ...*pb.AssignRequest) []*pb.AssignReply {
+	tb.Helper()
+	var replies []*pb.AssignReply
+	for _, request := range requests {
+		replies = append(replies, assignAll(tb, s, request)...)
+		waitFor(tb, time.Second, func() bool {
+			s.readyMu.Lock()
+			defer s.readyMu.Unlock()
+			return !s.notifying
+		})
+	}
+	return replies
+}
+
+func instanceIds(replies []*pb.AssignReply) map[string]int {
+	ids := make(map[string]int)
+	for _, reply := range replies {
+		ids[reply.Assigment.InstanceId]++
+	}
+	return ids
+}
+
+// 单槽位实例忙碌时不会再分配给其他请求
+func TestSingleCapacityInstance(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	replies := assignShared(t, s, assignRequest("r1"), assignRequest("r2"))
+	if ids := instanceIds(replies); len(ids) != 2 {
+		t.Fatalf("busy instance was shared: %v", ids)
+	}
+}
+
+// 容量为 3 的实例同时处理 3 个请求，第 4 个请求创建新实例，释放槽位后可以再次分配
+func TestMultiCapacityInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceCapacity = 3
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	replies := assignShared(t, s, assignRequest("r1"), assignRequest("r2"), assignRequest("r3"), assignRequest("r4"))
+	ids := instanceIds(replies)
+	if len(ids) != 2 || ids[replies[0].Assigment.InstanceId] != 3 {
+		t.Fatalf("unexpected assignment %v", ids)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("got %d creates, want 2", n)
+	}
+
+	// 释放已满实例的一个槽位后重新进入空闲队列
+	if _, err := s.Idle(ctx, idleRequest(replies[0], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	first := replies[0].Assigment.InstanceId
+	waitFor(t, time.Second, func() bool {
+		s.idleMu.Lock()
+		defer s.idleMu.Unlock()
+		for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+			if element.Value.(*model2.Instance).Id == first {
+				return true
+			}
+		}
+		return false
+	})
+	// 两个实例共有 3 个空闲槽位，不需要创建新实例
+	assignAll(t, s, assignRequest("r5"), assignRequest("r6"))
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("got %d creates, want 2", n)
+	}
+}
+
+// 队尾仍有请求在处理的多槽位实例不阻塞 gc 回收其他过期实例，空闲队列保持空闲时间顺序
+func TestGCSkipsBusyMultiCapacityInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceCapacity = 2
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = 200 * time.Millisecond
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	// r1、r2 占满实例 a，r3 创建实例 b
+	replies := assignShared(t, s, assignRequest("r1"), assignRequest("r2"), assignRequest("r3"))
+	a, b := replies[0].Assigment.InstanceId, replies[2].Assigment.InstanceId
+	if replies[1].Assigment.InstanceId != a || b == a {
+		t.Fatalf("unexpected assignment %v", instanceIds(replies))
+	}
+	if _, err := s.Idle(ctx, idleRequest(replies[2], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	// a 释放一个槽位后重新进入空闲队列，仍有请求在处理，空闲时间比 b 早
+	if _, err := s.Idle(ctx, idleRequest(replies[0], false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 2 })
+	s.idleMu.Lock()
+	var previous time.Time
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		idleTime := element.Value.(*model2.Instance).LastIdleTime
+		if !previous.IsZero() && idleTime.After(previous) {
+			s.idleMu.Unlock()
+			t.Fatalf("idle list is not ordered by idle time")
+		}
+		previous = idleTime
+	}
+	tail := s.idleInstance.Back().Value.(*model2.Instance).Id
+	s.idleMu.Unlock()
+	if tail != a {
+		t.Fatalf("busy instance should be at the tail, got %s", tail)
+	}
+
+	waitFor(t, 2*time.Second, func() bool { return platform.liveSlots() == 1 })
+	s.instancesMu.RLock()
+	_, kept := s.instances[a]
+	s.instancesMu.RUnlock()
+	if !kept {
+		t.Fatalf("busy instance %s was collected", a)
+	}
+}
+
+// 平台在 StartupTimeout 内不可访问时返回 Unavailable，平台恢复后下一个请求重新探测并成功
+func TestStartupProbe(t *testing.T) {
+	cfg := testConfig()
+	cfg.StartupTimeout = 50 * time.Millisecond
+	platform := newFakePlatform()
+	platform.setPingErr(status.Error(codes.Unavailable, "connection refused"))
+	s := newTestSimple(t, cfg, platform)
+	ctx := context.Background()
+
+	if _, err := s.Assign(ctx, assignRequest("r1")); status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 0 {
+		t.Fatalf("request reached the platform before it was ready: %d creates", n)
+	}
+
+	platform.setPingErr(nil)
+	assignAll(t, s, assignRequest("r2"))
+	// 成功的探测结果会被记住，之后的请求不再 Ping
+	pings := atomic.LoadInt64(&platform.pings)
+	assignAll(t, s, assignRequest("r3"))
+	if n := atomic.LoadInt64(&platform.pings); n != pings {
+		t.Fatalf("probe ran again after success: %d pings, want %d", n, pings)
+	}
+}
+
+// 请求自己的 ctx 先结束时返回 ctx 的错误
+func TestStartupProbeRequestCanceled(t *testing.T) {
+	cfg := testConfig()
+	cfg.StartupTimeout = time.Second
+	platform := newFakePlatform()
+	platform.setPingErr(errors.New("not ready"))
+	s := newTestSimple(t, cfg, platform)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Assign(ctx, assignRequest("r1")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context deadline", err)
+	}
+}
+
+// DesiredInstances = ceil(requestCostTime * rps * safetyFactor)，ReadyInstances 只统计空闲实例
+func TestGetScalingMetrics(t *testing.T) {
+	cfg := testConfig()
+	cfg.ScalingSafetyFactor = 1.2
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	replies := assignAll(t, s, assignRequest("r1"), assignRequest("r2"))
+	idleInOrder(t, s, replies[0])
+	metrics := s.GetScalingMetrics()
+	if metrics.CurrentInstances != 2 || metrics.ReadyInstances != 1 || metrics.QueueDepth != 0 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+
+	// 请求耗时 1s，窗口内有 6 个请求，rps 为 6，期望 ceil(1 * 6 * 1.2) = 8 个实例
+	s = newTestSimple(t, cfg, newFakePlatform())
+	s.runtimeStatus.requestDurationMu.Lock()
+	s.runtimeStatus.requestCostTime = time.Second
+	s.runtimeStatus.requestDurationMu.Unlock()
+	now := time.Now()
+	for i := 0; i < 6; i++ {
+		s.runtimeStatus.AssignStart(fmt.Sprintf("r%d", i), now)
+	}
+	if got := s.GetScalingMetrics().DesiredInstances; got != 8 {
+		t.Fatalf("got %d desired instances, want 8", got)
+	}
+}
+
+// LongPollingChanSize 为 2 时，两个并发请求都能通过长轮询收到新创建的实例
+func TestLongPollingChanSize(t *testing.T) {
+	cfg := testConfig()
+	cfg.LongPollingChanSize = 2
+	platform := newFakePlatform()
+	platform.createDelay = 20 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+
+	var wg sync.WaitGroup
+	replies := make([]*pb.AssignReply, 2)
+	errs := make([]error, 2)
+	for i := range replies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			replies[i], errs[i] = s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i)))
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("assign r%d: %v", i, err)
+		}
+	}
+	if replies[0].Assigment.InstanceId == replies[1].Assigment.InstanceId {
+		t.Fatalf("both requests got instance %s", replies[0].Assigment.InstanceId)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("got %d creates, want 2", n)
+	}
+}
+
+// 请求取消后才创建好的实例放回空闲队列，不会丢失
+func TestLongPollingChanSizeCanceledRequest(t *testing.T) {
+	cfg := testConfig()
+	cfg.LongPollingChanSize = 2
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Assign(ctx, assignRequest("r1")); err == nil {
+		t.Fatalf("canceled request should fail")
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 1 })
+	assignAll(t, s, assignRequest("r2"))
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("got %d creates, want 1", n)
+	}
+}
+
+// Idle 带上的 slot id 与实例记录的不一致时返回 InvalidArgument，实例保持忙碌
+func TestIdleSlotIdMismatch(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+
+	tampered := idleRequest(reply, true)
+	tampered.Assigment = &pb.Assignment{
+		RequestId:  reply.Assigment.RequestId,
+		MetaKey:    reply.Assigment.MetaKey,
+		InstanceId: reply.Assigment.InstanceId,
+		SlotId:     "other-slot",
+	}
+	if _, err := s.Idle(ctx, tampered); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("got %v, want InvalidArgument", err)
+	}
+	if platform.liveSlots() != 1 || s.Stats().TotalIdleInstance != 0 {
+		t.Fatalf("mismatched idle changed the instance: %+v", s.Stats())
+	}
+
+	// 正确的 slot id 和空 slot id 都可以释放实例
+	idleInOrder(t, s, reply)
+	reply = assignAll(t, s, assignRequest("r2"))[0]
+	reply.Assigment.SlotId = ""
+	idleInOrder(t, s, reply)
+}
+
+func preferenceRequest(requestId string, preference pb.PoolPreference) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.PoolPreference = preference
+	return request
+}
+
+// Warm 请求没有空闲实例时直接返回 Unavailable，不创建实例
+func TestWarmPreferenceRejectsWhenEmpty(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+
+	if _, err := s.Assign(ctx, preferenceRequest("r1", pb.PoolPreference_Warm)); status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 0 {
+		t.Fatalf("warm request created %d instances", n)
+	}
+
+	idle := assignAll(t, s, assignRequest("r2"))[0]
+	idleInOrder(t, s, idle)
+	if reply := assignAll(t, s, preferenceRequest("r3", pb.PoolPreference_Warm))[0]; reply.Assigment.InstanceId != idle.Assigment.InstanceId {
+		t.Fatalf("got %s, want idle instance %s", reply.Assigment.InstanceId, idle.Assigment.InstanceId)
+	}
+}
+
+// Cold 请求总是创建新实例，空闲实例留给其他请求
+func TestColdPreferenceAlwaysCreates(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+
+	idle := assignAll(t, s, assignRequest("r1"))[0]
+	idleInOrder(t, s, idle)
+	cold := assignAll(t, s, preferenceRequest("r2", pb.PoolPreference_Cold))[0]
+	if cold.Assigment.InstanceId == idle.Assigment.InstanceId {
+		t.Fatalf("cold request took the idle instance")
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("got %d creates, want 2", n)
+	}
+	if s.Stats().TotalIdleInstance != 1 {
+		t.Fatalf("idle instance should stay in the pool: %+v", s.Stats())
+	}
+	// Any 请求仍然使用空闲实例
+	if reply := assignAll(t, s, assignRequest("r3"))[0]; reply.Assigment.InstanceId != idle.Assigment.InstanceId {
+		t.Fatalf("got %s, want idle instance %s", reply.Assigment.InstanceId, idle.Assigment.InstanceId)
+	}
+}
+
+// 每种状态各有一个实例时，InstanceCountByStatus 分别计数
+func TestInstanceCountByStatus(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+
+	replies := assignAll(t, s, assignRequest("idle"), assignRequest("busy"), assignRequest("pending"))
+	idleInOrder(t, s, replies[0])
+	if err := s.GracefulEvict(replies[2].Assigment.InstanceId, true); err != nil {
+		t.Fatalf("graceful evict: %v", err)
+	}
+	// 之前的创建都已返回，之后的创建阻塞到测试结束
+	platform.createDelay = time.Minute
+	go s.Warmup(context.Background(), 1)
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingNum) == 1 })
+
+	want := map[string]int{"idle": 1, "busy": 1, "creating": 1, "quarantined": 0, "expire_pending": 1, "warmup": 0}
+	got := s.InstanceCountByStatus()
+	for key, n := range want {
+		if got[key] != n {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+// 空闲队列中已经不在 instances 里的实例被 compactIdleList 删除，不会再分配出去
+func TestCompactIdleListRemovesGhosts(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	replies := assignAll(t, s, assignRequest("ghost"), assignRequest("live"))
+	idleInOrder(t, s, replies...)
+	ghost := replies[0].Assigment.InstanceId
+
+	// 模拟 slot 删除失败后实例只从 instances 中删除的情况
+	s.instancesMu.Lock()
+	delete(s.instances, ghost)
+	s.instancesMu.Unlock()
+	s.compactIdleList()
+	if n := s.Stats().TotalIdleInstance; n != 1 {
+		t.Fatalf("got %d idle instances, want 1", n)
+	}
+	for i := 0; i < 2; i++ {
+		reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i)))
+		if err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		if reply.Assigment.InstanceId == ghost {
+			t.Fatalf("ghost instance %s was assigned", ghost)
+		}
+	}
+}
+
+// 依次进入等待的请求记录各自进入时的排队位置，空闲实例命中时为 0
+func TestQueuePositionAtAssign(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+
+	const n = 3
+	replies := make([]*pb.AssignReply, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i)))
+			if err != nil {
+				t.Errorf("assign r%d: %v", i, err)
+				return
+			}
+			replies[i] = reply
+		}(i)
+		waitFor(t, time.Second, func() bool { return queueLen(s) == i+1 })
+	}
+	wg.Wait()
+	for i, reply := range replies {
+		if reply == nil {
+			t.FailNow()
+		}
+		if reply.QueuePositionAtAssign != int32(i) {
+			t.Fatalf("r%d got queue position %d, want %d", i, reply.QueuePositionAtAssign, i)
+		}
+	}
+
+	idleInOrder(t, s, replies[0])
+	if reply := assignAll(t, s, assignRequest("hit"))[0]; reply.QueuePositionAtAssign != 0 {
+		t.Fatalf("idle pool hit got queue position %d", reply.QueuePositionAtAssign)
+	}
+}
+
+// Shutdown 之后不再启动后台任务
+func TestGoBackgroundAfterShutdown(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	ran := make(chan struct{})
+	if !s.goBackground(func() { close(ran) }) {
+		t.Fatalf("goBackground refused before shutdown")
+	}
+	<-ran
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if s.goBackground(func() { t.Errorf("background task ran after shutdown") }) {
+		t.Fatalf("goBackground accepted a task after shutdown")
+	}
+	// 已经关闭时请求返回 Unavailable，预留的创建额度被释放
+	if _, err := s.Assign(context.Background(), assignRequest("late")); status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want Unavailable", err)
+	}
+	s.Clear(1)
+	if n := atomic.LoadInt64(&s.creatingNum); n != 0 {
+		t.Fatalf("creatingNum %d after shutdown", n)
+	}
+}
+
+// 创建、销毁和 Clear 与 Shutdown 并发时，Shutdown 返回后不会再有新的后台任务
+func TestShutdownConcurrentWithBackgroundWork(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		cfg := testConfig()
+		cfg.HotStandby = true
+		platform := newFakePlatform()
+		s := newTestSimple(t, cfg, platform)
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("r-%d-%d", w, i)))
+					if err != nil {
+						continue
+					}
+					s.Idle(context.Background(), idleRequest(reply, i%2 == 0))
+					s.Clear(0.5)
+				}
+			}(w)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if err := s.Shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+		close(stop)
+		wg.Wait()
+		if n := atomic.LoadInt64(&s.creatingNum); n != 0 {
+			t.Fatalf("round %d: creatingNum %d after shutdown", round, n)
+		}
+		// Shutdown 返回后没有后台任务在运行，wg 可以立即等待完成
+		finished := make(chan struct{})
+		go func() {
+			s.wg.Wait()
+			close(finished)
+		}()
+		select {
+		case <-finished:
+		case <-time.After(time.Second):
+			t.Fatalf("round %d: background work started after shutdown", round)
+		}
+	}
+}
+
+// 没有 deadline 的 Close 在 ShutdownTimeout 后返回，不等待进行中的创建完成
+func TestCloseUsesShutdownTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.ShutdownTimeout = 100 * time.Millisecond
+	platform := newFakePlatform()
+	platform.createDelay = time.Minute
+	s := newTestSimple(t, cfg, platform)
+	for i := 0; i < 3; i++ {
+		go s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.creates) == 3 })
+
+	start := time.Now()
+	err := s.Close(context.Background())
+	elapsed := time.Since(start)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+	if elapsed < cfg.ShutdownTimeout || elapsed > time.Second {
+		t.Fatalf("close returned after %v, want about %v", elapsed, cfg.ShutdownTimeout)
+	}
+}
+
+// 调用方传入的 ctx 已有 deadline 时以 ctx 为准
+func TestCloseContextDeadlineTakesPrecedence(t *testing.T) {
+	cfg := testConfig()
+	cfg.ShutdownTimeout = time.Minute
+	platform := newFakePlatform()
+	platform.createDelay = time.Minute
+	s := newTestSimple(t, cfg, platform)
+	go s.Assign(context.Background(), assignRequest("r1"))
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.creates) == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := s.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("close returned after %v", elapsed)
+	}
+}
+
+// 平台调用耗时的 EWMA 收敛到注入的 50ms 延迟附近，超过预期耗时 3 倍时 CheckLive 返回 false
+func TestPlatformCallDuration(t *testing.T) {
+	cfg := testConfig()
+	cfg.ExpectedPlatformCallDuration = 10 * time.Millisecond
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+	if !s.CheckLive() {
+		t.Fatalf("scaler without platform calls should be live")
+	}
+
+	for i := 0; i < 5; i++ {
+		assignAll(t, s, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	waitFor(t, time.Second, func() bool { return s.runtimeStatus.GetMeanPlatformCallDuration() > 0 })
+	mean := s.runtimeStatus.GetMeanPlatformCallDuration()
+	if mean < 50*time.Millisecond || mean > 150*time.Millisecond {
+		t.Fatalf("mean platform call duration %v, want about 50ms", mean)
+	}
+	if s.CheckLive() {
+		t.Fatalf("slow platform calls should fail the liveness check")
+	}
+}
+
+// 把所有等待请求的入队时间往前拨 d
+func windWaiters(s *Simple, d time.Duration) {
+	s.longPollingMu.Lock()
+	defer s.longPollingMu.Unlock()
+	for _, request := range s.longPollingHeap.items {
+		request.enqueuedAt = request.enqueuedAt.Add(-d)
+	}
+}
+
+// 把最近一次成功的 Assign/Idle 和所有等待请求的入队时间往前拨 d
+func windLiveness(s *Simple, d time.Duration) {
+	atomic.AddInt64(&s.lastActivity, -int64(d))
+	windWaiters(s, d)
+}
+
+// 有请求等待超过 LivenessTimeout 且期间没有成功的 Assign/Idle 时 CheckLive 返回 false
+func TestCheckLiveAfterInactivity(t *testing.T) {
+	cfg := testConfig()
+	cfg.LivenessTimeout = 45 * time.Second
+	cfg.MaxInstances = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	// 没有流量时即使很久没有活动也视为存活
+	windLiveness(s, time.Minute)
+	if !s.CheckLive() {
+		t.Fatalf("scaler without traffic should be live")
+	}
+	busy := assignAll(t, s, assignRequest("busy"))[0]
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() {
+		_, err := s.Assign(ctx, assignRequest("waiting"))
+		served <- err
+	}()
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+	if !s.CheckLive() {
+		t.Fatalf("scaler with a recent assign should be live")
+	}
+
+	windLiveness(s, time.Minute)
+	if s.CheckLive() {
+		t.Fatalf("scaler should not be live after a minute without activity while a request waits")
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(busy, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("waiting assign: %v", err)
+	}
+	if !s.CheckLive() {
+		t.Fatalf("scaler should be live again after the idle call")
+	}
+}
+
+// 新建的 scaler 从创建时间开始计算，等待的请求超时前仍视为存活
+func TestCheckLiveSinceCreation(t *testing.T) {
+	cfg := testConfig()
+	cfg.LivenessTimeout = 45 * time.Second
+	platform := newFakePlatform()
+	// 创建期间请求一直在等待
+	platform.createDelay = 300 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+	go s.Assign(context.Background(), assignRequest("waiting"))
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+	windWaiters(s, time.Minute)
+	if !s.CheckLive() {
+		t.Fatalf("scaler created within the liveness timeout should be live")
+	}
+	atomic.AddInt64(&s.lastActivity, -int64(time.Minute))
+	if s.CheckLive() {
+		t.Fatalf("scaler created a minute ago with a waiting request should not be live")
+	}
+}
+
+// 退避时间从 100ms 开始翻倍，最长 5s，上下浮动 20%
+func TestCreateBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		base := 5 * time.Second
+		if attempt <= 6 {
+			base = 100 * time.Millisecond << (attempt - 1)
+		}
+		if base > 5*time.Second {
+			base = 5 * time.Second
+		}
+		for i := 0; i < 100; i++ {
+			delay := createBackoff(attempt)
+			if delay < base*8/10 || delay > base*12/10 {
+				t.Fatalf("attempt %d: backoff %s out of [%s, %s]", attempt, delay, base*8/10, base*12/10)
+			}
+		}
+	}
+}
+
+// 平台前几次创建失败，重试后等待的请求仍然拿到实例
+func TestCreateRetrySucceeds(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxCreateRetries = 3
+	platform := newFakePlatform()
+	platform.failCreates(2)
+	s := newTestSimple(t, cfg, platform)
+	reply, err := s.Assign(context.Background(), assignRequest("retry"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if reply.Assigment == nil || reply.Assigment.InstanceId == "" {
+		t.Fatalf("unexpected reply %v", reply)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 3 {
+		t.Fatalf("creates %d, want 3", n)
+	}
+}
+
+// 重试次数用完后等待的请求收到 Unavailable，并离开等待队列
+func TestCreateRetryExhausted(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxCreateRetries = 1
+	platform := newFakePlatform()
+	platform.failCreates(5)
+	s := newTestSimple(t, cfg, platform)
+	_, err := s.Assign(context.Background(), assignRequest("retry"))
+	if status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "injected create failure") {
+		t.Fatalf("got %v, want Unavailable with the create error", err)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("creates %d, want 2", n)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("failed request is still queued, queue length %d", n)
+	}
+}
+
+// CreateSlot 和 Init 分别受各自的超时限制，返回的错误带上超时的阶段
+func TestCreateInstancePhaseTimeouts(t *testing.T) {
+	cases := []struct {
+		name          string
+		createDelay   time.Duration
+		initDelay     time.Duration
+		phase         string
+		slotCreate    uint64
+		init          uint64
+		destroyedSlot bool
+	}{
+		{name: "create slot", createDelay: time.Minute, phase: CreatePhaseCreateSlot, slotCreate: 1},
+		{name: "init", initDelay: time.Minute, phase: CreatePhaseInit, init: 1, destroyedSlot: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.SlotCreateTimeout = 20 * time.Millisecond
+			cfg.SlotInitTimeout = 20 * time.Millisecond
+			platform := newFakePlatform()
+			platform.createDelay = c.createDelay
+			platform.initDelay = c.initDelay
+			s := newTestSimple(t, cfg, platform)
+
+			start := time.Now()
+			_, err := s.Assign(context.Background(), assignRequest("r1"))
+			if status.Code(err) != codes.DeadlineExceeded {
+				t.Fatalf("got %v, want DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("assign returned after %v", elapsed)
+			}
+			var createErr *CreateInstanceError
+			if err := s.createInstance(context.Background(), &testMeta().Meta, "r2", nil); !errors.As(err, &createErr) || createErr.Phase != c.phase || !createErr.Timeout {
+				t.Fatalf("got %v, want a %s timeout", err, c.phase)
+			}
+			if slotCreate, init := s.CreateTimeouts(); slotCreate != 2*c.slotCreate || init != 2*c.init {
+				t.Fatalf("got timeouts %d/%d, want %d/%d", slotCreate, init, 2*c.slotCreate, 2*c.init)
+			}
+			// Init 超时的 slot 会被销毁
+			if c.destroyedSlot {
+				waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+			}
+		})
+	}
+}
+
+// 平台挂起时等待的请求在 2 倍 SlotCreateTimeout 内收到 DeadlineExceeded，创建名额随之释放
+func TestSlotCreateTimeoutFailsWaiterPromptly(t *testing.T) {
+	cfg := testConfig()
+	cfg.SlotCreateTimeout = 100 * time.Millisecond
+	platform := newFakePlatform()
+	platform.createDelay = time.Hour
+	s := newTestSimple(t, cfg, platform)
+
+	start := time.Now()
+	_, err := s.Assign(context.Background(), assignRequest("r1"))
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("got %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*cfg.SlotCreateTimeout {
+		t.Fatalf("assign returned after %v, want within %v", elapsed, 2*cfg.SlotCreateTimeout)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingNum) == 0 })
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("timed out request is still queued, queue length %d", n)
+	}
+}
+
+// InstanceMeta 返回创建时的 meta，修改副本不影响 scaler
+func TestInstanceMetaReturnsCopy(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	meta := s.InstanceMeta()
+	want := testMeta()
+	if meta.Key != want.Key || meta.Runtime != want.Runtime || meta.TimeoutInSecs != want.TimeoutInSecs || meta.MemoryInMb != want.MemoryInMb {
+		t.Fatalf("got %+v, want %+v", &meta.Meta, &want.Meta)
+	}
+	meta.Key = "changed"
+	meta.MemoryInMb = 1
+	if again := s.InstanceMeta(); again.Key != want.Key || again.MemoryInMb != want.MemoryInMb {
+		t.Fatalf("modifying the copy changed the scaler meta: %+v", &again.Meta)
+	}
+}
+
+// 本应被 gc 回收的空闲实例在 KeepAliveInstance 延长的时间内保留
+func TestKeepAliveInstancePreventsGC(t *testing.T) {
+	cfg := testConfig()
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = 50 * time.Millisecond
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, assignRequest("kept"), assignRequest("expired"))
+	idleInOrder(t, s, replies...)
+
+	kept := replies[0].Assigment.InstanceId
+	if err := s.KeepAliveInstance(kept, time.Minute); err != nil {
+		t.Fatalf("keep alive: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 1 })
+	time.Sleep(5 * cfg.IdleDurationBeforeGC)
+	s.instancesMu.RLock()
+	_, ok := s.instances[kept]
+	s.instancesMu.RUnlock()
+	if !ok {
+		t.Fatalf("kept alive instance %s was collected", kept)
+	}
+}
+
+func TestKeepAliveInstanceErrors(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	if err := s.KeepAliveInstance("missing", time.Second); status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v, want NotFound", err)
+	}
+	reply := assignAll(t, s, assignRequest("busy"))[0]
+	if err := s.KeepAliveInstance(reply.Assigment.InstanceId, time.Second); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want FailedPrecondition", err)
+	}
+}
+
+func clientRequest(requestId, clientId string) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.ClientId = clientId
+	return request
+}
+
+// 只有配置了限流的客户端会被限流，其他客户端不受影响
+func TestClientRateLimit(t *testing.T) {
+	cfg := testConfig()
+	cfg.ClientRateLimits = map[string]float64{"limited": 1}
+	s := newTestSimple(t, cfg, newFakePlatform())
+	ctx := context.Background()
+
+	assignAll(t, s, clientRequest("l1", "limited"))
+	_, err := s.Assign(ctx, clientRequest("l2", "limited"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	for i := 0; i < 3; i++ {
+		assignAll(t, s, clientRequest(fmt.Sprintf("f%d", i), "free"))
+	}
+}
+
+// DrainIdle 从队尾开始删除，fn 返回 false 后停止
+func TestDrainIdlePartial(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	var requests []*pb.AssignRequest
+	for i := 0; i < 5; i++ {
+		requests = append(requests, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	replies := assignAll(t, s, requests...)
+	idleInOrder(t, s, replies...)
+
+	removed := 0
+	drained := s.DrainIdle(func(instance *model2.Instance) bool {
+		removed++
+		return removed <= 3
+	})
+	if len(drained) != 3 {
+		t.Fatalf("drained %d instances, want 3", len(drained))
+	}
+	// 最早空闲的实例在队尾，最先删除
+	for i, instance := range drained {
+		if instance.Id != replies[i].Assigment.InstanceId {
+			t.Fatalf("drained %s at %d, want %s", instance.Id, i, replies[i].Assigment.InstanceId)
+		}
+	}
+	if stats := s.Stats(); stats.TotalIdleInstance != 2 || stats.TotalInstance != 2 {
+		t.Fatalf("unexpected stats after drain %+v", stats)
+	}
+	// 调用方负责销毁返回的实例
+	if n := platform.liveSlots(); n != 5 {
+		t.Fatalf("DrainIdle destroyed slots: %d live, want 5", n)
+	}
+}
+
+// 空闲队列中实例 id 的顺序，队首在前
+func idleIds(s *Simple) []string {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	var ids []string
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		ids = append(ids, element.Value.(*model2.Instance).Id)
+	}
+	return ids
+}
+
+// 相同 routing key 在相同的空闲队列上总是选中第 hash % len 个实例
+func TestRoutingKeySelectsSameInstance(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	var requests []*pb.AssignRequest
+	for i := 0; i < 4; i++ {
+		requests = append(requests, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	idleInOrder(t, s, assignAll(t, s, requests...)...)
+
+	for _, key := range []string{"tenant-a", "tenant-b", "tenant-c", "tenant-a"} {
+		// 实例放回后移到队首，每次按当前的空闲队列计算
+		ids := idleIds(s)
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		want := ids[h.Sum32()%uint32(len(ids))]
+		request := assignRequest(key)
+		request.RoutingKey = key
+		reply := assignAll(t, s, request)[0]
+		if reply.Assigment.InstanceId != want {
+			t.Fatalf("routing key %s got %s, want %s", key, reply.Assigment.InstanceId, want)
+		}
+		idleInOrder(t, s, reply)
+	}
+
+	// 空闲队列不变时多次选择结果相同
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	if first, second := s.routedIdleInstance("tenant-a"), s.routedIdleInstance("tenant-a"); first != second {
+		t.Fatalf("same routing key selected different instances")
+	}
+	if s.routedIdleInstance("") != nil {
+		t.Fatalf("empty routing key should fall back to the default order")
+	}
+}
+
+func tierRequest(requestId, tier string) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.RequiredNetworkTier = tier
+	return request
+}
+
+// 优先选择网络等级匹配的空闲实例，没有匹配时退回任意等级并计数
+func TestNetworkTierRouting(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	platform.setNetworkTier("premium")
+	premium := assignAll(t, s, assignRequest("premium"))[0]
+	platform.setNetworkTier("standard")
+	standard := assignAll(t, s, assignRequest("standard"))[0]
+	// standard 实例后放回，位于队首
+	idleInOrder(t, s, premium, standard)
+
+	reply := assignAll(t, s, tierRequest("r1", "premium"))[0]
+	if reply.Assigment.InstanceId != premium.Assigment.InstanceId {
+		t.Fatalf("got %s, want premium instance %s", reply.Assigment.InstanceId, premium.Assigment.InstanceId)
+	}
+	if n := s.NetworkTierMismatches(); n != 0 {
+		t.Fatalf("got %d mismatches, want 0", n)
+	}
+
+	// 只剩 standard 实例，要求 premium 的请求退回使用它
+	reply = assignAll(t, s, tierRequest("r2", "premium"))[0]
+	if reply.Assigment.InstanceId != standard.Assigment.InstanceId {
+		t.Fatalf("got %s, want fallback instance %s", reply.Assigment.InstanceId, standard.Assigment.InstanceId)
+	}
+	if n := s.NetworkTierMismatches(); n != 1 {
+		t.Fatalf("got %d mismatches, want 1", n)
+	}
+}
+
+// 创建 n 个实例并按顺序放回空闲队列
+func idleN(tb testing.TB, s *Simple, n int) {
+	tb.Helper()
+	var requests []*pb.AssignRequest
+	for i := 0; i < n; i++ {
+		requests = append(requests, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	idleInOrder(tb, s, assignAll(tb, s, requests...)...)
+}
+
+// 100 个空闲实例按每页 10 个翻页，按空闲队列顺序不重不漏
+func TestGetIdlePage(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	idleN(t, s, 100)
+
+	want := idleIds(s)
+	var got []string
+	for offset := 0; ; offset += 10 {
+		page := s.GetIdlePage(offset, 10)
+		if len(page) == 0 {
+			break
+		}
+		if len(page) != 10 {
+			t.Fatalf("page at offset %d has %d instances", offset, len(page))
+		}
+		for _, detail := range page {
+			got = append(got, detail.InstanceId)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("paginated ids do not match the idle list")
+	}
+	if page := s.GetIdlePage(95, 10); len(page) != 5 {
+		t.Fatalf("last partial page has %d instances, want 5", len(page))
+	}
+	if s.GetIdlePage(-1, 10) != nil || s.GetIdlePage(0, 0) != nil {
+		t.Fatalf("invalid offset or limit should return nil")
+	}
+}
+
+// MetaOverrides 中的 InitTimeoutMs 只对对应的 meta key 生效
+func TestMetaOverrideInitTimeout(t *testing.T) {
+	cases := []struct {
+		name    string
+		metaKey string
+		want    codes.Code
+	}{
+		{name: "override", metaKey: testMeta().Key, want: codes.DeadlineExceeded},
+		{name: "other key", metaKey: "other", want: codes.OK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.SlotInitTimeout = time.Minute
+			cfg.MetaOverrides = map[string]config.MetaConfig{c.metaKey: {InitTimeoutMs: 20}}
+			platform := newFakePlatform()
+			platform.initDelay = 100 * time.Millisecond
+			s := newTestSimple(t, cfg, platform)
+
+			start := time.Now()
+			_, err := s.Assign(context.Background(), assignRequest("r1"))
+			if status.Code(err) != c.want {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+			if c.want == codes.DeadlineExceeded && time.Since(start) > time.Second {
+				t.Fatalf("per-key init timeout was not applied")
+			}
+		})
+	}
+}
+
+func dryRunRequest(requestId string) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.DryRun = true
+	return request
+}
+
+// 没有空闲实例时 dry run 返回估算的冷启动延迟，不创建实例也不进入等待队列
+func TestDryRunWithoutIdleInstance(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 20 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+
+	reply, err := s.Assign(ctx, dryRunRequest("dry"))
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !reply.IsDryRun || reply.Assigment.InstanceId != "" {
+		t.Fatalf("unexpected dry run reply %+v", reply)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 0 || queueLen(s) != 0 {
+		t.Fatalf("dry run created %d instances and left %d waiters", n, queueLen(s))
+	}
+
+	// 有过一次创建后按创建耗时估算
+	assignAll(t, s, assignRequest("r1"))
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingDuration) > 0 })
+	reply, err = s.Assign(ctx, dryRunRequest("dry2"))
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if reply.WarmupLatencyMs < 20 {
+		t.Fatalf("got warmup latency %dms, want at least the create delay", reply.WarmupLatencyMs)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("dry run created instances: %d creates", n)
+	}
+}
+
+// 有空闲实例时 dry run 返回会被选中的实例，但不从空闲队列取出
+func TestDryRunDoesNotConsumeIdleInstance(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idle := assignAll(t, s, assignRequest("r1"))[0]
+	idleInOrder(t, s, idle)
+
+	for i := 0; i < 3; i++ {
+		reply := assignAll(t, s, dryRunRequest(fmt.Sprintf("dry%d", i)))[0]
+		if !reply.IsDryRun || reply.Assigment.InstanceId != idle.Assigment.InstanceId || reply.WarmupLatencyMs != 0 {
+			t.Fatalf("unexpected dry run reply %+v", reply)
+		}
+	}
+	if stats := s.Stats(); stats.TotalIdleInstance != 1 {
+		t.Fatalf("dry run consumed the idle instance: %+v", stats)
+	}
+	if reply := assignAll(t, s, assignRequest("r2"))[0]; reply.Assigment.InstanceId != idle.Assigment.InstanceId {
+		t.Fatalf("idle instance is no longer available")
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("got %d creates, want 1", n)
+	}
+}
+
+func reuseConfig(poolSize int) *config.Config {
+	cfg := testConfig()
+	cfg.SlotReusePoolSize = poolSize
+	return cfg
+}
+
+// 回收的实例 slot 重置后放入复用池，下一次创建直接使用，不再调用 CreateSlot
+func TestSlotReuseHit(t *testing.T) {
+	platform := newFakeResetPlatform()
+	s := newTestSimple(t, reuseConfig(1), platform)
+	first := assignAll(t, s, assignRequest("r1"))[0]
+	if err := s.ForceEvict(first.Assigment.InstanceId); err != nil {
+		t.Fatalf("force evict: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return len(s.slotReusePool) == 1 })
+	if n := atomic.LoadInt64(&platform.destroys); n != 0 {
+		t.Fatalf("reset slot was destroyed: %d destroys", n)
+	}
+
+	second := assignAll(t, s, assignRequest("r2"))[0]
+	if second.Assigment.SlotId != first.Assigment.SlotId {
+		t.Fatalf("got slot %s, want reused slot %s", second.Assigment.SlotId, first.Assigment.SlotId)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("got %d creates, want 1", n)
+	}
+	if n := s.SlotReusedTotal(); n != 1 {
+		t.Fatalf("got %d reused slots, want 1", n)
+	}
+}
+
+// 复用池已满、重置失败或实例异常时 slot 直接销毁
+func TestSlotReuseFallback(t *testing.T) {
+	t.Run("pool full", func(t *testing.T) {
+		platform := newFakeResetPlatform()
+		s := newTestSimple(t, reuseConfig(1), platform)
+		replies := assignAll(t, s, assignRequest("r1"), assignRequest("r2"))
+		for _, reply := range replies {
+			if err := s.ForceEvict(reply.Assigment.InstanceId); err != nil {
+				t.Fatalf("force evict: %v", err)
+			}
+		}
+		waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroys) == 1 })
+		if n := len(s.slotReusePool); n != 1 {
+			t.Fatalf("got %d pooled slots, want 1", n)
+		}
+	})
+	t.Run("reset failed", func(t *testing.T) {
+		platform := newFakeResetPlatform()
+		platform.setResetErr(errors.New("reset failed"))
+		s := newTestSimple(t, reuseConfig(1), platform)
+		reply := assignAll(t, s, assignRequest("r1"))[0]
+		if err := s.ForceEvict(reply.Assigment.InstanceId); err != nil {
+			t.Fatalf("force evict: %v", err)
+		}
+		waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+		if n := atomic.LoadInt64(&platform.resets); n != 1 || len(s.slotReusePool) != 0 {
+			t.Fatalf("got %d resets and %d pooled slots", n, len(s.slotReusePool))
+		}
+	})
+	t.Run("bad instance", func(t *testing.T) {
+		platform := newFakeResetPlatform()
+		s := newTestSimple(t, reuseConfig(1), platform)
+		reply := assignAll(t, s, assignRequest("r1"))[0]
+		if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+		waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+		if n := atomic.LoadInt64(&platform.resets); n != 0 {
+			t.Fatalf("bad instance slot was reset %d times", n)
+		}
+	})
+}
+
+func TestDumpStats(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	replies := assignAll(t, s, assignRequest("r1"), assignRequest("r2"))
+	idleInOrder(t, s, replies[0])
+
+	var buf bytes.Buffer
+	if err := s.DumpStats(&buf); err != nil {
+		t.Fatalf("dump stats: %v", err)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			t.Fatalf("malformed line %q", line)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	for _, key := range []string{"app", "idle_instances", "total_instances", "creating", "pending_requests", "rps", "request_cost_ms", "mean_assign_latency_ms", "p95_latency_ms", "pool_hit_ratio", "allocated_memory_mb"} {
+		if _, ok := values[key]; !ok {
+			t.Fatalf("missing key %s in:\n%s", key, buf.String())
+		}
+	}
+	if values["idle_instances"] != "1" || values["total_instances"] != "2" || values["app"] != testMeta().Key {
+		t.Fatalf("unexpected values %v", values)
+	}
+}
+
+// 请求取消后立即从等待队列移除，之后创建好的实例放回空闲队列
+func TestCanceledAssignLeavesQueue(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Assign(ctx, assignRequest("r1"))
+		done <- err
+	}()
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context canceled", err)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("canceled request is still queued: %d waiters", n)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 1 })
+}
+
+// 取消与投递并发发生时，实例要么交给请求，要么放回空闲队列，不会一直处于忙碌状态
+func TestCanceledAssignRacingDelivery(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 5 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+
+	var wg sync.WaitGroup
+	var assigned []*pb.AssignReply
+	var mu sync.Mutex
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%10)*time.Millisecond)
+			defer cancel()
+			if reply, err := s.Assign(ctx, assignRequest(fmt.Sprintf("r%d", i))); err == nil {
+				mu.Lock()
+				assigned = append(assigned, reply)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	ctx := context.Background()
+	for _, reply := range assigned {
+		if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	// 所有创建完成后每个实例都应该空闲
+	waitFor(t, 2*time.Second, func() bool {
+		stats := s.Stats()
+		return atomic.LoadInt64(&s.creatingNum) == 0 && queueLen(s) == 0 && stats.TotalIdleInstance == stats.TotalInstance
+	})
+}
+
+// 记录 "assign queued" 日志中的原因
+type queuedReasonHandler struct {
+	mu      sync.Mutex
+	reasons map[string]string
+}
+
+func (h *queuedReasonHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *queuedReasonHandler) Handle(_ context.Context, record slog.Record) error {
+	if record.Message != "assign queued" {
+		return nil
+	}
+	var requestId, reason string
+	record.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case "requestId":
+			requestId = attr.Value.String()
+		case "reason":
+			reason = attr.Value.String()
+		}
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reasons[requestId] = reason
+	return nil
+}
+
+func (h *queuedReasonHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *queuedReasonHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *queuedReasonHandler) reason(requestId string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.reasons[requestId]
+}
+
+// 只在没有创建中的实例时创建
+type createOncePolicy struct{}
+
+func (createOncePolicy) ShouldCreate(input ScaleDecisionInput) bool {
+	return input.CreatingCount == 0
+}
+
+// 触发创建的请求记录为 none_idle，等待已在创建的实例的请求记录为 await_create
+func TestAssignQueuedReason(t *testing.T) {
+	handler := &queuedReasonHandler{reasons: make(map[string]string)}
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform, WithLogger(slog.New(handler)), WithScalingPolicy(createOncePolicy{}))
+
+	var wg sync.WaitGroup
+	for _, requestId := range []string{"first", "second"} {
+		wg.Add(1)
+		go func(requestId string) {
+			defer wg.Done()
+			reply, err := s.Assign(context.Background(), assignRequest(requestId))
+			if err != nil {
+				t.Errorf("assign %s: %v", requestId, err)
+				return
+			}
+			// 释放实例，让另一个请求也能拿到
+			s.Idle(context.Background(), idleRequest(reply, false))
+		}(requestId)
+		waitFor(t, time.Second, func() bool { return handler.reason(requestId) != "" })
+	}
+	wg.Wait()
+	if reason := handler.reason("first"); reason != AssignQueuedNoneIdle {
+		t.Fatalf("first request queued for %q, want %q", reason, AssignQueuedNoneIdle)
+	}
+	if reason := handler.reason("second"); reason != AssignQueuedAwaitCreate {
+		t.Fatalf("second request queued for %q, want %q", reason, AssignQueuedAwaitCreate)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 1 {
+		t.Fatalf("got %d creates, want 1", n)
+	}
+}
+
+// Config 返回当前生效配置的副本，ReloadConfig 之后返回新的配置
+func TestConfigReturnsCopy(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 7
+	s := newTestSimple(t, cfg, newFakePlatform())
+	got := s.Config()
+	if got.MaxInstances != 7 || got.GcInterval != cfg.GcInterval || got.IdleDurationBeforeGC != cfg.IdleDurationBeforeGC {
+		t.Fatalf("config does not match the one passed to New")
+	}
+	got.MaxInstances = 1
+	if s.Config().MaxInstances != 7 {
+		t.Fatalf("modifying the copy changed the scaler config")
+	}
+
+	reloaded := *cfg
+	reloaded.MaxInstances = 9
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if n := s.Config().MaxInstances; n != 9 {
+		t.Fatalf("got MaxInstances %d after reload, want 9", n)
+	}
+}
+
+// 并发读写实例的 CustomData，同时通过 GetIdlePage 读取快照，在 -race 下运行
+func TestInstanceCustomDataConcurrent(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	s.instancesMu.RLock()
+	instance := s.instances[reply.Assigment.InstanceId]
+	s.instancesMu.RUnlock()
+	idleInOrder(t, s, reply)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				instance.SetCustomData(fmt.Sprintf("key-%d", i), j)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				instance.GetCustomData(fmt.Sprintf("key-%d", i))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.GetIdlePage(0, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 4; i++ {
+		if value, ok := instance.GetCustomData(fmt.Sprintf("key-%d", i)); !ok || value != 99 {
+			t.Fatalf("key-%d got %v, want 99", i, value)
+		}
+	}
+	if page := s.GetIdlePage(0, 1); len(page) != 1 || page[0].CustomData["key-0"] != 99 {
+		t.Fatalf("custom data is not exposed in instance details: %+v", page)
+	}
+}
+
+// instancesMu 与 idleMu 拆分后，读操作和写操作两两并发执行，在 -race 下不应出现数据竞争或死锁
+// 结束后 instances 与平台上存活的 slot 数量一致
+func TestConcurrentInstanceAndIdleAccess(t *testing.T) {
+	type op struct {
+		name string
+		run  func(s *Simple, i int)
+	}
+	readers := []op{
+		{"stats", func(s *Simple, i int) { s.Stats() }},
+		{"idle page", func(s *Simple, i int) { s.GetIdlePage(0, 10) }},
+		{"count by status", func(s *Simple, i int) { s.InstanceCountByStatus() }},
+		{"dry run", func(s *Simple, i int) { s.Assign(context.Background(), dryRunRequest(fmt.Sprintf("dry-%d", i))) }},
+	}
+	writers := []op{
+		{"assign idle", func(s *Simple, i int) {
+			reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("w-%d-%d", i, time.Now().UnixNano())))
+			if err == nil {
+				s.Idle(context.Background(), idleRequest(reply, i%5 == 0))
+			}
+		}},
+		{"force evict", func(s *Simple, i int) {
+			if page := s.GetIdlePage(0, 1); len(page) > 0 {
+				s.ForceEvict(page[0].InstanceId)
+			}
+		}},
+		{"drain", func(s *Simple, i int) {
+			drained := s.DrainIdle(func(instance *model2.Instance) bool { return i%3 == 0 })
+			for _, instance := range drained {
+				s.destroyInstance(instance, "drain")
+			}
+		}},
+	}
+	var pairs [][2]op
+	for _, writer := range writers {
+		for _, reader := range readers {
+			pairs = append(pairs, [2]op{reader, writer})
+		}
+	}
+	for i := range writers {
+		for j := i; j < len(writers); j++ {
+			pairs = append(pairs, [2]op{writers[i], writers[j]})
+		}
+	}
+	for _, pair := range pairs {
+		pair := pair
+		t.Run(pair[0].name+"/"+pair[1].name, func(t *testing.T) {
+			platform := newFakePlatform()
+			s := newTestSimple(t, testConfig(), platform)
+			if err := s.Warmup(context.Background(), 3); err != nil {
+				t.Fatalf("warmup: %v", err)
+			}
+			var wg sync.WaitGroup
+			for _, o := range pair {
+				for g := 0; g < 2; g++ {
+					wg.Add(1)
+					go func(run func(s *Simple, i int)) {
+						defer wg.Done()
+						for i := 0; i < 30; i++ {
+							run(s, i)
+						}
+					}(o.run)
+				}
+			}
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatalf("concurrent operations did not finish, possible deadlock")
+			}
+			waitFor(t, 2*time.Second, func() bool { return s.Stats().TotalInstance == platform.liveSlots() })
+			if stats := s.Stats(); stats.TotalIdleInstance > stats.TotalInstance {
+				t.Fatalf("idle list holds more instances than the instance map: %+v", stats)
+			}
+		})
+	}
+}
+
+// 按提示分配，返回选中的实例
+func assignWithHint(tb testing.TB, s *Simple, requestId string, hint AssignHint) string {
+	tb.Helper()
+	reply, err := s.AssignWithHint(context.Background(), assignRequest(requestId), hint)
+	if err != nil {
+		tb.Fatalf("assign with hint: %v", err)
+	}
+	return reply.Assigment.InstanceId
+}
+
+// 每个提示字段单独生效，无法满足时退回默认选择空闲队列队首
+func TestAssignWithHint(t *testing.T) {
+	cases := []struct {
+		name string
+		// 根据空闲队列从队首到队尾的实例构造提示，返回期望选中的实例
+		hint func(s *Simple, ids []string) (AssignHint, string)
+	}{
+		{"no hint", func(s *Simple, ids []string) (AssignHint, string) {
+			return AssignHint{}, ids[0]
+		}},
+		{"prefer instance", func(s *Simple, ids []string) (AssignHint, string) {
+			return AssignHint{PreferInstanceId: ids[2]}, ids[2]
+		}},
+		{"prefer unknown instance", func(s *Simple, ids []string) (AssignHint, string) {
+			return AssignHint{PreferInstanceId: "missing"}, ids[0]
+		}},
+		{"avoid instances", func(s *Simple, ids []string) (AssignHint, string) {
+			return AssignHint{AvoidInstanceIds: ids[:2]}, ids[2]
+		}},
+		{"avoid all instances", func(s *Simple, ids []string) (AssignHint, string) {
+			return AssignHint{AvoidInstanceIds: ids}, ids[0]
+		}},
+		{"max idle age", func(s *Simple, ids []string) (AssignHint, string) {
+			s.idleMu.Lock()
+			for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+				if instance := element.Value.(*model2.Instance); instance.Id != ids[1] {
+					instance.LastIdleTime = time.Now().Add(-time.Minute)
+				}
+			}
+			s.idleMu.Unlock()
+			return AssignHint{MaxIdleAgeMs: 10000}, ids[1]
+		}},
+		{"max idle age unsatisfied", func(s *Simple, ids []string) (AssignHint, string) {
+			s.idleMu.Lock()
+			for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+				element.Value.(*model2.Instance).LastIdleTime = time.Now().Add(-time.Minute)
+			}
+			s.idleMu.Unlock()
+			return AssignHint{MaxIdleAgeMs: 10000}, ids[0]
+		}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			s := newTestSimple(t, testConfig(), newFakePlatform())
+			idleN(t, s, 3)
+			hint, want := c.hint(s, idleIds(s))
+			if got := assignWithHint(t, s, "hinted", hint); got != want {
+				t.Fatalf("got instance %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// RequireLabels 按实例进入空闲队列时 CustomData 中的字符串值匹配
+func TestAssignWithHintRequireLabels(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"))
+	labeled := replies[0].Assigment.InstanceId
+	s.instancesMu.RLock()
+	s.instances[labeled].SetCustomData("zone", "a")
+	s.instances[replies[1].Assigment.InstanceId].SetCustomData("zone", 1)
+	s.instancesMu.RUnlock()
+	idleInOrder(t, s, replies...)
+	front := idleIds(s)[0]
+
+	if got := assignWithHint(t, s, "miss", AssignHint{RequireLabels: map[string]string{"zone": "b"}}); got != front {
+		t.Fatalf("unsatisfied labels should fall back to the front, got %s want %s", got, front)
+	}
+	if got := assignWithHint(t, s, "hit", AssignHint{RequireLabels: map[string]string{"zone": "a"}}); got != labeled {
+		t.Fatalf("got instance %s, want labeled instance %s", got, labeled)
+	}
+}
+
+// 在等待队列中加入 n 个请求，返回它们的 channel
+func pushWaiters(s *Simple, n int) []chan *model2.Instance {
+	s.longPollingMu.Lock()
+	defer s.longPollingMu.Unlock()
+	chans := make([]chan *model2.Instance, n)
+	for i := range chans {
+		chans[i] = make(chan *model2.Instance, 1)
+		s.longPollingHeap.push(chans[i], time.Time{})
+	}
+	return chans
+}
+
+func readyInstances(n int) []*model2.Instance {
+	instances := make([]*model2.Instance, n)
+	for i := range instances {
+		instances[i] = &model2.Instance{Id: fmt.Sprintf("ready-%d", i)}
+	}
+	return instances
+}
+
+// 一批实例同时就绪时在一次临界区内分配给全部等待的请求，没有实例进入空闲队列
+func TestNotifyRequestsBatch(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	chans := pushWaiters(s, 5)
+	s.notifyRequests(readyInstances(5))
+
+	delivered := make(map[string]bool)
+	for i, ch := range chans {
+		select {
+		case instance := <-ch:
+			delivered[instance.Id] = true
+		default:
+			t.Fatalf("waiter %d got no instance", i)
+		}
+	}
+	if len(delivered) != 5 {
+		t.Fatalf("instances were not delivered one per waiter: %v", delivered)
+	}
+	s.longPollingMu.Lock()
+	pending := s.longPollingHeap.Len()
+	s.longPollingMu.Unlock()
+	if stats := s.Stats(); pending != 0 || stats.TotalIdleInstance != 0 {
+		t.Fatalf("pending %d, idle %d, want both 0", pending, stats.TotalIdleInstance)
+	}
+}
+
+// 5 个请求同时等待各自创建的实例，每个请求分到不同的实例
+func TestConcurrentCreatesServeWaiters(t *testing.T) {
+	platform := newFakePlatform()
+	platform.createDelay = 20 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	replies := make([]*pb.AssignReply, 5)
+	var wg sync.WaitGroup
+	for i := range replies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i)))
+			if err != nil {
+				t.Errorf("assign: %v", err)
+				return
+			}
+			replies[i] = reply
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+	if ids := instanceIds(replies); len(ids) != 5 {
+		t.Fatalf("waiters shared instances: %v", ids)
+	}
+	if creates := atomic.LoadInt64(&platform.creates); creates != 5 {
+		t.Fatalf("got %d creates, want 5", creates)
+	}
+}
+
+// 50 个实例同时就绪：批量通知只获取一次 longPollingMu，逐个通知时 50 个 goroutine 争抢 longPollingMu
+func BenchmarkNotifyRequests(b *testing.B) {
+	const n = 50
+	b.Run("batch", func(b *testing.B) {
+		s := newTestSimple(b, testConfig(), newFakePlatform())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pushWaiters(s, n)
+			s.notifyRequests(readyInstances(n))
+		}
+	})
+	b.Run("one by one", func(b *testing.B) {
+		s := newTestSimple(b, testConfig(), newFakePlatform())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pushWaiters(s, n)
+			var wg sync.WaitGroup
+			for _, instance := range readyInstances(n) {
+				wg.Add(1)
+				go func(instance *model2.Instance) {
+					defer wg.Done()
+					s.notifyRequest(instance)
+				}(instance)
+			}
+			wg.Wait()
+		}
+	})
+}
+
+// 按日志消息计数的 slog handler
+type messageCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newMessageCounter() *messageCounter {
+	return &messageCounter{counts: make(map[string]int)}
+}
+
+func (h *messageCounter) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *messageCounter) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[record.Message]++
+	return nil
+}
+
+func (h *messageCounter) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *messageCounter) WithGroup(string) slog.Handler { return h }
+
+func (h *messageCounter) count(message string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.counts[message]
+}
+
+// 6 次 Init 成功、4 次失败后失败率为 0.4，超过阈值时输出告警
+func TestInitErrorRate(t *testing.T) {
+	cfg := testConfig()
+	cfg.InitErrorRateThreshold = 0.3
+	platform := newFakePlatform()
+	logs := newMessageCounter()
+	s := newTestSimple(t, cfg, platform, WithLogger(slog.New(logs)))
+	if rate := s.InitErrorRate(); rate != 0 {
+		t.Fatalf("got rate %v before any init, want 0", rate)
+	}
+	if err := s.Warmup(context.Background(), 6); err != nil {
+		t.Fatalf("warmup: %v", err)
+	}
+	platform.setInitErr(status.Error(codes.Internal, "init failed"))
+	if err := s.Warmup(context.Background(), 4); err == nil {
+		t.Fatalf("warmup should fail when init fails")
+	}
+	if rate := s.InitErrorRate(); rate != 0.4 {
+		t.Fatalf("got rate %v, want 0.4", rate)
+	}
+	if logs.count("init error rate exceeds threshold") == 0 {
+		t.Fatalf("expected a warning when the rate exceeds the threshold")
+	}
+}
+
+// 空闲队列中实例的 InitDurationInMs，从队首到队尾
+func idleInitDurations(s *Simple) []int64 {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	var durations []int64
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		durations = append(durations, element.Value.(*model2.Instance).InitDurationInMs)
+	}
+	return durations
+}
+
+// 开启 SortIdleByInitDuration 后空闲队列按初始化耗时从小到大排列，Assign 取初始化最快的实例，归还后回到原位置
+func TestSortIdleByInitDuration(t *testing.T) {
+	cfg := testConfig()
+	cfg.SortIdleByInitDuration = true
+	platform := newFakePlatform()
+	platform.setInitDurations(30, 10, 50, 20, 40)
+	s := newTestSimple(t, cfg, platform)
+	replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"), assignRequest("r3"), assignRequest("r4"))
+	idleInOrder(t, s, replies...)
+	if got := fmt.Sprint(idleInitDurations(s)); got != "[10 20 30 40 50]" {
+		t.Fatalf("idle list is not sorted by init duration: %s", got)
+	}
+
+	reply := assignAll(t, s, assignRequest("fast"))[0]
+	if got := fmt.Sprint(idleInitDurations(s)); got != "[20 30 40 50]" {
+		t.Fatalf("assign did not take the fastest instance: %s", got)
+	}
+	idleInOrder(t, s, reply)
+	if got := fmt.Sprint(idleInitDurations(s)); got != "[10 20 30 40 50]" {
+		t.Fatalf("returned instance was not reinserted in order: %s", got)
+	}
+}
+
+// 1000 个空闲实例时，有序插入后直接取队首，与无序插入后线性扫描出初始化最快的实例对比
+func BenchmarkIdleInitDurationOrder(b *testing.B) {
+	const n = 1000
+	newIdle := func(b *testing.B, sorted bool) *Simple {
+		s := newTestSimple(b, testConfig(), newFakePlatform())
+		s.idleMu.Lock()
+		defer s.idleMu.Unlock()
+		for i := 0; i < n; i++ {
+			instance := &model2.Instance{Id: fmt.Sprintf("i%d", i), InitDurationInMs: int64(i * 7919 % n)}
+			if sorted {
+				s.insertSortedIdle(instance)
+			} else {
+				s.pushIdleFront(instance)
+			}
+		}
+		return s
+	}
+	b.Run("sorted insert", func(b *testing.B) {
+		s := newIdle(b, true)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			s.idleMu.Lock()
+			element := s.idleInstance.Front()
+			s.removeIdleElement(element)
+			s.insertSortedIdle(element.Value.(*model2.Instance))
+			s.idleMu.Unlock()
+		}
+	})
+	b.Run("linear scan", func(b *testing.B) {
+		s := newIdle(b, false)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			s.idleMu.Lock()
+			fastest := s.idleInstance.Front()
+			for element := fastest.Next(); element != nil; element = element.Next() {
+				if element.Value.(*model2.Instance).InitDurationInMs < fastest.Value.(*model2.Instance).InitDurationInMs {
+					fastest = element
+				}
+			}
+			s.removeIdleElement(fastest)
+			s.pushIdleFront(fastest.Value.(*model2.Instance))
+			s.idleMu.Unlock()
+		}
+	})
+}
+
+// 创建实例耗时 200ms 的 Assign 超过 100ms 阈值时调用 OnSlowAssign，命中空闲实例的 Assign 不调用
+func TestSlowAssignCallback(t *testing.T) {
+	var mu sync.Mutex
+	slow := make(map[string]time.Duration)
+	cfg := testConfig()
+	cfg.AssignSlowPathThreshold = 100 * time.Millisecond
+	cfg.OnSlowAssign = func(requestId string, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		slow[requestId] = latency
+	}
+	platform := newFakePlatform()
+	platform.createDelay = 200 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+
+	idleInOrder(t, s, assignAll(t, s, assignRequest("cold"))...)
+	assignAll(t, s, assignRequest("warm"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if latency, ok := slow["cold"]; !ok || latency < 200*time.Millisecond {
+		t.Fatalf("slow assign was not reported: %v", slow)
+	}
+	if _, ok := slow["warm"]; ok || len(slow) != 1 {
+		t.Fatalf("fast assign should not be reported: %v", slow)
+	}
+}
+
+// 部分回收时 EvictPolicyPreserveFastInit 先回收初始化最慢的实例，默认策略回收空闲最久的实例
+func TestClearEvictPolicy(t *testing.T) {
+	cases := []struct {
+		policy config.EvictPolicy
+		want   string
+	}{
+		{config.EvictPolicyPreserveFastInit, "[10 20 30]"},
+		{config.EvictPolicyIdleTime, "[20 30 40]"},
+	}
+	for _, c := range cases {
+		cfg := testConfig()
+		cfg.EvictPolicy = c.policy
+		platform := newFakePlatform()
+		// 按归还顺序，空闲最久的两个实例是 50 和 10
+		platform.setInitDurations(50, 10, 40, 20, 30)
+		s := newTestSimple(t, cfg, platform)
+		replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"), assignRequest("r3"), assignRequest("r4"))
+		idleInOrder(t, s, replies...)
+
+		s.Clear(0.4)
+		waitFor(t, time.Second, func() bool { return platform.liveSlots() == 3 })
+		durations := idleInitDurations(s)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		if got := fmt.Sprint(durations); got != c.want {
+			t.Fatalf("policy %v kept %s, want %s", c.policy, got, c.want)
+		}
+	}
+}
+
+// Clear 回收 floor(空闲实例数 * rate) 个实例，rate 超过 1 时按 1 处理，非正数和 NaN 不回收
+func TestClearRate(t *testing.T) {
+	cases := []struct {
+		rate float64
+		want int
+	}{
+		{-1, 4},
+		{0, 4},
+		{math.NaN(), 4},
+		{0.3, 3},
+		{0.5, 2},
+		{1, 0},
+		{2, 0},
+	}
+	for _, c := range cases {
+		platform := newFakePlatform()
+		s := newTestSimple(t, testConfig(), platform)
+		replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"), assignRequest("r2"), assignRequest("r3"))
+		idleInOrder(t, s, replies...)
+
+		s.Clear(c.rate)
+		waitFor(t, time.Second, func() bool { return platform.liveSlots() == c.want })
+		if stats := s.Stats(); stats.TotalIdleInstance != c.want || stats.TotalInstance != c.want {
+			t.Fatalf("rate %v left %+v, want %d instances", c.rate, stats, c.want)
+		}
+	}
+}
+
+// 并发的 Clear 和 gc 不会重复销毁同一个实例
+func TestClearConcurrentWithGC(t *testing.T) {
+	cfg := testConfig()
+	cfg.GcInterval = 5 * time.Millisecond
+	cfg.IdleDurationBeforeGC = time.Millisecond
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	const n = 8
+	requests := make([]*pb.AssignRequest, n)
+	for i := range requests {
+		requests[i] = assignRequest(fmt.Sprintf("r%d", i))
+	}
+	replies := assignAll(t, s, requests...)
+	for _, reply := range replies {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+			t.Fatalf("idle: %v", err)
+		}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				s.Clear(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 && s.Stats().TotalInstance == 0 })
+	if destroys := atomic.LoadInt64(&platform.destroys); destroys != n {
+		t.Fatalf("destroys %d, want %d", destroys, n)
+	}
+}
+
+// Score 由空闲时间比例（不超过 1）、1/(UsageCount+1) 和 InitDurationInMs 比例相加
+func TestInstanceScore(t *testing.T) {
+	idleDuration := time.Minute
+	cases := []struct {
+		name       string
+		idleFor    time.Duration
+		usageCount int64
+		initMs     int64
+		maxInitMs  int64
+		want       float64
+	}{
+		{"new instance", 0, 0, 0, 0, 1},
+		{"half idle", 30 * time.Second, 1, 0, 100, 1},
+		{"idle age is capped", time.Hour, 3, 0, 100, 1.25},
+		{"slow init", 0, 1, 100, 100, 1.5},
+		{"all factors", time.Hour, 0, 50, 100, 2.5},
+	}
+	for _, c := range cases {
+		instance := &model2.Instance{LastIdleTime: time.Now().Add(-c.idleFor), UsageCount: c.usageCount, InitDurationInMs: c.initMs}
+		if got := instance.Score(idleDuration, c.maxInitMs); got < c.want-0.01 || got > c.want+0.01 {
+			t.Fatalf("%s: got score %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// 空闲实例数受 MinIdleInstances 限制时，EvictByScore 回收分数最高的实例，默认回收空闲最久的实例
+func TestGCEvictByScore(t *testing.T) {
+	cases := []struct {
+		evictByScore bool
+		// 保留下来的实例的 InitDurationInMs
+		want int64
+	}{
+		{true, 10},
+		{false, 30},
+	}
+	for _, c := range cases {
+		cfg := testConfig()
+		cfg.EvictByScore = c.evictByScore
+		cfg.MinIdleInstances = 1
+		cfg.IdleDurationBeforeGC = 30 * time.Minute
+		platform := newFakePlatform()
+		platform.setInitDurations(50, 10, 30)
+		s := newTestSimple(t, cfg, platform)
+		idleN(t, s, 3)
+		// 空闲队列从队尾到队首依次为 50、10、30，全部已经过期
+		s.idleMu.Lock()
+		age := time.Hour
+		for element := s.idleInstance.Back(); element != nil; element = element.Prev() {
+			element.Value.(*model2.Instance).LastIdleTime = time.Now().Add(-age)
+			age -= time.Minute
+		}
+		s.idleMu.Unlock()
+
+		updated := *cfg
+		updated.GcInterval = 20 * time.Millisecond
+		if err := s.ReloadConfig(&updated); err != nil {
+			t.Fatalf("reload config: %v", err)
+		}
+		waitFor(t, 2*time.Second, func() bool { return platform.liveSlots() == 1 })
+		if got := idleInitDurations(s); len(got) != 1 || got[0] != c.want {
+			t.Fatalf("evict by score %v kept %v, want [%d]", c.evictByScore, got, c.want)
+		}
+	}
+}
+
+// 1000 个空闲实例按 Score 排序的开销
+func BenchmarkSortByInstanceScore(b *testing.B) {
+	s := newTestSimple(b, testConfig(), newFakePlatform())
+	atomic.StoreInt64(&s.maxInitMs, 1000)
+	instances := make([]*model2.Instance, 1000)
+	for i := range instances {
+		instances[i] = &model2.Instance{
+			LastIdleTime:     time.Now().Add(-time.Duration(i) * time.Second),
+			UsageCount:       int64(i % 7),
+			InitDurationInMs: int64(i * 7919 % 1000),
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		shuffled := append([]*model2.Instance(nil), instances...)
+		s.sortByInstanceScore(shuffled)
+	}
+}
+
+// 把空闲实例移出空闲队列，模拟人工重置后等待 PromoteToIdle 的实例
+func demoteIdle(tb testing.TB, s *Simple, instanceId string) {
+	tb.Helper()
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	if !s.removeIdle(instanceId) {
+		tb.Fatalf("instance %s is not idle", instanceId)
+	}
+}
+
+// 预热状态的实例提升后回到空闲队列，已经空闲、忙碌或不存在的实例返回错误
+func TestPromoteToIdle(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	replies := assignAll(t, s, assignRequest("warm"), assignRequest("busy"))
+	idleInOrder(t, s, replies[0])
+	warm := replies[0].Assigment.InstanceId
+	demoteIdle(t, s, warm)
+	if counts := s.InstanceCountByStatus(); counts["warmup"] != 1 || counts["idle"] != 0 || counts["busy"] != 1 {
+		t.Fatalf("unexpected counts before promotion: %v", counts)
+	}
+
+	if err := s.PromoteToIdle(warm); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	if counts := s.InstanceCountByStatus(); counts["warmup"] != 0 || counts["idle"] != 1 {
+		t.Fatalf("unexpected counts after promotion: %v", counts)
+	}
+	if err := s.PromoteToIdle(warm); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("promoting an idle instance got %v, want FailedPrecondition", err)
+	}
+	if err := s.PromoteToIdle(replies[1].Assigment.InstanceId); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("promoting a busy instance got %v, want FailedPrecondition", err)
+	}
+	if err := s.PromoteToIdle("missing"); status.Code(err) != codes.NotFound {
+		t.Fatalf("promoting an unknown instance got %v, want NotFound", err)
+	}
+	if reply := assignAll(t, s, assignRequest("reuse"))[0]; reply.Assigment.InstanceId != warm {
+		t.Fatalf("promoted instance was not reused, got %s", reply.Assigment.InstanceId)
+	}
+}
+
+// 有请求在等待时，提升的实例直接交给等待的请求
+func TestPromoteToIdleServesWaiter(t *testing.T) {
+	// 已有一个实例，等待的请求不会再创建实例
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+	reply := assignAll(t, s, assignRequest("warm"))[0]
+	idleInOrder(t, s, reply)
+	demoteIdle(t, s, reply.Assigment.InstanceId)
+
+	ch := make(chan *pb.AssignReply, 1)
+	go func() {
+		reply, err := s.Assign(context.Background(), assignRequest("waiter"))
+		if err != nil {
+			t.Errorf("assign: %v", err)
+		}
+		ch <- reply
+	}()
+	waitFor(t, time.Second, func() bool {
+		s.longPollingMu.Lock()
+		defer s.longPollingMu.Unlock()
+		return s.longPollingHeap.Len() == 1
+	})
+	if err := s.PromoteToIdle(reply.Assigment.InstanceId); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	select {
+	case got := <-ch:
+		if got == nil || got.Assigment.InstanceId != reply.Assigment.InstanceId {
+			t.Fatalf("waiter got %v, want promoted instance %s", got, reply.Assigment.InstanceId)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("waiter was not served by the promoted instance")
+	}
+	if n := s.Stats().TotalIdleInstance; n != 0 {
+		t.Fatalf("delivered instance should not stay in the idle list, got %d idle", n)
+	}
+}
+
+// PromoteToIdle 与同一实例的多次通知并发执行时，实例只进入空闲队列一次
+func TestPromoteToIdleConcurrentWithNotify(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	reply := assignAll(t, s, assignRequest("warm"))[0]
+	idleInOrder(t, s, reply)
+	s.instancesMu.RLock()
+	instance := s.instances[reply.Assigment.InstanceId]
+	s.instancesMu.RUnlock()
+	for i := 0; i < 100; i++ {
+		demoteIdle(t, s, instance.Id)
+		var wg sync.WaitGroup
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			s.PromoteToIdle(instance.Id)
+		}()
+		for j := 0; j < 2; j++ {
+			go func() {
+				defer wg.Done()
+				s.notifyRequest(instance)
+			}()
+		}
+		wg.Wait()
+		if ids := idleIds(s); len(ids) != 1 {
+			t.Fatalf("round %d: idle list holds %v", i, ids)
+		}
+	}
+}
+
+func TestEstimateTimeToServe(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	setEstimate := func(creating int64, duration time.Duration) {
+		atomic.StoreInt64(&s.creatingNum, creating)
+		atomic.StoreInt64(&s.creatingDuration, int64(duration))
+	}
+	defer setEstimate(0, 0)
+
+	cases := []struct {
+		name     string
+		creating int64
+		queuePos int
+		want     time.Duration
+	}{
+		{"two creating, first", 2, 1, 100 * time.Millisecond},
+		{"two creating, fifth", 2, 5, 100 * time.Millisecond},
+		{"two creating, tenth", 2, 10, 400 * time.Millisecond},
+		// 没有正在创建的实例时每个请求都要等一次创建
+		{"none creating, first", 0, 1, 100 * time.Millisecond},
+		{"none creating, fifth", 0, 5, 500 * time.Millisecond},
+		{"none creating, tenth", 0, 10, time.Second},
+	}
+	for _, c := range cases {
+		setEstimate(c.creating, 100*time.Millisecond)
+		if got := s.EstimateTimeToServe(c.queuePos); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+
+	// 还没有创建成功的实例时用平台调用耗时估算
+	setEstimate(0, 0)
+	s.runtimeStatus.platformCallMu.Lock()
+	s.runtimeStatus.platformCallDuration = 50 * time.Millisecond
+	s.runtimeStatus.platformCallMu.Unlock()
+	if got := s.EstimateTimeToServe(2); got != 100*time.Millisecond {
+		t.Fatalf("fallback estimate got %s, want 100ms", got)
+	}
+}
+
+// 空闲实例能满足的位置不需要等待，其后的位置扣除空闲实例再估算
+func TestEstimateTimeToServeWithIdle(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	replies := assignAll(t, s, assignRequest("a"), assignRequest("b"))
+	idleInOrder(t, s, replies...)
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&s.creatingDuration) != 0 })
+	atomic.StoreInt64(&s.creatingDuration, int64(100*time.Millisecond))
+	atomic.StoreInt64(&s.creatingNum, 2)
+	defer atomic.StoreInt64(&s.creatingNum, 0)
+
+	for _, c := range []struct {
+		queuePos int
+		want     time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{5, 100 * time.Millisecond},
+		{10, 300 * time.Millisecond},
+	} {
+		if got := s.EstimateTimeToServe(c.queuePos); got != c.want {
+			t.Errorf("queuePos %d: got %s, want %s", c.queuePos, got, c.want)
+		}
+	}
+}
+
+// Idle 持有 instancesMu 时启动通知，通知再获取 longPollingMu 和 idleMu
+// 等待队列中始终有请求，每次 Idle 都走到把实例交给等待请求的路径，同时并发读取两把锁保护的状态
+func TestIdleNotifyLockOrder(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 2
+	s := newTestSimple(t, cfg, newFakePlatform())
+
+	const workers, rounds = 8, 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				reply, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("lock-%d-%d", w, i)))
+				if err != nil {
+					errs <- err
+					return
+				}
+				if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.Stats()
+			s.InstanceCountByStatus()
+			s.EstimateTimeToServe(3)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(20 * time.Second):
+		t.Fatalf("Assign and Idle deadlocked")
+	}
+	close(stop)
+	readers.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("assign or idle: %v", err)
+	}
+	if stats := s.Stats(); stats.TotalInstance > 2 {
+		t.Fatalf("unexpected stats after all requests returned %+v", stats)
+	}
+	// Idle 之后异步通知，最后归还的实例稍后才回到空闲队列
+	waitFor(t, time.Second, func() bool {
+		stats := s.Stats()
+		return stats.TotalIdleInstance == stats.TotalInstance
+	})
+}
+
+// 请求要求销毁实例时，平台支持 ResetInstance 就原地重置并放回空闲队列，达到次数上限后销毁
+func TestRecycleOnNeedDestroy(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceMaxRecycleCount = 2
+	platform := newFakeRecyclePlatform()
+	s := newTestSimple(t, cfg, platform)
+
+	reply := assignAll(t, s, assignRequest("r0"))[0]
+	instanceId, slotId := reply.Assigment.InstanceId, reply.Assigment.SlotId
+	for i := 1; i <= cfg.InstanceMaxRecycleCount; i++ {
+		if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+			t.Fatalf("idle %d: %v", i, err)
+		}
+		waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+		if recycled := platform.recycled(); len(recycled) != i || recycled[i-1] != slotId {
+			t.Fatalf("got reset slots %v after %d idles, want %s reset each time", recycled, i, slotId)
+		}
+		s.instancesMu.Lock()
+		instance := s.instances[instanceId]
+		s.instancesMu.Unlock()
+		if instance == nil || instance.RecycleCount != i || atomic.LoadInt32(&instance.UsedSlots) != 0 {
+			t.Fatalf("recycled instance is not idle with count %d: %+v", i, instance)
+		}
+		reply = assignAll(t, s, assignRequest(fmt.Sprintf("r%d", i)))[0]
+		if reply.Assigment.InstanceId != instanceId {
+			t.Fatalf("got instance %s, want the recycled instance %s", reply.Assigment.InstanceId, instanceId)
+		}
+	}
+
+	if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if recycled := platform.recycled(); len(recycled) != cfg.InstanceMaxRecycleCount {
+		t.Fatalf("reset past the limit: %v", recycled)
+	}
+	if atomic.LoadInt64(&platform.destroys) != 1 || platform.liveSlots() != 0 || s.Stats().TotalInstance != 0 {
+		t.Fatalf("instance over the recycle limit should be destroyed, destroys %d", atomic.LoadInt64(&platform.destroys))
+	}
+}
+
+// ResetInstance 失败时退回销毁
+func TestRecycleFailureDestroys(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceMaxRecycleCount = 3
+	platform := newFakeRecyclePlatform()
+	platform.setRecycleErr(errors.New("sandbox is broken"))
+	s := newTestSimple(t, cfg, platform)
+
+	reply := assignAll(t, s, assignRequest("r0"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	if len(platform.recycled()) != 1 {
+		t.Fatalf("reset should be tried first, got %v", platform.recycled())
+	}
+	if atomic.LoadInt64(&platform.destroys) != 1 || platform.liveSlots() != 0 || s.Stats().TotalInstance != 0 || len(idleIds(s)) != 0 {
+		t.Fatalf("failed reset should destroy the instance, destroys %d", atomic.LoadInt64(&platform.destroys))
+	}
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v for the destroyed instance, want NotFound", err)
+	}
+}
+
+// 平台不支持 ResetInstance 或没有配置回收次数时总是销毁
+func TestRecycleDisabled(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform platform_client2.Client
+		count    int
+	}{
+		{"unsupported", newFakePlatform(), 3},
+		{"zero count", newFakeRecyclePlatform(), 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.InstanceMaxRecycleCount = c.count
+			s := newTestSimple(t, cfg, c.platform)
+			reply := assignAll(t, s, assignRequest("r0"))[0]
+			if _, err := s.Idle(context.Background(), idleRequest(reply, true)); err != nil {
+				t.Fatalf("idle: %v", err)
+			}
+			if s.Stats().TotalInstance != 0 || len(idleIds(s)) != 0 {
+				t.Fatalf("instance should be destroyed: %+v", s.Stats())
+			}
+			if recycler, ok := c.platform.(*fakeRecyclePlatform); ok && len(recycler.recycled()) != 0 {
+				t.Fatalf("instance should not be reset: %v", recycler.recycled())
+			}
+		})
+	}
+}
+
+// gc 回收时平台不返回，DestroySLot 在 SlotDeleteTimeout 后放弃，不会一直占用后台 goroutine
+func TestGCDeleteBoundedBySlotDeleteTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.GcInterval = 20 * time.Millisecond
+	cfg.IdleDurationBeforeGC = 20 * time.Millisecond
+	cfg.SlotDeleteTimeout = 50 * time.Millisecond
+	platform := newFakePlatform()
+	platform.destroyDelay = time.Hour
+	s := newTestSimple(t, cfg, platform)
+
+	reply := assignAll(t, s, assignRequest("r0"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroys) == 1 })
+	start := time.Now()
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroyCanceled) == 1 })
+	if elapsed := time.Since(start); elapsed > cfg.SlotDeleteTimeout+time.Second/2 {
+		t.Fatalf("gc deletion returned after %s, want about SlotDeleteTimeout", elapsed)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("background deletion is still running: %v", err)
+	}
+}
+
+// 后台销毁不随触发它的请求结束，Close 超时后取消仍在进行的销毁
+func TestCloseCancelsSlotDeletion(t *testing.T) {
+	cfg := testConfig()
+	cfg.SlotDeleteTimeout = time.Hour
+	cfg.ShutdownTimeout = 100 * time.Millisecond
+	platform := newFakePlatform()
+	platform.destroyDelay = time.Hour
+	s := newSimple(testMeta(), cfg, withPlatformClient(platform), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	reply := assignAll(t, s, assignRequest("r0"))[0]
+	if err := s.ForceEvict(reply.Assigment.InstanceId); err != nil {
+		t.Fatalf("force evict: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroys) == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt64(&platform.destroyReturns) != 0 {
+		t.Fatalf("deletion should still be running")
+	}
+	start := time.Now()
+	if err := s.Close(context.Background()); err == nil {
+		t.Fatalf("close should report the shutdown timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("close took %s, want about ShutdownTimeout", elapsed)
+	}
+	waitFor(t, time.Second, func() bool { return atomic.LoadInt64(&platform.destroyCanceled) == 1 })
+}
+
+// 调用方放弃等待后实例仍然创建完成并进入空闲队列，供之后的请求使用
+func TestCreateOutlivesAssignContext(t *testing.T) {
+	cfg := testConfig()
+	platform := newFakePlatform()
+	platform.createDelay = 100 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Assign(ctx, assignRequest("r0")); err == nil {
+		t.Fatalf("assign should fail when the caller gives up")
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	assignAll(t, s, assignRequest("r1"))
+	if creates := atomic.LoadInt64(&platform.creates); creates != 1 {
+		t.Fatalf("got %d creates, want the abandoned instance reused", creates)
+	}
+}
+
+// 回收数超过 GcChurnThreshold 时间隔减半，最小 1s 且不超过 GcInterval；连续 3 次没有回收时加倍，最大 GcInterval
+func TestNextGcInterval(t *testing.T) {
+	cases := []struct {
+		name       string
+		gcInterval time.Duration
+		threshold  int
+		interval   time.Duration
+		collected  []int
+		want       time.Duration
+	}{
+		{"disabled", time.Minute, 0, time.Minute, []int{100}, time.Minute},
+		{"halve on churn", time.Minute, 2, time.Minute, []int{3}, 30 * time.Second},
+		{"at threshold", time.Minute, 2, time.Minute, []int{2}, time.Minute},
+		{"halve to floor", 4 * time.Second, 1, 4 * time.Second, []int{5, 5, 5}, time.Second},
+		{"floor above gc interval", 500 * time.Millisecond, 1, 500 * time.Millisecond, []int{5}, 500 * time.Millisecond},
+		{"double after idle ticks", time.Minute, 1, 10 * time.Second, []int{0, 0, 0}, 20 * time.Second},
+		{"two idle ticks", time.Minute, 1, 10 * time.Second, []int{0, 0}, 10 * time.Second},
+		{"collection resets idle ticks", time.Minute, 1, 10 * time.Second, []int{0, 0, 1, 0, 0}, 10 * time.Second},
+		{"double up to gc interval", time.Minute, 1, 40 * time.Second, []int{0, 0, 0}, time.Minute},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.GcInterval = c.gcInterval
+			cfg.GcChurnThreshold = c.threshold
+			s := newTestSimple(t, cfg, newFakePlatform())
+			interval, idleTicks := c.interval, 0
+			for _, collected := range c.collected {
+				interval = s.nextGcInterval(interval, collected, &idleTicks)
+			}
+			if interval != c.want {
+				t.Fatalf("got interval %s, want %s", interval, c.want)
+			}
+		})
+	}
+}
+
+// gc 一次回收的实例数超过阈值后 Stats 报告缩短的间隔，重新加载新的 GcInterval 后从新的间隔开始
+func TestGcIntervalShrinksUnderChurn(t *testing.T) {
+	cfg := testConfig()
+	cfg.GcInterval = 2 * time.Second
+	cfg.GcChurnThreshold = 1
+	cfg.IdleDurationBeforeGC = 10 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	if got := s.Stats().GcInterval; got != cfg.GcInterval {
+		t.Fatalf("got gc interval %s, want %s", got, cfg.GcInterval)
+	}
+	idleN(t, s, 3)
+	waitFor(t, 2*cfg.GcInterval, func() bool { return s.Stats().GcInterval == time.Second })
+	if n := s.Stats().TotalInstance; n != 0 {
+		t.Fatalf("gc should collect all idle instances, %d left", n)
+	}
+
+	reloaded := *cfg
+	reloaded.GcInterval = 3 * time.Second
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().GcInterval == reloaded.GcInterval })
+}
+
+// 等待队列达到 MaxQueueDepth 后新的 Assign 立即返回 ResourceExhausted，不入队也不创建实例
+func TestMaxQueueDepthSheds(t *testing.T) {
+	const depth = 3
+	cfg := testConfig()
+	cfg.MaxQueueDepth = depth
+	cfg.ShutdownTimeout = 100 * time.Millisecond
+	platform := newFakePlatform()
+	// 平台一直不返回，直到 Close 取消
+	platform.createDelay = time.Hour
+	s := newTestSimple(t, cfg, platform)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < depth; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Assign(ctx, assignRequest(fmt.Sprintf("waiting-%d", i)))
+		}(i)
+	}
+	waitFor(t, time.Second, func() bool { return queueLen(s) == depth })
+	creates := atomic.LoadInt64(&platform.creates)
+
+	start := time.Now()
+	_, err := s.Assign(context.Background(), assignRequest("shed"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("shed assign took %s, want an immediate rejection", elapsed)
+	}
+	if message := status.Convert(err).Message(); !strings.Contains(message, fmt.Sprintf("queue depth %d", depth)) || !strings.Contains(message, fmt.Sprintf("limit %d", depth)) {
+		t.Fatalf("error should report the depth and the limit: %q", message)
+	}
+	if queueLen(s) != depth || atomic.LoadInt64(&platform.creates) != creates {
+		t.Fatalf("shed assign was queued or created an instance")
+	}
+
+	// 等待的请求离开后重新接受
+	cancel()
+	wg.Wait()
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 0 })
+	retryCtx, retryCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer retryCancel()
+	if _, err := s.Assign(retryCtx, assignRequest("retry")); status.Code(err) == codes.ResourceExhausted {
+		t.Fatalf("assign was shed after the queue drained")
+	}
+}
+
+// MaxQueueDepth 为 0 时不限制等待队列长度
+func TestMaxQueueDepthUnlimited(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxQueueDepth = 0
+	cfg.ShutdownTimeout = 100 * time.Millisecond
+	platform := newFakePlatform()
+	platform.createDelay = time.Hour
+	s := newTestSimple(t, cfg, platform)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.Assign(ctx, assignRequest(fmt.Sprintf("r%d", i))); status.Code(err) == codes.ResourceExhausted {
+				t.Errorf("assign %d was shed without a limit", i)
+			}
+		}(i)
+	}
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 20 })
+	cancel()
+	wg.Wait()
+}
+
+// 开启 HotStandby 后 Assign 用掉最后一个可用实例时在后台补一个备用实例，下一个请求不需要等待创建
+func TestHotStandbyRefill(t *testing.T) {
+	cfg := testConfig()
+	cfg.HotStandby = true
+	platform := newFakePlatform()
+	platform.createDelay = 50 * time.Millisecond
+	s := newTestSimple(t, cfg, platform)
+
+	assignAll(t, s, assignRequest("first"))
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	if creates := atomic.LoadInt64(&platform.creates); creates != 2 {
+		t.Fatalf("got %d creates, want the first instance and one standby", creates)
+	}
+	start := time.Now()
+	assignAll(t, s, assignRequest("second"))
+	if elapsed := time.Since(start); elapsed >= platform.createDelay {
+		t.Fatalf("assign took %s, want the standby to be used", elapsed)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	if creates := atomic.LoadInt64(&platform.creates); creates != 3 {
+		t.Fatalf("got %d creates, want the standby refilled once", creates)
+	}
+}
+
+// 关闭 HotStandby、达到 MaxInstances 或仍有空闲实例时不创建备用实例
+func TestHotStandbySkipped(t *testing.T) {
+	cases := []struct {
+		name    string
+		standby bool
+		max     int
+		idle    int
+	}{
+		{name: "disabled"},
+		{name: "max instances", standby: true, max: 1},
+		{name: "idle left", standby: true, idle: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MaxInstances = c.max
+			platform := newFakePlatform()
+			s := newTestSimple(t, cfg, platform)
+			idleN(t, s, c.idle)
+			enabled := *cfg
+			enabled.HotStandby = c.standby
+			if err := s.ReloadConfig(&enabled); err != nil {
+				t.Fatalf("reload config: %v", err)
+			}
+			before := atomic.LoadInt64(&platform.creates)
+			assignAll(t, s, assignRequest("r"))
+			time.Sleep(50 * time.Millisecond)
+			want := before
+			if c.idle == 0 {
+				want++
+			}
+			if creates := atomic.LoadInt64(&platform.creates); creates != want {
+				t.Fatalf("got %d creates, want %d without a standby", creates, want)
+			}
+		})
+	}
+}
+
+// 实例始终全部忙碌时比较有无备用实例的 Assign 耗时
+func BenchmarkAssignHotStandby(b *testing.B) {
+	for _, standby := range []bool{false, true} {
+		b.Run(fmt.Sprintf("standby=%v", standby), func(b *testing.B) {
+			cfg := testConfig()
+			cfg.HotStandby = standby
+			platform := newFakePlatform()
+			platform.createDelay = time.Millisecond
+			s := newTestSimple(b, cfg, platform)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// 请求不归还，每个 Assign 都用掉最后一个可用实例
+				if _, err := s.Assign(context.Background(), assignRequest(fmt.Sprintf("r%d", i))); err != nil {
+					b.Fatalf("assign: %v", err)
+				}
+				if standby {
+					// 模拟请求间隔，让备用实例有时间创建完成，等待时间不计入耗时
+					b.StopTimer()
+					waitFor(b, time.Second, func() bool { return len(idleIds(s)) == 1 })
+					b.StartTimer()
+				}
+			}
+		})
+	}
+}
+
+// 调用方没有 deadline 时 LongPollingTimeout 到期返回 DeadlineExceeded，并把请求移出等待队列
+func TestLongPollingTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	cfg.LongPollingTimeout = 50 * time.Millisecond
+	s := newTestSimple(t, cfg, newFakePlatform())
+	assignAll(t, s, assignRequest("busy"))
+
+	start := time.Now()
+	_, err := s.Assign(context.Background(), assignRequest("waiting"))
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("got %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed < cfg.LongPollingTimeout || elapsed > time.Second {
+		t.Fatalf("assign returned after %s, want about %s", elapsed, cfg.LongPollingTimeout)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Fatalf("timed out request should leave the queue, got %d waiting", n)
+	}
+}
+
+// 超时的同时实例已投递到 channel 时，取消等待会把实例取出放回空闲队列
+func TestCancelWaiterReturnsDeliveredInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxInstances = 1
+	s := newTestSimple(t, cfg, newFakePlatform())
+	busy := assignAll(t, s, assignRequest("busy"))[0]
+
+	s.longPollingMu.Lock()
+	waiter := s.longPollingHeap.push(make(chan *model2.Instance, 1), time.Time{})
+	s.longPollingMu.Unlock()
+	if _, err := s.Idle(context.Background(), idleRequest(busy, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 0 })
+	if n := len(idleIds(s)); n != 0 {
+		t.Fatalf("instance should be delivered to the waiter, got %d idle", n)
+	}
+
+	s.cancelWaiter(waiter)
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	assignAll(t, s, assignRequest("next"))
+}
+
+// 校验失败的配置不会替换当前配置
+func TestReloadConfigValidates(t *testing.T) {
+	cfg := testConfig()
+	s := newTestSimple(t, cfg, newFakePlatform())
+	cases := map[string]func(c *config.Config){
+		"zero gc interval":        func(c *config.Config) { c.GcInterval = 0 },
+		"negative idle duration":  func(c *config.Config) { c.IdleDurationBeforeGC = -time.Second },
+		"negative max instances":  func(c *config.Config) { c.MaxInstances = -1 },
+		"min idle above max idle": func(c *config.Config) { c.MinIdleInstances, c.MaxIdleInstances = 3, 2 },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			invalid := *cfg
+			mutate(&invalid)
+			invalid.MaxIdleMemoryMb = 1
+			if err := s.ReloadConfig(&invalid); err == nil {
+				t.Fatalf("invalid config should be rejected")
+			}
+			if s.Config().MaxIdleMemoryMb != 0 {
+				t.Fatalf("rejected config replaced the current one")
+			}
+		})
+	}
+	if err := s.ReloadConfig(nil); err == nil {
+		t.Fatalf("nil config should be rejected")
+	}
+}
+
+// 重新加载后 gc 按新的 GcInterval、IdleDurationBeforeGC 和 MinIdleInstances 回收，不需要重新创建 scaler
+func TestReloadConfigAppliesToGc(t *testing.T) {
+	cfg := testConfig()
+	cfg.IdleDurationBeforeGC = time.Hour
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	idleN(t, s, 3)
+
+	reloaded := *cfg
+	reloaded.GcInterval = 10 * time.Millisecond
+	reloaded.IdleDurationBeforeGC = 0
+	reloaded.MinIdleInstances = 2
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	// gc 循环收到通知后重新计时
+	waitFor(t, time.Second, func() bool { return s.Stats().GcInterval == reloaded.GcInterval })
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 2 })
+
+	floor := reloaded
+	floor.MinIdleInstances = 0
+	if err := s.ReloadConfig(&floor); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+}
+
+// 重新加载时同样应用当前 meta key 的 MetaOverrides
+func TestReloadConfigAppliesMetaOverride(t *testing.T) {
+	cfg := testConfig()
+	s := newTestSimple(t, cfg, newFakePlatform())
+	reloaded := *cfg
+	reloaded.MaxInstances = 10
+	reloaded.MetaOverrides = map[string]config.MetaConfig{
+		testMeta().Key: {MaxInstances: 1},
+		"other":        {MaxInstances: 5},
+	}
+	if err := s.ReloadConfig(&reloaded); err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if n := s.Config().MaxInstances; n != 1 {
+		t.Fatalf("got MaxInstances %d, want the override 1", n)
+	}
+	if reloaded.MaxInstances != 10 {
+		t.Fatalf("applying the override modified the caller's config")
+	}
+}
+
+// 按日志消息记录结构化字段名的 slog handler
+type attrRecorder struct {
+	mu   sync.Mutex
+	keys map[string]map[string]bool
+}
+
+func (h *attrRecorder) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *attrRecorder) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := h.keys[record.Message]
+	if keys == nil {
+		keys = make(map[string]bool)
+		h.keys[record.Message] = keys
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		keys[attr.Key] = true
+		return true
+	})
+	return nil
+}
+
+func (h *attrRecorder) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *attrRecorder) WithGroup(string) slog.Handler { return h }
+
+// message 的日志是否带有全部 keys
+func (h *attrRecorder) has(message string, keys ...string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	got, ok := h.keys[message]
+	if !ok {
+		return false
+	}
+	for _, key := range keys {
+		if !got[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// WithLogger 注入的 handler 收到 Assign、Idle 和 gc 回收的结构化日志
+func TestStructuredLogs(t *testing.T) {
+	cfg := testConfig()
+	cfg.GcInterval = 10 * time.Millisecond
+	cfg.IdleDurationBeforeGC = 0
+	logs := &attrRecorder{keys: make(map[string]map[string]bool)}
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform, WithLogger(slog.New(logs)))
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	if _, err := s.Idle(context.Background(), idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+
+	cases := []struct {
+		message string
+		keys    []string
+	}{
+		{"gc loop is started", []string{"metaKey"}},
+		{"assign", []string{"requestId"}},
+		{"assign queued", []string{"requestId", "reason"}},
+		{"idle", []string{"requestId", "instanceId", "duration"}},
+		{"start delete instance", []string{"metaKey", "instanceId", "slotId"}},
+	}
+	for _, c := range cases {
+		if !logs.has(c.message, c.keys...) {
+			t.Errorf("log %q is missing keys %v", c.message, c.keys)
+		}
+	}
+}
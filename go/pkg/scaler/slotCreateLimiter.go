@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"math"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+type slotCreateLimit struct {
+	rps   float64
+	burst int
+}
+
+var (
+	slotCreateLimitersMu sync.Mutex
+	// 进程内所有 scaler 共享 CreateSlot 限流器，SlotCreateRPS 和 SlotCreateBurst 相同的 scaler 共用同一个
+	slotCreateLimiters = make(map[slotCreateLimit]*rate.Limiter)
+)
+
+// 返回 rps 和 burst 对应的共享限流器，rps 小于等于 0 时返回 nil，burst 小于等于 0 时取 ceil(rps)
+func sharedSlotCreateLimiter(rps float64, burst int) *rate.Limiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(rps))
+	}
+	key := slotCreateLimit{rps: rps, burst: burst}
+	slotCreateLimitersMu.Lock()
+	defer slotCreateLimitersMu.Unlock()
+	if limiter, ok := slotCreateLimiters[key]; ok {
+		return limiter
+	}
+	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+	slotCreateLimiters[key] = limiter
+	return limiter
+}
+
+// WithSlotCreateLimiter 使用调用方提供的限流器限制 CreateSlot 调用，多个 scaler 可以传入同一个限流器，nil 表示不限流
+func WithSlotCreateLimiter(limiter *rate.Limiter) Option {
+	return func(s *Simple) {
+		s.slotCreateLimiter = limiter
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"golang.org/x/time/rate"
+)
+
+// 相同的 rps 和 burst 共用同一个限流器，rps 小于等于 0 表示不限流
+func TestSharedSlotCreateLimiter(t *testing.T) {
+	if limiter := sharedSlotCreateLimiter(0, 5); limiter != nil {
+		t.Fatalf("non-positive rps should not limit")
+	}
+	a := sharedSlotCreateLimiter(3.5, 0)
+	if a == nil || a.Burst() != 4 || a.Limit() != rate.Limit(3.5) {
+		t.Fatalf("unexpected limiter %+v", a)
+	}
+	if b := sharedSlotCreateLimiter(3.5, 4); b != a {
+		t.Fatalf("same rps and burst should share the limiter")
+	}
+	if c := sharedSlotCreateLimiter(3.5, 1); c == a {
+		t.Fatalf("different burst should not share the limiter")
+	}
+}
+
+// 共享限流器的多个 scaler 每秒创建的 slot 总数不超过 SlotCreateRPS，超出的创建等待而不是失败
+func TestSlotCreateRateLimit(t *testing.T) {
+	const rps, burst, perScaler = 10, 2, 4
+	cfg := testConfig()
+	cfg.SlotCreateRPS = rps
+	cfg.SlotCreateBurst = burst
+	// 其他测试可能使用同样的配置，这里单独传入限流器
+	limiter := rate.NewLimiter(rps, burst)
+	platforms := []*fakePlatform{newFakePlatform(), newFakePlatform()}
+	var scalers []*Simple
+	for _, platform := range platforms {
+		scalers = append(scalers, newTestSimple(t, cfg, platform, WithSlotCreateLimiter(limiter)))
+	}
+	creates := func() int64 {
+		var total int64
+		for _, platform := range platforms {
+			total += atomic.LoadInt64(&platform.creates)
+		}
+		return total
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, len(scalers)*perScaler)
+	for i, s := range scalers {
+		for j := 0; j < perScaler; j++ {
+			wg.Add(1)
+			go func(s *Simple, requestId string) {
+				defer wg.Done()
+				if _, err := s.Assign(context.Background(), assignRequest(requestId)); err != nil {
+					errs <- err
+				}
+			}(s, fmt.Sprintf("s%d-r%d", i, j))
+		}
+	}
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	for waiting := true; waiting; {
+		select {
+		case <-finished:
+			waiting = false
+		case <-time.After(10 * time.Millisecond):
+		}
+		// 多留一个令牌的余量，避免采样时刻的误差
+		if allowed := burst + int64(time.Since(start).Seconds()*rps) + 1; creates() > allowed {
+			t.Fatalf("created %d slots after %s, want at most %d", creates(), time.Since(start), allowed)
+		}
+	}
+	close(errs)
+	for err := range errs {
+		t.Fatalf("rate limited assign failed: %v", err)
+	}
+	total := int64(len(scalers) * perScaler)
+	if creates() != total {
+		t.Fatalf("got %d creates, want %d", creates(), total)
+	}
+	if elapsed, min := time.Since(start), time.Duration(total-burst)*time.Second/rps; elapsed < min-20*time.Millisecond {
+		t.Fatalf("%d creates took %s, want at least %s", total, elapsed, min)
+	}
+}
+
+// 没有配置 SlotCreateRPS 时不限流
+func TestSlotCreateUnlimited(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	if s.slotCreateLimiter != nil {
+		t.Fatalf("limiter should be nil without SlotCreateRPS")
+	}
+	start := time.Now()
+	var requests []*pb.AssignRequest
+	for i := 0; i < 20; i++ {
+		requests = append(requests, assignRequest(fmt.Sprintf("r%d", i)))
+	}
+	assignAll(t, s, requests...)
+	if elapsed := time.Since(start); atomic.LoadInt64(&platform.creates) != 20 || elapsed > time.Second {
+		t.Fatalf("got %d creates in %s", atomic.LoadInt64(&platform.creates), elapsed)
+	}
+}
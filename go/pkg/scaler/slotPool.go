@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	"github.com/google/uuid"
+)
+
+// SlotPool 预先创建、尚未初始化的 slot，内存在创建时已经预留
+// 用于 CreateSlot 较慢而 Init 较快的平台，slot 的规格固定为应用 meta 的内存
+type SlotPool struct {
+	slots chan *model2.Slot
+	// 取走 slot 后通知补齐
+	refillCh chan struct{}
+}
+
+func newSlotPool(size int) *SlotPool {
+	return &SlotPool{
+		slots:    make(chan *model2.Slot, size),
+		refillCh: make(chan struct{}, 1),
+	}
+}
+
+// 不阻塞地取出一个 slot，池为空或 p 为 nil 时返回 nil
+func (p *SlotPool) get() *model2.Slot {
+	if p == nil {
+		return nil
+	}
+	select {
+	case slot := <-p.slots:
+		select {
+		case p.refillCh <- struct{}{}:
+		default:
+		}
+		return slot
+	default:
+		return nil
+	}
+}
+
+// Len 池中的 slot 数
+func (p *SlotPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.slots)
+}
+
+// 创建后立即补齐，之后在 slot 被取走或每个 GcInterval 补齐一次，直到 scaler 关闭
+func (s *Simple) slotPoolLoop() {
+	ticker := time.NewTicker(s.cfg().GcInterval)
+	defer ticker.Stop()
+	for {
+		s.refillSlotPool()
+		select {
+		case <-s.done:
+			return
+		case <-s.slotPool.refillCh:
+		case <-ticker.C:
+		}
+	}
+}
+
+// 依次创建 slot 直到池满，CreateSlot 经过与直接创建相同的限流；失败时等下一次补齐
+func (s *Simple) refillSlotPool() {
+	for len(s.slotPool.slots) < cap(s.slotPool.slots) && !s.isShutdown() && !s.IsDraining() {
+		if err := s.reserveMemory(s.metaData.MemoryInMb); err != nil {
+			s.logger.Warn("preallocate slot rejected", "metaKey", s.metaData.Key, "error", err)
+			return
+		}
+		resourceConfig := newResourceConfig(&s.metaData.Meta)
+		slot, err := s.createSlot(context.Background(), uuid.NewString(), &resourceConfig)
+		if err != nil {
+			s.releaseMemory(s.metaData.MemoryInMb)
+			s.logger.Error("preallocate slot failed", "metaKey", s.metaData.Key, "error", err)
+			return
+		}
+		s.audit(AuditActionCreate, slot.Id, "", "preallocated")
+		s.slotPool.slots <- slot
+	}
+}
+
+// 销毁池中剩余的 slot
+func (s *Simple) destroyPreallocatedSlots(ctx context.Context) {
+	for slot := s.slotPool.get(); slot != nil; slot = s.slotPool.get() {
+		if err := s.platformClient.DestroySLot(ctx, uuid.NewString(), slot.Id, "scaler closed"); err != nil {
+			s.logger.Error("delete preallocated slot failed", "metaKey", s.metaData.Key, "slotId", slot.Id, "error", err)
+		}
+		s.audit(AuditActionDelete, slot.Id, "", "scaler closed")
+		s.releaseMemory(s.metaData.MemoryInMb)
+	}
+}
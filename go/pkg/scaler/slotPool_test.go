@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newSlotPoolSimple(t *testing.T, size int) (*Simple, *fakePlatform) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.PreallocatedSlots = size
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	waitFor(t, time.Second, func() bool { return s.slotPool.Len() == size })
+	return s, platform
+}
+
+func TestSlotPoolPreallocates(t *testing.T) {
+	s, platform := newSlotPoolSimple(t, 2)
+	if n := atomic.LoadInt64(&platform.creates); n != 2 {
+		t.Fatalf("creates %d, want 2", n)
+	}
+	if n := s.Stats().TotalInstance; n != 0 {
+		t.Fatalf("preallocated slots should not be instances, total=%d", n)
+	}
+}
+
+// Assign 使用池中的 slot，不直接调用 CreateSlot，取走后池会被补齐
+func TestAssignUsesPooledSlot(t *testing.T) {
+	s, platform := newSlotPoolSimple(t, 2)
+	// fakePlatform 按顺序编号，池中是前两个 slot
+	pooled := map[string]bool{"slot-1": true, "slot-2": true}
+
+	reply, err := s.Assign(context.Background(), assignRequest("r1"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	s.instancesMu.RLock()
+	slotId := s.instances[reply.Assigment.InstanceId].Slot.Id
+	s.instancesMu.RUnlock()
+	if !pooled[slotId] {
+		t.Fatalf("instance uses slot %s, want a pooled slot", slotId)
+	}
+	waitFor(t, time.Second, func() bool { return s.slotPool.Len() == 2 })
+	if n := atomic.LoadInt64(&platform.creates); n != 3 {
+		t.Fatalf("creates %d, want 3 after one refill", n)
+	}
+}
+
+// 内存与应用 meta 不同的请求不使用池中的 slot
+func TestAssignSkipsPoolForDifferentMemory(t *testing.T) {
+	s, platform := newSlotPoolSimple(t, 2)
+	request := assignRequest("r1")
+	request.MetaData.MemoryInMb = 512
+	if _, err := s.Assign(context.Background(), request); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if n := s.slotPool.Len(); n != 2 {
+		t.Fatalf("pool len %d, want 2", n)
+	}
+	if n := atomic.LoadInt64(&platform.creates); n != 3 {
+		t.Fatalf("creates %d, want 3", n)
+	}
+}
+
+func TestCloseDestroysPooledSlots(t *testing.T) {
+	s, platform := newSlotPoolSimple(t, 2)
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if n := platform.liveSlots(); n != 0 {
+		t.Fatalf("%d pooled slots left after close", n)
+	}
+	if n := s.slotPool.Len(); n != 0 {
+		t.Fatalf("pool len %d after close, want 0", n)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"github.com/google/uuid"
+)
+
+// Snapshot scaler 的状态快照，用于重启后恢复实例
+type Snapshot struct {
+	MetaKey       string
+	Instances     []InstanceSnapshot
+	RuntimeStatus *pb.RuntimeStatusProto
+}
+
+// InstanceSnapshot 恢复实例需要的 slot 信息
+type InstanceSnapshot struct {
+	InstanceId         string
+	SlotId             string
+	MemoryInMb         uint64
+	CreateTime         uint64
+	CreateDurationInMs uint64
+	NetworkTier        string
+	LastIdleTime       time.Time
+	Tags               map[string]string
+}
+
+// TakeSnapshot 导出当前所有实例的 slot 信息和运行时统计
+func (s *Simple) TakeSnapshot() Snapshot {
+	snapshot := Snapshot{
+		MetaKey:       s.metaData.Key,
+		RuntimeStatus: s.runtimeStatus.SnapshotToProto(),
+	}
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	for _, instance := range s.instances {
+		slot := instance.Slot
+		snapshot.Instances = append(snapshot.Instances, InstanceSnapshot{
+			InstanceId:         instance.Id,
+			SlotId:             slot.Id,
+			MemoryInMb:         slot.GetResourceConfig().GetMemoryInMegabytes(),
+			CreateTime:         slot.CreateTime,
+			CreateDurationInMs: slot.CreateDurationInMs,
+			NetworkTier:        slot.NetworkTier,
+			LastIdleTime:       instance.LastIdleTime,
+			Tags:               instance.Tags,
+		})
+	}
+	return snapshot
+}
+
+// NewFromSnapshot 创建 scaler 并在快照中的 slot 上并发重新初始化实例，初始化失败的 slot 会被跳过
+func NewFromSnapshot(metaData *model2.Meta, config *config.Config, snapshot Snapshot, opts ...Option) (Scaler, error) {
+	if snapshot.MetaKey != metaData.Key {
+		return nil, fmt.Errorf("snapshot of app %s can not restore app %s", snapshot.MetaKey, metaData.Key)
+	}
+	s := newSimple(metaData, config, opts...)
+	if snapshot.RuntimeStatus != nil {
+		s.runtimeStatus.LoadFromProto(snapshot.RuntimeStatus)
+	}
+	var restored int64
+	err := waitAll(s.ctx, len(snapshot.Instances), func(i int) error {
+		entry := snapshot.Instances[i]
+		if err := s.reserveMemory(metaData.MemoryInMb); err != nil {
+			s.logger.Warn("restore slot rejected", "metaKey", metaData.Key, "slotId", entry.SlotId, "error", err)
+			return nil
+		}
+		slot := &model2.Slot{
+			Slot: pb.Slot{
+				Id:                 entry.SlotId,
+				ResourceConfig:     &pb.ResourceConfig{MemoryInMegabytes: entry.MemoryInMb},
+				CreateTime:         entry.CreateTime,
+				CreateDurationInMs: entry.CreateDurationInMs,
+				NetworkTier:        entry.NetworkTier,
+			},
+		}
+		// initInstance 失败时会释放预留的内存
+		if err := s.initInstance(s.ctx, &metaData.Meta, uuid.NewString(), uuid.NewString(), slot, entry.Tags, time.Now()); err != nil {
+			s.logger.Error("restore slot failed", "metaKey", metaData.Key, "slotId", entry.SlotId, "error", err)
+			return nil
+		}
+		atomic.AddInt64(&restored, 1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("restore instances from snapshot", "metaKey", metaData.Key, "restored", restored, "total", len(snapshot.Instances))
+	return s, nil
+}
+
+// 应用 metaKey 的检查点文件路径
+func checkpointFile(dir, metaKey string) string {
+	return filepath.Join(dir, metaKey+".json")
+}
+
+// Checkpoint 把 TakeSnapshot 的结果以 JSON 写入 dir 下以 meta key 命名的文件，先写临时文件再重命名，避免写到一半的文件被读取
+func (s *Simple) Checkpoint(dir string) error {
+	data, err := json.Marshal(s.TakeSnapshot())
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, s.metaData.Key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	if err := os.Rename(file.Name(), checkpointFile(dir, s.metaData.Key)); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+// RestoreFromCheckpoint 读取 Checkpoint 写入的文件，把其中的实例直接作为空闲实例加入 scaler，不重新初始化
+// 空闲时间已超过 IdleDurationBeforeGC 的实例和超出内存预算的实例会被销毁，已经存在的实例会被跳过
+// 读取后删除检查点文件，同一个 meta key 之后创建的 scaler 不会再次接管这些 slot
+func (s *Simple) RestoreFromCheckpoint(dir string) error {
+	path := checkpointFile(dir, s.metaData.Key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	if snapshot.MetaKey != s.metaData.Key {
+		return fmt.Errorf("checkpoint of app %s can not restore app %s", snapshot.MetaKey, s.metaData.Key)
+	}
+	if snapshot.RuntimeStatus != nil {
+		s.runtimeStatus.LoadFromProto(snapshot.RuntimeStatus)
+	}
+	if err := os.Remove(path); err != nil {
+		s.logger.Warn("remove checkpoint failed", "metaKey", s.metaData.Key, "path", path, "error", err)
+	}
+	var restored []*model2.Instance
+	// 不恢复的 slot 及原因
+	var discarded []InstanceSnapshot
+	var reasons []string
+	s.instancesMu.Lock()
+	for _, entry := range snapshot.Instances {
+		if s.instances[entry.InstanceId] != nil {
+			continue
+		}
+		if time.Since(entry.LastIdleTime) > s.cfg().IdleDurationBeforeGC {
+			discarded, reasons = append(discarded, entry), append(reasons, EvictReasonGC)
+			continue
+		}
+		if err := s.reserveMemory(s.metaData.MemoryInMb); err != nil {
+			s.logger.Warn("restore instance rejected", "metaKey", s.metaData.Key, "instanceId", entry.InstanceId, "error", err)
+			discarded, reasons = append(discarded, entry), append(reasons, "restore rejected")
+			continue
+		}
+		instance := &model2.Instance{}
+		instance.Id = entry.InstanceId
+		instance.Slot = &model2.Slot{
+			Slot: pb.Slot{
+				Id:                 entry.SlotId,
+				ResourceConfig:     &pb.ResourceConfig{MemoryInMegabytes: entry.MemoryInMb},
+				CreateTime:         entry.CreateTime,
+				CreateDurationInMs: entry.CreateDurationInMs,
+				NetworkTier:        entry.NetworkTier,
+			},
+		}
+		instance.Meta = s.InstanceMeta()
+		instance.NetworkTier = entry.NetworkTier
+		instance.Tags = entry.Tags
+		instance.Weight = instanceWeight(entry.MemoryInMb)
+		instance.LastIdleTime = entry.LastIdleTime
+		instance.InstanceCapacity = s.cfg().InstanceCapacity
+		if s.cfg().InstanceMaxRecycleCount > 0 {
+			instance.AllowRecycle = true
+			instance.MaxRecycleCount = s.cfg().InstanceMaxRecycleCount
+		}
+		s.instances[instance.Id] = instance
+		restored = append(restored, instance)
+	}
+	s.instancesMu.Unlock()
+	s.logger.Info("restore instances from checkpoint", "metaKey", s.metaData.Key, "restored", len(restored), "discarded", len(discarded), "total", len(snapshot.Instances))
+	for i, entry := range discarded {
+		s.destroyCheckpointSlot(entry, reasons[i])
+	}
+	if len(restored) > 0 {
+		s.notifyRequests(restored)
+	}
+	return nil
+}
+
+// 销毁检查点中不恢复的 slot，这些 slot 没有预留内存
+func (s *Simple) destroyCheckpointSlot(entry InstanceSnapshot, reason string) {
+	ctx, cancel := s.phaseContext(s.cfg().SlotDeleteTimeout)
+	defer cancel()
+	if err := s.platformClient.DestroySLot(ctx, uuid.NewString(), entry.SlotId, reason); err != nil {
+		s.logger.Error("delete checkpoint slot failed", "metaKey", s.metaData.Key, "instanceId", entry.InstanceId, "slotId", entry.SlotId, "error", err)
+	}
+	s.audit(AuditActionDelete, entry.SlotId, entry.InstanceId, reason)
+}
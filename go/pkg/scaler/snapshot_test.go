@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	platform_client2 "github.com/AliyunContainerService/scaler/go/pkg/platform_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failSlotPlatform 在指定 slot 上 Init 失败
+type failSlotPlatform struct {
+	*fakePlatform
+	failSlot string
+}
+
+func (p *failSlotPlatform) Init(ctx context.Context, requestId, instanceId string, slot *model2.Slot, meta *model2.Meta) (*model2.Instance, error) {
+	if slot.Id == p.failSlot {
+		return nil, status.Errorf(codes.Internal, "slot %s is broken", slot.Id)
+	}
+	return p.fakePlatform.Init(ctx, requestId, instanceId, slot, meta)
+}
+
+// 写入检查点后读回快照
+func checkpointSnapshot(t *testing.T, s *Simple) Snapshot {
+	t.Helper()
+	dir := t.TempDir()
+	if err := s.Checkpoint(dir); err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+	data, err := os.ReadFile(checkpointFile(dir, s.metaData.Key))
+	if err != nil {
+		t.Fatalf("read checkpoint: %v", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("unmarshal checkpoint: %v", err)
+	}
+	return snapshot
+}
+
+func restoreSnapshot(t *testing.T, snapshot Snapshot, platform platform_client2.Client) *Simple {
+	t.Helper()
+	restored, err := NewFromSnapshot(testMeta(), testConfig(), snapshot,
+		withPlatformClient(platform), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatalf("new from snapshot: %v", err)
+	}
+	t.Cleanup(func() { restored.Close(context.Background()) })
+	return restored.(*Simple)
+}
+
+// 按 slot id 排序的实例 slot
+func slotIds(s *Simple) []string {
+	s.instancesMu.RLock()
+	defer s.instancesMu.RUnlock()
+	var ids []string
+	for _, instance := range s.instances {
+		ids = append(ids, instance.Slot.Id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// 经过 Checkpoint 和 NewFromSnapshot 后在原来的 slot 上重新初始化全部实例，并恢复运行时统计
+func TestNewFromSnapshotRoundTrip(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idleN(t, s, 3)
+	snapshot := checkpointSnapshot(t, s)
+	if len(snapshot.Instances) != 3 {
+		t.Fatalf("snapshot holds %d instances, want 3", len(snapshot.Instances))
+	}
+
+	creates := atomic.LoadInt64(&platform.creates)
+	restored := restoreSnapshot(t, snapshot, platform)
+	waitFor(t, time.Second, func() bool { return restored.Stats().TotalIdleInstance == 3 })
+	if got, want := fmt.Sprint(slotIds(restored)), fmt.Sprint(slotIds(s)); got != want {
+		t.Fatalf("restored slots %s, want %s", got, want)
+	}
+	if atomic.LoadInt64(&platform.creates) != creates {
+		t.Fatalf("restore should reuse slots instead of creating new ones")
+	}
+	if got, want := restored.runtimeStatus.SnapshotToProto().ExecutionCount, snapshot.RuntimeStatus.ExecutionCount; got != want {
+		t.Fatalf("restored execution count %d, want %d", got, want)
+	}
+}
+
+// 重新初始化失败的 slot 被跳过，其余实例正常恢复
+func TestNewFromSnapshotSkipsFailedSlot(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	idleN(t, s, 3)
+	snapshot := checkpointSnapshot(t, s)
+
+	broken := snapshot.Instances[0].SlotId
+	restored := restoreSnapshot(t, snapshot, &failSlotPlatform{fakePlatform: platform, failSlot: broken})
+	waitFor(t, time.Second, func() bool { return restored.Stats().TotalIdleInstance == 2 })
+	for _, id := range slotIds(restored) {
+		if id == broken {
+			t.Fatalf("failed slot %s was restored", id)
+		}
+	}
+}
+
+func TestNewFromSnapshotMetaKeyMismatch(t *testing.T) {
+	snapshot := Snapshot{MetaKey: "other"}
+	if _, err := NewFromSnapshot(testMeta(), testConfig(), snapshot, withPlatformClient(newFakePlatform())); err == nil {
+		t.Fatalf("snapshot of another app should be rejected")
+	}
+}
+
+// 配置了 CheckpointDir 时 Close 写入检查点，之后创建的 scaler 直接接管其中的实例，不重新创建或初始化
+// 检查点文件读取后删除，再创建的 scaler 不会再次接管
+func TestCheckpointRestoreOnNew(t *testing.T) {
+	cfg := testConfig()
+	cfg.CheckpointDir = t.TempDir()
+	platform := newFakePlatform()
+	s := newSimple(testMeta(), cfg, withPlatformClient(platform), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	replies := assignAll(t, s, assignRequest("r0"), assignRequest("r1"))
+	idleInOrder(t, s, replies...)
+	want := slotIds(s)
+	s.Close(context.Background())
+
+	entries, err := os.ReadDir(cfg.CheckpointDir)
+	if err != nil {
+		t.Fatalf("read checkpoint dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != testMeta().Key+".json" {
+		t.Fatalf("checkpoint should be the only file left, got %v", entries)
+	}
+
+	creates, inits := atomic.LoadInt64(&platform.creates), atomic.LoadInt64(&platform.inits)
+	restored := newTestSimple(t, cfg, platform)
+	if got := slotIds(restored); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("restored slots %v, want %v", got, want)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(restored)) == len(want) })
+	assignAll(t, restored, assignRequest("r2"), assignRequest("r3"))
+	if atomic.LoadInt64(&platform.creates) != creates || atomic.LoadInt64(&platform.inits) != inits {
+		t.Fatalf("restored instances should serve without creating, creates %d inits %d", atomic.LoadInt64(&platform.creates)-creates, atomic.LoadInt64(&platform.inits)-inits)
+	}
+	if _, err := os.Stat(checkpointFile(cfg.CheckpointDir, testMeta().Key)); !os.IsNotExist(err) {
+		t.Fatalf("checkpoint should be removed after restore, stat: %v", err)
+	}
+
+	other := newTestSimple(t, cfg, platform)
+	if n := other.Stats().TotalInstance; n != 0 {
+		t.Fatalf("second scaler adopted %d instances from a consumed checkpoint", n)
+	}
+}
+
+// 空闲时间超过 IdleDurationBeforeGC 的实例不恢复，其 slot 被销毁；已经存在的实例被跳过
+func TestRestoreFromCheckpointDiscardsStale(t *testing.T) {
+	cfg := testConfig()
+	cfg.IdleDurationBeforeGC = time.Minute
+	platform := newFakePlatform()
+	s := newTestSimple(t, cfg, platform)
+	existing := assignAll(t, s, assignRequest("r0"))[0]
+
+	dir := t.TempDir()
+	stale, fresh := "stale-slot", "fresh-slot"
+	platform.mu.Lock()
+	platform.slots[stale], platform.slots[fresh] = true, true
+	platform.mu.Unlock()
+	snapshot := Snapshot{
+		MetaKey: s.metaData.Key,
+		Instances: []InstanceSnapshot{
+			{InstanceId: "stale", SlotId: stale, LastIdleTime: time.Now().Add(-time.Hour)},
+			{InstanceId: "fresh", SlotId: fresh, LastIdleTime: time.Now()},
+			{InstanceId: existing.Assigment.InstanceId, SlotId: existing.Assigment.SlotId, LastIdleTime: time.Now()},
+		},
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("marshal snapshot: %v", err)
+	}
+	if err := os.WriteFile(checkpointFile(dir, s.metaData.Key), data, 0o644); err != nil {
+		t.Fatalf("write checkpoint: %v", err)
+	}
+	if err := s.RestoreFromCheckpoint(dir); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := s.Stats().TotalInstance; got != 2 {
+		t.Fatalf("got %d instances, want the existing and the fresh one", got)
+	}
+	waitFor(t, time.Second, func() bool { return fmt.Sprint(idleIds(s)) == "[fresh]" })
+	platform.mu.Lock()
+	staleAlive, freshAlive := platform.slots[stale], platform.slots[fresh]
+	platform.mu.Unlock()
+	if staleAlive || !freshAlive {
+		t.Fatalf("stale slot alive %v, fresh slot alive %v", staleAlive, freshAlive)
+	}
+	if err := s.RestoreFromCheckpoint(dir); !os.IsNotExist(err) {
+		t.Fatalf("got %v restoring a consumed checkpoint, want not exist", err)
+	}
+}
+
+// 检查点属于其他应用时不恢复
+func TestRestoreFromCheckpointMetaKeyMismatch(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	dir := t.TempDir()
+	data, _ := json.Marshal(Snapshot{MetaKey: "other"})
+	if err := os.WriteFile(checkpointFile(dir, s.metaData.Key), data, 0o644); err != nil {
+		t.Fatalf("write checkpoint: %v", err)
+	}
+	if err := s.RestoreFromCheckpoint(dir); err == nil {
+		t.Fatalf("checkpoint of another app should be rejected")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Supervisor 定期检查 CheckLive，返回 false 时用注册表中的同一实现重新创建 scaler 并替换，对调用方透明
+// 替换期间新的调用等待新 scaler 创建完成；旧 scaler 进入下线，已分配的实例仍可以通过 Idle 归还，
+// 请求全部归还或超过 ShutdownTimeout 后销毁旧 scaler 的全部实例并关闭
+type Supervisor struct {
+	name     string
+	metaData *model2.Meta
+	config   *config.Config
+	opts     []Option
+	logger   *slog.Logger
+
+	mu    sync.RWMutex
+	inner Scaler
+	// 已被替换、正在下线的 scaler
+	retiring []Scaler
+
+	restarts int64
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewSupervisor 用注册表中 name 对应的实现创建 scaler，并每隔 checkInterval 检查一次 CheckLive
+func NewSupervisor(name string, metaData *model2.Meta, config *config.Config, checkInterval time.Duration, opts ...Option) (*Supervisor, error) {
+	inner, err := DefaultRegistry.NewScaler(name, metaData, config, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Supervisor{
+		name:     name,
+		metaData: metaData,
+		config:   config,
+		opts:     opts,
+		logger:   slog.Default(),
+		inner:    inner,
+		done:     make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.superviseLoop(checkInterval)
+	}()
+	return s, nil
+}
+
+func (s *Supervisor) superviseLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			// 调用方主动下线后不再替换
+			if inner := s.current(); !inner.IsDraining() && !inner.CheckLive() {
+				s.restart()
+			}
+		}
+	}
+}
+
+// 持有写锁创建新的 scaler，期间新的调用等待；创建失败时保留旧的 scaler，下次检查再重试
+// Close 之后不再替换，Close 在写锁内关闭 done，保证 wg.Wait 开始后不会再启动 retire
+func (s *Supervisor) restart() {
+	s.mu.Lock()
+	select {
+	case <-s.done:
+		s.mu.Unlock()
+		return
+	default:
+	}
+	old := s.inner
+	inner, err := DefaultRegistry.NewScaler(s.name, s.metaData, s.config, s.opts...)
+	if err != nil {
+		s.mu.Unlock()
+		s.logger.Error("restart scaler failed", "metaKey", s.metaData.Key, "error", err)
+		return
+	}
+	s.inner = inner
+	s.retiring = append(s.retiring, old)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.retire(old)
+	}()
+	s.mu.Unlock()
+	restarts := atomic.AddInt64(&s.restarts, 1)
+	s.logger.Warn("scaler is not live, restarted", "metaKey", s.metaData.Key, "count", restarts)
+}
+
+// 可以在关闭时销毁全部实例的 scaler，Simple 和 Sharded 都实现了该接口
+type retirer interface {
+	Retire(ctx context.Context) error
+}
+
+// 等待旧 scaler 上已分配的请求归还，超过 ShutdownTimeout 或 Supervisor 关闭时不再等待，之后销毁全部实例并关闭
+// 旧 scaler 不支持 Retire 时下线并等待 DrainComplete 后关闭
+func (s *Supervisor) retire(old Scaler) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var err error
+	if r, ok := old.(retirer); ok {
+		err = r.Retire(ctx)
+	} else {
+		// 其他实现只能关闭，无法保证实例被销毁
+		old.Drain()
+		select {
+		case <-old.DrainComplete():
+		case <-ctx.Done():
+		}
+		err = old.Close(context.Background())
+	}
+	if err != nil {
+		s.logger.Error("close replaced scaler failed", "metaKey", s.metaData.Key, "error", err)
+	}
+	s.mu.Lock()
+	for i, scaler := range s.retiring {
+		if scaler == old {
+			s.retiring = append(s.retiring[:i], s.retiring[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+}
+
+func (s *Supervisor) current() Scaler {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inner
+}
+
+// Restarts 累计替换 scaler 的次数
+func (s *Supervisor) Restarts() int64 {
+	return atomic.LoadInt64(&s.restarts)
+}
+
+func (s *Supervisor) Assign(ctx context.Context, request *pb.AssignRequest) (*pb.AssignReply, error) {
+	return s.current().Assign(ctx, request)
+}
+
+func (s *Supervisor) BatchAssign(ctx context.Context, request *pb.BatchAssignRequest) (*pb.BatchAssignReply, error) {
+	return s.current().BatchAssign(ctx, request)
+}
+
+// Idle 先交给当前的 scaler，找不到实例时再依次交给正在下线的 scaler
+func (s *Supervisor) Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
+	s.mu.RLock()
+	inner := s.inner
+	retiring := append([]Scaler(nil), s.retiring...)
+	s.mu.RUnlock()
+	reply, err := inner.Idle(ctx, request)
+	for _, old := range retiring {
+		if status.Code(err) != codes.NotFound {
+			break
+		}
+		reply, err = old.Idle(ctx, request)
+	}
+	return reply, err
+}
+
+func (s *Supervisor) Stats() Stats {
+	return s.current().Stats()
+}
+
+func (s *Supervisor) Clear(rate float64) {
+	s.current().Clear(rate)
+}
+
+func (s *Supervisor) CheckLive() bool {
+	return s.current().CheckLive()
+}
+
+func (s *Supervisor) GetScalingMetrics() ScalingMetrics {
+	return s.current().GetScalingMetrics()
+}
+
+// Close 停止检查，等待仍在下线的 scaler 销毁实例后关闭当前的 scaler
+func (s *Supervisor) Close(ctx context.Context) error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		close(s.done)
+		s.mu.Unlock()
+	})
+	s.wg.Wait()
+	return s.current().Close(ctx)
+}
+
+func (s *Supervisor) InstanceMeta() *model2.Meta {
+	return s.current().InstanceMeta()
+}
+
+func (s *Supervisor) Drain() {
+	s.current().Drain()
+}
+
+func (s *Supervisor) IsDraining() bool {
+	return s.current().IsDraining()
+}
+
+func (s *Supervisor) DrainComplete() <-chan struct{} {
+	return s.current().DrainComplete()
+}
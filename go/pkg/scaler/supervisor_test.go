@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+const flakyImplementation = "flaky-test"
+
+func init() {
+	DefaultRegistry.Register(flakyImplementation, func(metaData *model2.Meta, config *config.Config, opts ...Option) Scaler {
+		return &flakyScaler{Simple: newSimple(metaData, config, opts...), live: 1}
+	})
+}
+
+// flakyScaler 由测试控制 CheckLive 结果的 Simple
+type flakyScaler struct {
+	*Simple
+	live int32
+}
+
+func (s *flakyScaler) CheckLive() bool {
+	return atomic.LoadInt32(&s.live) == 1
+}
+
+func newTestSupervisor(t *testing.T, platform *fakePlatform) *Supervisor {
+	t.Helper()
+	supervisor, err := NewSupervisor(flakyImplementation, testMeta(), testConfig(), 10*time.Millisecond,
+		withPlatformClient(platform), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatalf("new supervisor: %v", err)
+	}
+	t.Cleanup(func() {
+		supervisor.Close(context.Background())
+	})
+	return supervisor
+}
+
+// CheckLive 失败后替换 scaler，新的调用交给新的 scaler，旧 scaler 在请求归还后销毁全部实例
+func TestSupervisorRestartsDeadScaler(t *testing.T) {
+	platform := newFakePlatform()
+	supervisor := newTestSupervisor(t, platform)
+	ctx := context.Background()
+
+	old := supervisor.current().(*flakyScaler)
+	reply, err := supervisor.Assign(ctx, assignRequest("r1"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	atomic.StoreInt32(&old.live, 0)
+	waitFor(t, time.Second, func() bool { return supervisor.Restarts() == 1 })
+	inner := supervisor.current()
+	if inner == Scaler(old) {
+		t.Fatalf("dead scaler is not replaced")
+	}
+	// 旧 scaler 在后台 goroutine 中开始下线
+	waitFor(t, time.Second, old.IsDraining)
+
+	// 新 scaler 找不到实例，Idle 交给正在下线的旧 scaler
+	if _, err := supervisor.Idle(ctx, idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle on retiring scaler: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return platform.liveSlots() == 0 })
+	waitFor(t, time.Second, func() bool {
+		supervisor.mu.RLock()
+		defer supervisor.mu.RUnlock()
+		return len(supervisor.retiring) == 0
+	})
+
+	if _, err := supervisor.Assign(ctx, assignRequest("r2")); err != nil {
+		t.Fatalf("assign after restart: %v", err)
+	}
+	if n := inner.Stats().TotalInstance; n != 1 {
+		t.Fatalf("new scaler has %d instances, want 1", n)
+	}
+	if n := supervisor.Restarts(); n != 1 {
+		t.Fatalf("restarts %d, want 1", n)
+	}
+}
+
+// Retire 销毁空闲实例，不等待 gc 过期
+func TestRetireDestroysIdleInstances(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	ctx := context.Background()
+	reply, err := s.Assign(ctx, assignRequest("r1"))
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if _, err := s.Idle(ctx, idleRequest(reply, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return s.Stats().TotalIdleInstance == 1 })
+
+	retireCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := s.Retire(retireCtx); err != nil {
+		t.Fatalf("retire: %v", err)
+	}
+	if n := platform.liveSlots(); n != 0 {
+		t.Fatalf("%d slots left after retire", n)
+	}
+	if n := s.Stats().TotalInstance; n != 0 {
+		t.Fatalf("%d instances left after retire", n)
+	}
+}
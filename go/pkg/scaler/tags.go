@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// 实例的标签是否包含 required 中的所有标签，required 为空时总是满足
+func matchesTags(instance *model2.Instance, required map[string]string) bool {
+	for key, want := range required {
+		if got, ok := instance.Tags[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// 按空闲队列的分配顺序挑选第一个满足 required 的实例，其中满足 hint 的优先，没有满足标签的实例时返回 nil，调用方需持有 idleMu
+func (s *Simple) selectIdleByTags(required map[string]string, hint *AssignHint) *list.Element {
+	first, next := s.idleInstance.Front, (*list.Element).Next
+	if s.cfg().IdleQueueOrder == config.IdleQueueFIFO {
+		first, next = s.idleInstance.Back, (*list.Element).Prev
+	}
+	var candidate *list.Element
+	for element := first(); element != nil; element = next(element) {
+		instance := element.Value.(*model2.Instance)
+		if !matchesTags(instance, required) {
+			continue
+		}
+		if hint.isZero() || hint.matches(instance) && (hint.PreferInstanceId == "" || instance.Id == hint.PreferInstanceId) {
+			return element
+		}
+		if candidate == nil {
+			candidate = element
+		}
+	}
+	return candidate
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func TestMatchesTags(t *testing.T) {
+	instance := &model2.Instance{Tags: map[string]string{"gpu": "a100", "az": "cn-1"}}
+	cases := []struct {
+		name     string
+		instance *model2.Instance
+		required map[string]string
+		want     bool
+	}{
+		{"nil required", instance, nil, true},
+		{"empty required", instance, map[string]string{}, true},
+		{"empty required untagged", &model2.Instance{}, map[string]string{}, true},
+		{"exact", instance, map[string]string{"gpu": "a100", "az": "cn-1"}, true},
+		{"partial", instance, map[string]string{"gpu": "a100"}, true},
+		{"value differs", instance, map[string]string{"gpu": "t4"}, false},
+		{"missing key", instance, map[string]string{"gpu": "a100", "disk": "ssd"}, false},
+		{"empty value is not missing", &model2.Instance{Tags: map[string]string{"gpu": ""}}, map[string]string{"gpu": ""}, true},
+		{"untagged instance", &model2.Instance{}, map[string]string{"gpu": ""}, false},
+	}
+	for _, c := range cases {
+		if got := matchesTags(c.instance, c.required); got != c.want {
+			t.Fatalf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func taggedAssignRequest(requestId string, tags map[string]string) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.RequiredTags = tags
+	return request
+}
+
+// 带标签的请求只使用标签满足的空闲实例，没有时按所需标签创建新实例；不带标签的请求可以使用任意实例
+func TestAssignRequiredTags(t *testing.T) {
+	platform := newFakeTagPlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	gpu := map[string]string{"gpu": "a100", "az": "cn-1"}
+
+	tagged := assignAll(t, s, taggedAssignRequest("gpu", gpu))[0]
+	idleInOrder(t, s, tagged)
+	// 部分匹配即可复用
+	reused := assignAll(t, s, taggedAssignRequest("partial", map[string]string{"gpu": "a100"}))[0]
+	if reused.Assigment.InstanceId != tagged.Assigment.InstanceId || atomic.LoadInt64(&platform.creates) != 1 {
+		t.Fatalf("partial tags should reuse the tagged instance, creates %d", atomic.LoadInt64(&platform.creates))
+	}
+	idleInOrder(t, s, reused)
+
+	other := assignAll(t, s, taggedAssignRequest("t4", map[string]string{"gpu": "t4"}))[0]
+	if other.Assigment.InstanceId == tagged.Assigment.InstanceId || atomic.LoadInt64(&platform.creates) != 2 {
+		t.Fatalf("mismatched tags should create a new instance, creates %d", atomic.LoadInt64(&platform.creates))
+	}
+	s.instancesMu.RLock()
+	created := s.instances[other.Assigment.InstanceId].Tags["gpu"]
+	s.instancesMu.RUnlock()
+	if created != "t4" {
+		t.Fatalf("new instance should be initialized with the required tags, got gpu=%q", created)
+	}
+
+	plain := assignAll(t, s, assignRequest("plain"))[0]
+	if plain.Assigment.InstanceId != tagged.Assigment.InstanceId || atomic.LoadInt64(&platform.creates) != 2 {
+		t.Fatalf("request without tags should use any idle instance, creates %d", atomic.LoadInt64(&platform.creates))
+	}
+}
+
+// 等待中的带标签请求不会被标签不满足的实例满足
+func TestTaggedWaiterSkipsMismatchedInstance(t *testing.T) {
+	platform := newFakeTagPlatform()
+	platform.createDelay = 100 * time.Millisecond
+	s := newTestSimple(t, testConfig(), platform)
+	plain := assignAll(t, s, assignRequest("plain"))[0]
+
+	replies := make(chan *pb.AssignReply, 1)
+	go func() {
+		reply, err := s.Assign(context.Background(), taggedAssignRequest("gpu", map[string]string{"gpu": "a100"}))
+		if err != nil {
+			t.Errorf("tagged assign: %v", err)
+		}
+		replies <- reply
+	}()
+	waitFor(t, time.Second, func() bool { return queueLen(s) == 1 })
+	if _, err := s.Idle(context.Background(), idleRequest(plain, false)); err != nil {
+		t.Fatalf("idle: %v", err)
+	}
+	waitFor(t, time.Second, func() bool { return len(idleIds(s)) == 1 })
+	reply := <-replies
+	if reply == nil || reply.Assigment.InstanceId == plain.Assigment.InstanceId {
+		t.Fatalf("tagged waiter was served by an untagged instance")
+	}
+}
+
+// 平台不支持按标签初始化时带标签的请求失败，不创建 slot
+func TestRequiredTagsUnsupported(t *testing.T) {
+	platform := newFakePlatform()
+	s := newTestSimple(t, testConfig(), platform)
+	if _, err := s.Assign(context.Background(), taggedAssignRequest("gpu", map[string]string{"gpu": "a100"})); err == nil {
+		t.Fatalf("assign with tags should fail without TagInitializer")
+	}
+	if creates := atomic.LoadInt64(&platform.creates); creates != 0 {
+		t.Fatalf("got %d creates, want none", creates)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// 没有通过 WithTracer 注入时使用的 tracer，不记录任何 span
+var noopTracer trace.Tracer = noop.NewTracerProvider().Tracer("github.com/AliyunContainerService/scaler")
+
+// WithTracer 使用 tracer 记录 Assign 和创建实例的 span，tracer 为 nil 时不记录
+func WithTracer(tracer trace.Tracer) Option {
+	return func(s *Simple) {
+		if tracer != nil {
+			s.tracer = tracer
+		}
+	}
+}
+
+// 与 phaseContext 相同，但带上 parent 中的 span，平台调用和其中的 span 挂在调用方的 trace 下
+// 平台调用的生命周期仍然跟随 scaler，不受 parent 取消的影响
+func (s *Simple) tracedPhaseContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := s.phaseContext(timeout)
+	return trace.ContextWithSpan(ctx, trace.SpanFromContext(parent)), cancel
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedSimple(t *testing.T) (*Simple, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	s := newTestSimple(t, testConfig(), newFakePlatform(), WithTracer(provider.Tracer("test")))
+	return s, recorder
+}
+
+// 按名字返回已结束的 span，同名的 span 只保留最后一个
+func endedSpans(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return spans
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == attribute.Key(key) {
+			return attr.Value.AsString()
+		}
+	}
+	return ""
+}
+
+// 冷启动的 Assign 记录等待和创建实例的子 span，平台调用挂在 createInstance 下，整条链路属于调用方的 trace
+func TestAssignSpans(t *testing.T) {
+	s, recorder := newTracedSimple(t)
+	ctx, parent := s.tracer.Start(context.Background(), "caller")
+	if _, err := s.Assign(ctx, assignRequest("r1")); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	parent.End()
+
+	names := []string{"caller", "scaler.Assign", "scaler.waitIdleInstance", "scaler.createInstance", "platform.CreateSlot", "platform.Init"}
+	// createInstance 在后台 goroutine 中结束，可能晚于 Assign 返回
+	waitFor(t, time.Second, func() bool { return len(endedSpans(recorder)) >= len(names) })
+	spans := endedSpans(recorder)
+	for _, name := range names {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("span %s is not recorded", name)
+		}
+	}
+	parents := map[string]string{
+		"scaler.Assign":           "caller",
+		"scaler.waitIdleInstance": "scaler.Assign",
+		"scaler.createInstance":   "scaler.Assign",
+		"platform.CreateSlot":     "scaler.createInstance",
+		"platform.Init":           "scaler.createInstance",
+	}
+	for child, want := range parents {
+		span := spans[child]
+		if span.Parent().SpanID() != spans[want].SpanContext().SpanID() {
+			t.Fatalf("span %s is not a child of %s", child, want)
+		}
+		if span.SpanContext().TraceID() != spans["caller"].SpanContext().TraceID() {
+			t.Fatalf("span %s is not in the caller's trace", child)
+		}
+	}
+	for _, name := range []string{"scaler.Assign", "scaler.createInstance"} {
+		if got := spanAttribute(spans[name], "requestId"); got != "r1" {
+			t.Fatalf("span %s has requestId %q, want r1", name, got)
+		}
+		if got := spanAttribute(spans[name], "metaKey"); got != testMeta().Key {
+			t.Fatalf("span %s has metaKey %q, want %s", name, got, testMeta().Key)
+		}
+	}
+	if spanAttribute(spans["platform.Init"], "slotId") == "" {
+		t.Fatalf("platform.Init span has no slotId")
+	}
+}
+
+// Idle 记录带有 requestId 和 instanceId 的 span
+func TestIdleSpan(t *testing.T) {
+	s, recorder := newTracedSimple(t)
+	reply := assignAll(t, s, assignRequest("r1"))[0]
+	idleInOrder(t, s, reply)
+	span, ok := endedSpans(recorder)["scaler.Idle"]
+	if !ok {
+		t.Fatalf("span scaler.Idle is not recorded")
+	}
+	if spanAttribute(span, "requestId") != "r1" || spanAttribute(span, "instanceId") != reply.Assigment.InstanceId {
+		t.Fatalf("unexpected attributes %v", span.Attributes())
+	}
+}
+
+// 没有注入 tracer 时使用不记录的 tracer，调用方 ctx 中的 span 保持不变
+func TestDefaultTracerIsNoop(t *testing.T) {
+	s := newTestSimple(t, testConfig(), newFakePlatform())
+	if s.tracer != noopTracer {
+		t.Fatalf("default tracer is not the no-op tracer")
+	}
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "caller")
+	if _, err := s.Assign(ctx, assignRequest("r1")); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	parent.End()
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Name() != "caller" {
+		t.Fatalf("scaler recorded spans without a tracer: %d spans", len(spans))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 请求补齐预热池，已有待处理的信号时直接返回
+func (s *Simple) signalWarmPool() {
+	if s.warmPoolCh == nil {
+		return
+	}
+	select {
+	case s.warmPoolCh <- struct{}{}:
+	default:
+	}
+}
+
+// 创建后立即预热，之后在空闲实例被分配或每个 GcInterval 补齐一次，直到 scaler 关闭
+func (s *Simple) warmPoolLoop() {
+	ticker := time.NewTicker(s.cfg().GcInterval)
+	defer ticker.Stop()
+	for {
+		s.refillWarmPool()
+		select {
+		case <-s.done:
+			return
+		case <-s.warmPoolCh:
+		case <-ticker.C:
+		}
+	}
+}
+
+// 把空闲和创建中的实例数补齐到 WarmPoolSize，不等待创建完成
+func (s *Simple) refillWarmPool() {
+	if s.IsDraining() {
+		return
+	}
+	s.idleMu.Lock()
+	idle := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	n := s.reserveCreations(s.cfg().WarmPoolSize - idle - int(atomic.LoadInt64(&s.creatingNum)))
+	if n <= 0 {
+		return
+	}
+	s.logger.Info("refill warm instances", "metaKey", s.metaData.Key, "count", n)
+	started := s.goBackground(func() {
+		if err := s.createInstances(context.Background(), n); err != nil {
+			s.logger.Error("refill warm instances failed", "metaKey", s.metaData.Key, "error", err)
+		}
+	})
+	if !started {
+		s.releaseCreations(n)
+	}
+}
+
+// gc 回收过期实例时至少保留的空闲实例数
+func (s *Simple) idleFloor() int {
+	if s.IsDraining() {
+		return 0
+	}
+	if s.cfg().MinIdleInstances > s.cfg().WarmPoolSize {
+		return s.cfg().MinIdleInstances
+	}
+	return s.cfg().WarmPoolSize
+}
+
+// Assign 成功后空闲和创建中的实例都为 0 时补一个备用实例，同一时间只有一个备用实例在创建
+func (s *Simple) refillHotStandby() {
+	if !s.cfg().HotStandby || s.IsDraining() {
+		return
+	}
+	s.idleMu.Lock()
+	idle := s.idleInstance.Len()
+	s.idleMu.Unlock()
+	if idle+int(atomic.LoadInt64(&s.creatingNum)) > 0 {
+		return
+	}
+	if maxIdle := s.cfg().MaxIdleInstances; maxIdle > 0 && idle >= maxIdle {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.hotStandbyCreating, 0, 1) {
+		return
+	}
+	if s.reserveCreations(1) <= 0 {
+		atomic.StoreInt32(&s.hotStandbyCreating, 0)
+		return
+	}
+	s.logger.Info("create hot standby instance", "metaKey", s.metaData.Key)
+	started := s.goBackground(func() {
+		defer atomic.StoreInt32(&s.hotStandbyCreating, 0)
+		if err := s.createInstance(context.Background(), &s.metaData.Meta, uuid.NewString(), nil); err != nil {
+			s.logger.Error("create hot standby instance failed", "metaKey", s.metaData.Key, "error", err)
+		}
+	})
+	if !started {
+		s.releaseCreations(1)
+		atomic.StoreInt32(&s.hotStandbyCreating, 0)
+	}
+}
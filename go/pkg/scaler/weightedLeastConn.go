@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"container/list"
+	"sync/atomic"
+
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+)
+
+// 实例的权重，与分配的内存成正比，内存未知时为 1
+func instanceWeight(memoryInMb uint64) float64 {
+	if memoryInMb == 0 {
+		return 1
+	}
+	return float64(memoryInMb)
+}
+
+// 加权最少连接的分数，正在处理的请求数除以权重，越小越优先
+func weightedConnScore(instance *model2.Instance) float64 {
+	weight := instance.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	return float64(atomic.LoadInt32(&instance.UsedSlots)) / weight
+}
+
+// 选择分数最小的空闲实例，分数相同时选择权重小的实例，把内存大的实例留给更重的负载，调用方需持有 idleMu
+// 每次分配都重新计算分数，相当于按分数重新排序后取队首
+func (s *Simple) selectIdleByWeightedConn() *list.Element {
+	var best *list.Element
+	var bestScore float64
+	for element := s.idleInstance.Front(); element != nil; element = element.Next() {
+		instance := element.Value.(*model2.Instance)
+		score := weightedConnScore(instance)
+		if best == nil || score < bestScore || score == bestScore && instance.Weight < best.Value.(*model2.Instance).Weight {
+			best, bestScore = element, score
+		}
+	}
+	return best
+}
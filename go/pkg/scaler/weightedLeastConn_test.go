@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Alibaba Cloud Serverless Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaler
+
+import (
+	"testing"
+
+	"github.com/AliyunContainerService/scaler/go/pkg/config"
+	model2 "github.com/AliyunContainerService/scaler/go/pkg/model"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
+)
+
+func TestWeightedConnScore(t *testing.T) {
+	cases := []struct {
+		name   string
+		used   int32
+		weight float64
+		want   float64
+	}{
+		{"idle", 0, 128, 0},
+		{"busy", 2, 128, 2.0 / 128},
+		{"unknown weight", 3, 0, 3},
+		{"negative weight", 1, -5, 1},
+	}
+	for _, c := range cases {
+		instance := &model2.Instance{UsedSlots: c.used, Weight: c.weight}
+		if got := weightedConnScore(instance); got != c.want {
+			t.Fatalf("%s: got score %v, want %v", c.name, got, c.want)
+		}
+	}
+	if instanceWeight(0) != 1 || instanceWeight(512) != 512 {
+		t.Fatalf("weight should follow the memory and default to 1")
+	}
+}
+
+func memoryAssignRequest(requestId string, memoryInMb uint64) *pb.AssignRequest {
+	request := assignRequest(requestId)
+	request.MetaData.MemoryInMb = memoryInMb
+	return request
+}
+
+// 两个实例都空闲时优先分配内存小的实例，与空闲队列中的顺序无关
+func TestWeightedLeastConnPrefersSmallInstance(t *testing.T) {
+	cfg := testConfig()
+	cfg.IdleQueueOrder = config.IdleQueueWeightedLeastConn
+	s := newTestSimple(t, cfg, newFakePlatform())
+	replies := assignAll(t, s, memoryAssignRequest("small", 128), memoryAssignRequest("large", 1024))
+	small, large := replies[0].Assigment.InstanceId, replies[1].Assigment.InstanceId
+	// 两种归还顺序下大实例分别位于队首和队尾
+	for _, largeFirst := range []bool{false, true} {
+		if largeFirst {
+			idleInOrder(t, s, replies[0], replies[1])
+		} else {
+			idleInOrder(t, s, replies[1], replies[0])
+		}
+		picked := assignAll(t, s, assignRequest("next"), assignRequest("other"))
+		if picked[0].Assigment.InstanceId != small || picked[1].Assigment.InstanceId != large {
+			t.Fatalf("large instance first %v: got %s then %s, want the small instance %s first", largeFirst, picked[0].Assigment.InstanceId, picked[1].Assigment.InstanceId, small)
+		}
+		replies = []*pb.AssignReply{picked[0], picked[1]}
+	}
+}
+
+// 多槽位实例按正在处理的请求数除以权重选择，内存大的实例可以承担更多请求
+func TestWeightedLeastConnBalancesByWeight(t *testing.T) {
+	cfg := testConfig()
+	cfg.IdleQueueOrder = config.IdleQueueWeightedLeastConn
+	cfg.InstanceCapacity = 8
+	s := newTestSimple(t, cfg, newFakePlatform())
+	// 第二个请求要求新建实例，否则会使用第一个实例的空闲槽位
+	cold := memoryAssignRequest("large", 512)
+	cold.PoolPreference = pb.PoolPreference_Cold
+	replies := assignAll(t, s, memoryAssignRequest("small", 128), cold)
+	small, large := replies[0].Assigment.InstanceId, replies[1].Assigment.InstanceId
+	counts := map[string]int{small: 1, large: 1}
+	for i := 0; i < 8; i++ {
+		reply := assignAll(t, s, assignRequest("r"))[0]
+		counts[reply.Assigment.InstanceId]++
+	}
+	// 512MB 的实例权重是 128MB 的 4 倍，10 个请求按 2:8 分配
+	if counts[small] != 2 || counts[large] != 8 {
+		t.Fatalf("got %d requests on the small instance and %d on the large one, want 2 and 8", counts[small], counts[large])
+	}
+}
@@ -24,7 +24,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	pb "github.com/AliyunContainerService/scaler/proto"
+	pb "github.com/AliyunContainerService/scaler/go/proto"
 )
 
 type Server struct {
@@ -42,18 +42,18 @@ func (s *Server) Assign(ctx context.Context, request *pb.AssignRequest) (*pb.Ass
 	if request.MetaData == nil {
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("app meta is nil"))
 	}
-	metaData := &model.Meta{
-		Meta: pb.Meta{
-			Key:           request.MetaData.Key,
-			Runtime:       request.MetaData.Runtime,
-			TimeoutInSecs: request.MetaData.TimeoutInSecs,
-			MemoryInMb:    request.MetaData.MemoryInMb,
-		},
-	}
-	scheduler := s.mgr.GetOrCreate(metaData)
+	scheduler := s.mgr.GetOrCreate(model.NewMeta(request.MetaData))
 	return scheduler.Assign(ctx, request)
 }
 
+func (s *Server) BatchAssign(ctx context.Context, request *pb.BatchAssignRequest) (*pb.BatchAssignReply, error) {
+	if request.MetaData == nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("app meta is nil"))
+	}
+	scheduler := s.mgr.GetOrCreate(model.NewMeta(request.MetaData))
+	return scheduler.BatchAssign(ctx, request)
+}
+
 func (s *Server) Idle(ctx context.Context, request *pb.IdleRequest) (*pb.IdleReply, error) {
 	if request.Assigment == nil {
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("assignment is nil"))
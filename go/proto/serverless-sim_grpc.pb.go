@@ -20,6 +20,8 @@ const _ = grpc.SupportPackageIsVersion7
 type ScalerClient interface {
 	Assign(ctx context.Context, in *AssignRequest, opts ...grpc.CallOption) (*AssignReply, error)
 	Idle(ctx context.Context, in *IdleRequest, opts ...grpc.CallOption) (*IdleReply, error)
+	// acquire count instances of the same app at once, all or none
+	BatchAssign(ctx context.Context, in *BatchAssignRequest, opts ...grpc.CallOption) (*BatchAssignReply, error)
 }
 
 type scalerClient struct {
@@ -48,12 +50,23 @@ func (c *scalerClient) Idle(ctx context.Context, in *IdleRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *scalerClient) BatchAssign(ctx context.Context, in *BatchAssignRequest, opts ...grpc.CallOption) (*BatchAssignReply, error) {
+	out := new(BatchAssignReply)
+	err := c.cc.Invoke(ctx, "/serverless.simulator.Scaler/BatchAssign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScalerServer is the server API for Scaler service.
 // All implementations must embed UnimplementedScalerServer
 // for forward compatibility
 type ScalerServer interface {
 	Assign(context.Context, *AssignRequest) (*AssignReply, error)
 	Idle(context.Context, *IdleRequest) (*IdleReply, error)
+	// acquire count instances of the same app at once, all or none
+	BatchAssign(context.Context, *BatchAssignRequest) (*BatchAssignReply, error)
 	mustEmbedUnimplementedScalerServer()
 }
 
@@ -67,6 +80,9 @@ func (UnimplementedScalerServer) Assign(context.Context, *AssignRequest) (*Assig
 func (UnimplementedScalerServer) Idle(context.Context, *IdleRequest) (*IdleReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Idle not implemented")
 }
+func (UnimplementedScalerServer) BatchAssign(context.Context, *BatchAssignRequest) (*BatchAssignReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAssign not implemented")
+}
 func (UnimplementedScalerServer) mustEmbedUnimplementedScalerServer() {}
 
 // UnsafeScalerServer may be embedded to opt out of forward compatibility for this service.
@@ -116,6 +132,24 @@ func _Scaler_Idle_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Scaler_BatchAssign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAssignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScalerServer).BatchAssign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serverless.simulator.Scaler/BatchAssign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScalerServer).BatchAssign(ctx, req.(*BatchAssignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Scaler_ServiceDesc is the grpc.ServiceDesc for Scaler service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -131,6 +165,10 @@ var Scaler_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Idle",
 			Handler:    _Scaler_Idle_Handler,
 		},
+		{
+			MethodName: "BatchAssign",
+			Handler:    _Scaler_BatchAssign_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "serverless-sim.proto",
@@ -143,6 +181,12 @@ type PlatformClient interface {
 	// Slot
 	CreateSlot(ctx context.Context, in *CreateSlotRequest, opts ...grpc.CallOption) (*CreateSlotReply, error)
 	DestroySlot(ctx context.Context, in *DestroySlotRequest, opts ...grpc.CallOption) (*DestroySlotReply, error)
+	// create count slots with the same resource config in one call
+	CreateSlotGroup(ctx context.Context, in *CreateSlotGroupRequest, opts ...grpc.CallOption) (*CreateSlotGroupReply, error)
+	// release the application state on a slot so that it can be initialized again
+	ResetSlot(ctx context.Context, in *ResetSlotRequest, opts ...grpc.CallOption) (*ResetSlotReply, error)
+	// re-run initialization of the instance on a slot whose sandbox is still healthy
+	ResetInstance(ctx context.Context, in *ResetInstanceRequest, opts ...grpc.CallOption) (*ResetInstanceReply, error)
 	// Init
 	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitReply, error)
 }
@@ -173,6 +217,33 @@ func (c *platformClient) DestroySlot(ctx context.Context, in *DestroySlotRequest
 	return out, nil
 }
 
+func (c *platformClient) CreateSlotGroup(ctx context.Context, in *CreateSlotGroupRequest, opts ...grpc.CallOption) (*CreateSlotGroupReply, error) {
+	out := new(CreateSlotGroupReply)
+	err := c.cc.Invoke(ctx, "/serverless.simulator.Platform/CreateSlotGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) ResetSlot(ctx context.Context, in *ResetSlotRequest, opts ...grpc.CallOption) (*ResetSlotReply, error) {
+	out := new(ResetSlotReply)
+	err := c.cc.Invoke(ctx, "/serverless.simulator.Platform/ResetSlot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) ResetInstance(ctx context.Context, in *ResetInstanceRequest, opts ...grpc.CallOption) (*ResetInstanceReply, error) {
+	out := new(ResetInstanceReply)
+	err := c.cc.Invoke(ctx, "/serverless.simulator.Platform/ResetInstance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *platformClient) Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitReply, error) {
 	out := new(InitReply)
 	err := c.cc.Invoke(ctx, "/serverless.simulator.Platform/Init", in, out, opts...)
@@ -189,6 +260,12 @@ type PlatformServer interface {
 	// Slot
 	CreateSlot(context.Context, *CreateSlotRequest) (*CreateSlotReply, error)
 	DestroySlot(context.Context, *DestroySlotRequest) (*DestroySlotReply, error)
+	// create count slots with the same resource config in one call
+	CreateSlotGroup(context.Context, *CreateSlotGroupRequest) (*CreateSlotGroupReply, error)
+	// release the application state on a slot so that it can be initialized again
+	ResetSlot(context.Context, *ResetSlotRequest) (*ResetSlotReply, error)
+	// re-run initialization of the instance on a slot whose sandbox is still healthy
+	ResetInstance(context.Context, *ResetInstanceRequest) (*ResetInstanceReply, error)
 	// Init
 	Init(context.Context, *InitRequest) (*InitReply, error)
 	mustEmbedUnimplementedPlatformServer()
@@ -204,6 +281,15 @@ func (UnimplementedPlatformServer) CreateSlot(context.Context, *CreateSlotReques
 func (UnimplementedPlatformServer) DestroySlot(context.Context, *DestroySlotRequest) (*DestroySlotReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroySlot not implemented")
 }
+func (UnimplementedPlatformServer) CreateSlotGroup(context.Context, *CreateSlotGroupRequest) (*CreateSlotGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSlotGroup not implemented")
+}
+func (UnimplementedPlatformServer) ResetSlot(context.Context, *ResetSlotRequest) (*ResetSlotReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetSlot not implemented")
+}
+func (UnimplementedPlatformServer) ResetInstance(context.Context, *ResetInstanceRequest) (*ResetInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetInstance not implemented")
+}
 func (UnimplementedPlatformServer) Init(context.Context, *InitRequest) (*InitReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Init not implemented")
 }
@@ -256,6 +342,60 @@ func _Platform_DestroySlot_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Platform_CreateSlotGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSlotGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).CreateSlotGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serverless.simulator.Platform/CreateSlotGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).CreateSlotGroup(ctx, req.(*CreateSlotGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_ResetSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).ResetSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serverless.simulator.Platform/ResetSlot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).ResetSlot(ctx, req.(*ResetSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_ResetInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).ResetInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serverless.simulator.Platform/ResetInstance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).ResetInstance(ctx, req.(*ResetInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Platform_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InitRequest)
 	if err := dec(in); err != nil {
@@ -289,6 +429,18 @@ var Platform_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DestroySlot",
 			Handler:    _Platform_DestroySlot_Handler,
 		},
+		{
+			MethodName: "CreateSlotGroup",
+			Handler:    _Platform_CreateSlotGroup_Handler,
+		},
+		{
+			MethodName: "ResetSlot",
+			Handler:    _Platform_ResetSlot_Handler,
+		},
+		{
+			MethodName: "ResetInstance",
+			Handler:    _Platform_ResetInstance_Handler,
+		},
 		{
 			MethodName: "Init",
 			Handler:    _Platform_Init_Handler,